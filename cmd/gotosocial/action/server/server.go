@@ -339,11 +339,14 @@ var Start action.GTSAction = func(ctx context.Context) error {
 	}
 
 	// create and start the proxy using the other services we've created so far
-	rssTooter := rss.NewRssTooter(ctx, state, mediaManager, transportController, typeConverter, visFilter)
+	rssTooter := rss.NewRssTooter(ctx, state, mediaManager, transportController, typeConverter, visFilter, emailSender)
 	if err := rssTooter.Start(); err != nil {
 		return fmt.Errorf("error starting rssTooter: %s", err)
 	}
 
+	// Let pasted website and feed urls be resolved from the search.
+	processor.Search().SetFeedResolver(rssTooter)
+
 	// Add any extra CSP URIs from config.
 	cspExtraURIs = append(cspExtraURIs, config.GetAdvancedCSPExtraURIs()...)
 
@@ -379,7 +382,7 @@ var Start action.GTSAction = func(ctx context.Context) error {
 
 	var (
 		authModule        = api.NewAuth(dbService, processor, idp, routerSession, sessionName) // auth/oauth paths
-		clientModule      = api.NewClient(state, processor)                                    // api client endpoints
+		clientModule      = api.NewClient(state, processor, rssTooter)                         // api client endpoints
 		metricsModule     = api.NewMetrics()                                                   // Metrics endpoints
 		healthModule      = api.NewHealth(dbService.Ready)                                     // Health check endpoints
 		fileserverModule  = api.NewFileserver(processor)                                       // fileserver endpoints
@@ -230,11 +230,14 @@ var Start action.GTSAction = func(ctx context.Context) error {
 	}...)
 
 	// create and start the proxy using the other services we've created so far
-	rssTooter := rss.NewRssTooter(ctx, state, mediaManager, transportController, typeConverter, filter)
+	rssTooter := rss.NewRssTooter(ctx, state, mediaManager, transportController, typeConverter, filter, emailSender)
 	if err := rssTooter.Start(); err != nil {
 		return fmt.Errorf("error starting napper: %s", err)
 	}
 
+	// Let pasted website and feed urls be resolved from the search.
+	processor.Search().SetFeedResolver(rssTooter)
+
 	// Instantiate Content-Security-Policy
 	// middleware, with extra URIs.
 	cspExtraURIs := make([]string, 0)
@@ -288,7 +291,7 @@ var Start action.GTSAction = func(ctx context.Context) error {
 
 	var (
 		authModule        = api.NewAuth(state.DB, processor, idp, routerSession, sessionName) // auth/oauth paths
-		clientModule      = api.NewClient(state, processor)                                   // api client endpoints
+		clientModule      = api.NewClient(state, processor, rssTooter)                        // api client endpoints
 		metricsModule     = api.NewMetrics()                                                  // Metrics endpoints
 		healthModule      = api.NewHealth(state.DB.Ready)                                     // Health check endpoints
 		fileserverModule  = api.NewFileserver(processor)                                      // fileserver endpoints
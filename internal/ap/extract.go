@@ -496,7 +496,7 @@ func ExtractFields(i WithAttachment) []*gtsmodel.Field {
 // ExtractURL extracts the first URI it can find from the
 // given WithURL interface, or an error if no URL was set.
 // The ID of a type will not work, this function wants a URI
-// specifically.
+// specifically, either as an IRI or as the href of a Link.
 func ExtractURL(i WithURL) (*url.URL, error) {
 	urlProp := i.GetActivityStreamsUrl()
 	if urlProp == nil {
@@ -504,12 +504,18 @@ func ExtractURL(i WithURL) (*url.URL, error) {
 	}
 
 	for iter := urlProp.Begin(); iter != urlProp.End(); iter = iter.Next() {
-		if !iter.IsIRI() {
-			continue
+		if iter.IsIRI() {
+			// Found it.
+			return iter.GetIRI(), nil
 		}
 
-		// Found it.
-		return iter.GetIRI(), nil
+		if iter.IsActivityStreamsLink() {
+			hrefProp := iter.GetActivityStreamsLink().GetActivityStreamsHref()
+			if hrefProp != nil && hrefProp.IsIRI() {
+				// Found it as a link.
+				return hrefProp.GetIRI(), nil
+			}
+		}
 	}
 
 	return nil, gtserror.New("no valid URL property found")
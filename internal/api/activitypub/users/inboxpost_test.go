@@ -177,7 +177,7 @@ func (suite *InboxPostTestSuite) newUndo(
 	return undo
 }
 
-func (suite *InboxPostTestSuite) newUpdatePerson(person vocab.ActivityStreamsPerson, cc string, updateIRI string) vocab.ActivityStreamsUpdate {
+func (suite *InboxPostTestSuite) newUpdatePerson(person ap.Accountable, cc string, updateIRI string) vocab.ActivityStreamsUpdate {
 	// create an update
 	update := streams.NewActivityStreamsUpdate()
 
@@ -188,7 +188,9 @@ func (suite *InboxPostTestSuite) newUpdatePerson(person vocab.ActivityStreamsPer
 
 	// Set the person as the 'object' property.
 	updateObject := streams.NewActivityStreamsObjectProperty()
-	updateObject.AppendActivityStreamsPerson(person)
+	if err := updateObject.AppendType(person); err != nil {
+		suite.FailNow(err.Error())
+	}
 	update.SetActivityStreamsObject(updateObject)
 
 	// Set the To of the update as public
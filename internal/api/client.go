@@ -30,6 +30,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/customemojis"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/favourites"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/featuredtags"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/feeds"
 	filtersV1 "github.com/superseriousbusiness/gotosocial/internal/api/client/filters/v1"
 	filtersV2 "github.com/superseriousbusiness/gotosocial/internal/api/client/filters/v2"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/followrequests"
@@ -41,16 +42,19 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/notifications"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/polls"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/preferences"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/reader"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/reports"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/search"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/statuses"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/streaming"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/timelinefeeds"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/timelines"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/user"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/middleware"
 	"github.com/superseriousbusiness/gotosocial/internal/processing"
 	"github.com/superseriousbusiness/gotosocial/internal/router"
+	"github.com/superseriousbusiness/gotosocial/internal/rss"
 	"github.com/superseriousbusiness/gotosocial/internal/state"
 )
 
@@ -67,6 +71,7 @@ type Client struct {
 	customEmojis   *customemojis.Module   // api/v1/custom_emojis
 	favourites     *favourites.Module     // api/v1/favourites
 	featuredTags   *featuredtags.Module   // api/v1/featured_tags
+	feeds          *feeds.Module          // api/v1/feeds
 	filtersV1      *filtersV1.Module      // api/v1/filters
 	filtersV2      *filtersV2.Module      // api/v2/filters
 	followRequests *followrequests.Module // api/v1/follow_requests
@@ -78,10 +83,12 @@ type Client struct {
 	notifications  *notifications.Module  // api/v1/notifications
 	polls          *polls.Module          // api/v1/polls
 	preferences    *preferences.Module    // api/v1/preferences
+	reader         *reader.Module         // api/v1/app_passwords, api/greader, api/fever
 	reports        *reports.Module        // api/v1/reports
 	search         *search.Module         // api/v1/search, api/v2/search
 	statuses       *statuses.Module       // api/v1/statuses
 	streaming      *streaming.Module      // api/v1/streaming
+	timelineFeeds  *timelinefeeds.Module  // api/v1/timeline_feeds
 	timelines      *timelines.Module      // api/v1/timelines
 	user           *user.Module           // api/v1/user
 }
@@ -112,6 +119,7 @@ func (c *Client) Route(r *router.Router, m ...gin.HandlerFunc) {
 	c.customEmojis.Route(h)
 	c.favourites.Route(h)
 	c.featuredTags.Route(h)
+	c.feeds.Route(h)
 	c.filtersV1.Route(h)
 	c.filtersV2.Route(h)
 	c.followRequests.Route(h)
@@ -123,15 +131,17 @@ func (c *Client) Route(r *router.Router, m ...gin.HandlerFunc) {
 	c.notifications.Route(h)
 	c.polls.Route(h)
 	c.preferences.Route(h)
+	c.reader.Route(h)
 	c.reports.Route(h)
 	c.search.Route(h)
 	c.statuses.Route(h)
 	c.streaming.Route(h)
+	c.timelineFeeds.Route(h)
 	c.timelines.Route(h)
 	c.user.Route(h)
 }
 
-func NewClient(state *state.State, p *processing.Processor) *Client {
+func NewClient(state *state.State, p *processing.Processor, rssTooter rss.RssTooter) *Client {
 	return &Client{
 		processor: p,
 		db:        state.DB,
@@ -145,6 +155,7 @@ func NewClient(state *state.State, p *processing.Processor) *Client {
 		customEmojis:   customemojis.New(p),
 		favourites:     favourites.New(p),
 		featuredTags:   featuredtags.New(p),
+		feeds:          feeds.New(rssTooter, p),
 		filtersV1:      filtersV1.New(p),
 		filtersV2:      filtersV2.New(p),
 		followRequests: followrequests.New(p),
@@ -156,10 +167,12 @@ func NewClient(state *state.State, p *processing.Processor) *Client {
 		notifications:  notifications.New(p),
 		polls:          polls.New(p),
 		preferences:    preferences.New(p),
+		reader:         reader.New(rssTooter, p),
 		reports:        reports.New(p),
 		search:         search.New(p),
 		statuses:       statuses.New(p),
 		streaming:      streaming.New(p, time.Second*30, 4096),
+		timelineFeeds:  timelinefeeds.New(rssTooter, p),
 		timelines:      timelines.New(p),
 		user:           user.New(p),
 	}
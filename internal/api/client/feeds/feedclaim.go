@@ -0,0 +1,150 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package feeds
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apiutil "github.com/superseriousbusiness/gotosocial/internal/api/util"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// FeedClaimPOSTHandler swagger:operation POST /api/v1/feeds/{id}/claim feedClaim
+//
+// Claim the ownership of a feed account.
+//
+// The returned token must be published on the website of the feed, as the content of a
+// `<meta name="rsstooter-claim">` tag, or in the channel (RSS) or feed (Atom) element of
+// the feed, as the href of a `<link rel="rsstooter-claim">` or the content of a `<meta
+// name="rsstooter-claim">` element. The claim is verified on a next poll of the feed, after
+// which the claimant acts on behalf of the feed account. Claiming again returns the same
+// token, or a new one once the claim expired, 7 days after its creation without being verified.
+//
+//	---
+//	tags:
+//	- feeds
+//
+//	produces:
+//	- application/json
+//
+//	parameters:
+//	-
+//		name: id
+//		type: string
+//		description: ID of the feed account.
+//		in: path
+//		required: true
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- write:accounts
+//
+//	responses:
+//		'200':
+//			description: The claim on the feed account.
+//			schema:
+//				"$ref": "#/definitions/rssFeedClaim"
+//		'400':
+//			description: bad request
+//		'401':
+//			description: unauthorized
+//		'404':
+//			description: not found
+//		'406':
+//			description: not acceptable
+//		'422':
+//			description: the requester has too many pending claims
+//		'500':
+//			description: internal server error
+func (m *Module) FeedClaimPOSTHandler(c *gin.Context) {
+	m.feedClaimHandler(c, m.rssTooter.ClaimFeed)
+}
+
+// FeedClaimGETHandler swagger:operation GET /api/v1/feeds/{id}/claim feedClaimGet
+//
+// Get the claim of the requester on a feed account, to know whether it's verified.
+//
+//	---
+//	tags:
+//	- feeds
+//
+//	produces:
+//	- application/json
+//
+//	parameters:
+//	-
+//		name: id
+//		type: string
+//		description: ID of the feed account.
+//		in: path
+//		required: true
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- read:accounts
+//
+//	responses:
+//		'200':
+//			description: The claim on the feed account.
+//			schema:
+//				"$ref": "#/definitions/rssFeedClaim"
+//		'400':
+//			description: bad request
+//		'401':
+//			description: unauthorized
+//		'404':
+//			description: not found
+//		'406':
+//			description: not acceptable
+//		'500':
+//			description: internal server error
+func (m *Module) FeedClaimGETHandler(c *gin.Context) {
+	m.feedClaimHandler(c, m.rssTooter.GetFeedClaim)
+}
+
+// feedClaimHandler handles a request on the claim of a feed account with action.
+func (m *Module) feedClaimHandler(c *gin.Context, action claimAction) {
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorUnauthorized(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	if _, err := apiutil.NegotiateAccept(c, apiutil.JSONAcceptHeaders...); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	accountID := c.Param(IDKey)
+	if accountID == "" {
+		err := errors.New("no feed account id specified")
+		apiutil.ErrorHandler(c, gtserror.NewErrorBadRequest(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	claim, errWithCode := action(c.Request.Context(), authed.User, accountID)
+	if errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	apiutil.JSON(c, http.StatusOK, claim)
+}
@@ -0,0 +1,107 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package feeds
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	"github.com/superseriousbusiness/gotosocial/internal/rss"
+)
+
+const (
+	// IDKey is for feed account UUIDs
+	IDKey = "id"
+	// BasePath is the base path for serving the feeds API, minus the 'api' prefix
+	BasePath = "/v1/feeds"
+	// BasePathWithID is the base path with the ID key in it.
+	// Use this anywhere you need to know the ID of the feed account being queried.
+	BasePathWithID = BasePath + "/:" + IDKey
+	// DelegateIDKey is for delegate account UUIDs
+	DelegateIDKey = "delegate_id"
+	// DelegatesPath is the path for the delegates of a feed account.
+	DelegatesPath = BasePathWithID + "/delegates"
+	// DelegatesPathWithID is the path for one delegate of a feed account.
+	DelegatesPathWithID = DelegatesPath + "/:" + DelegateIDKey
+	// ClaimPath is the path for the claim of the requester on a feed account.
+	ClaimPath = BasePathWithID + "/claim"
+	// NewslettersPath is the path for the email newsletters published by proxy accounts.
+	NewslettersPath = BasePath + "/newsletters"
+	// ScrapedPath is the path for the feeds read from web pages without any feed.
+	ScrapedPath = BasePath + "/scraped"
+	// ScrapedPreviewPath is the path for previewing the items of a scraped feed.
+	ScrapedPreviewPath = ScrapedPath + "/preview"
+	// WebhookIDKey is for webhook UUIDs
+	WebhookIDKey = "webhook_id"
+	// WebhooksPath is the path for the server-wide webhooks, receiving the new statuses of every feed account.
+	WebhooksPath = BasePath + "/webhooks"
+	// WebhooksPathWithID is the path for one webhook, of a feed account or server-wide.
+	WebhooksPathWithID = WebhooksPath + "/:" + WebhookIDKey
+	// FeedWebhooksPath is the path for the webhooks of a feed account.
+	FeedWebhooksPath = BasePathWithID + "/webhooks"
+	// DeliveryIDKey is for webhook delivery UUIDs
+	DeliveryIDKey = "delivery_id"
+	// WebhookDeliveriesPath is the path for the webhook deliveries given up on.
+	WebhookDeliveriesPath = WebhooksPath + "/deliveries"
+	// WebhookDeliveryRetryPath is the path for attempting again a webhook delivery given up on.
+	WebhookDeliveryRetryPath = WebhookDeliveriesPath + "/:" + DeliveryIDKey + "/retry"
+)
+
+type Module struct {
+	rssTooter rss.RssTooter
+	processor *processing.Processor
+}
+
+func New(rssTooter rss.RssTooter, processor *processing.Processor) *Module {
+	return &Module{
+		rssTooter: rssTooter,
+		processor: processor,
+	}
+}
+
+func (m *Module) Route(attachHandler func(method string, path string, f ...gin.HandlerFunc) gin.IRoutes) {
+	attachHandler(http.MethodGet, BasePathWithID, m.FeedGETHandler)
+	attachHandler(http.MethodPatch, BasePathWithID, m.FeedPATCHHandler)
+	attachHandler(http.MethodGet, DelegatesPath, m.FeedDelegatesGETHandler)
+	attachHandler(http.MethodPost, DelegatesPathWithID, m.FeedDelegatePOSTHandler)
+	attachHandler(http.MethodDelete, DelegatesPathWithID, m.FeedDelegateDELETEHandler)
+	attachHandler(http.MethodGet, ClaimPath, m.FeedClaimGETHandler)
+	attachHandler(http.MethodPost, ClaimPath, m.FeedClaimPOSTHandler)
+	attachHandler(http.MethodGet, NewslettersPath, m.NewslettersGETHandler)
+	attachHandler(http.MethodPost, NewslettersPath, m.NewsletterPOSTHandler)
+	attachHandler(http.MethodPost, ScrapedPath, m.ScrapedFeedPOSTHandler)
+	attachHandler(http.MethodPost, ScrapedPreviewPath, m.ScrapedFeedPreviewPOSTHandler)
+	attachHandler(http.MethodGet, WebhooksPath, m.WebhooksGETHandler)
+	attachHandler(http.MethodPost, WebhooksPath, m.WebhookPOSTHandler)
+	attachHandler(http.MethodDelete, WebhooksPathWithID, m.WebhookDELETEHandler)
+	attachHandler(http.MethodGet, FeedWebhooksPath, m.FeedWebhooksGETHandler)
+	attachHandler(http.MethodPost, FeedWebhooksPath, m.FeedWebhookPOSTHandler)
+	attachHandler(http.MethodGet, WebhookDeliveriesPath, m.WebhookDeliveriesGETHandler)
+	attachHandler(http.MethodPost, WebhookDeliveryRetryPath, m.WebhookDeliveryRetryPOSTHandler)
+}
+
+// delegateChange changes the delegate of a feed account on behalf of requester.
+type delegateChange func(ctx context.Context, requester *gtsmodel.User, accountID string, delegateAccountID string) ([]*apimodel.Account, gtserror.WithCode)
+
+// claimAction acts on the claim of requester on a feed account.
+type claimAction func(ctx context.Context, requester *gtsmodel.User, accountID string) (*apimodel.RssFeedClaim, gtserror.WithCode)
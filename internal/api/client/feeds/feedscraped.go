@@ -0,0 +1,243 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package feeds
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	apiutil "github.com/superseriousbusiness/gotosocial/internal/api/util"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// ScrapedFeedPOSTHandler swagger:operation POST /api/v1/feeds/scraped scrapedFeedCreate
+//
+// Create the account of a website without any feed, whose items are read from a web page with selectors.
+//
+// The items are published like the ones of a feed. Preview the selectors first with /api/v1/feeds/scraped/preview.
+//
+//	---
+//	tags:
+//	- feeds
+//
+//	consumes:
+//	- application/json
+//	- application/xml
+//	- application/x-www-form-urlencoded
+//
+//	produces:
+//	- application/json
+//
+//	parameters:
+//	-
+//		name: url
+//		type: string
+//		description: Url of the web page.
+//		in: formData
+//		required: true
+//	-
+//		name: items
+//		type: string
+//		description: |-
+//			Selector of the item containers in the page. Selectors are XPath expressions
+//			when they start with "/", "." or "(", and CSS selectors otherwise.
+//		in: formData
+//		required: true
+//	-
+//		name: title
+//		type: string
+//		description: Selector of the item title, in its container.
+//		in: formData
+//		required: true
+//	-
+//		name: link
+//		type: string
+//		description: Selector of the item link (its href), in its container. The first link of the item if not set.
+//		in: formData
+//	-
+//		name: date
+//		type: string
+//		description: |-
+//			Selector of the item date (its datetime attribute or its text), in its container.
+//			Items without a date are dated when first seen.
+//		in: formData
+//	-
+//		name: summary
+//		type: string
+//		description: Selector of the item summary, in its container.
+//		in: formData
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- admin
+//
+//	responses:
+//		'200':
+//			description: The created feed.
+//			schema:
+//				"$ref": "#/definitions/rssFeed"
+//		'400':
+//			description: bad request, or the selectors match no item
+//		'401':
+//			description: unauthorized
+//		'403':
+//			description: forbidden
+//		'406':
+//			description: not acceptable
+//		'409':
+//			description: conflict, a feed account already exists for the page
+//		'500':
+//			description: internal server error
+func (m *Module) ScrapedFeedPOSTHandler(c *gin.Context) {
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorUnauthorized(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	if _, err := apiutil.NegotiateAccept(c, apiutil.JSONAcceptHeaders...); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	form := &apimodel.RssScrapedFeedRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorBadRequest(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	feed, errWithCode := m.rssTooter.CreateScrapedFeed(c.Request.Context(), authed.User, form)
+	if errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	apiutil.JSON(c, http.StatusOK, feed)
+}
+
+// ScrapedFeedPreviewPOSTHandler swagger:operation POST /api/v1/feeds/scraped/preview scrapedFeedPreview
+//
+// Preview the items read from a web page with selectors, without creating anything.
+//
+// The page is fetched from its url, unless its saved html is given.
+//
+//	---
+//	tags:
+//	- feeds
+//
+//	consumes:
+//	- application/json
+//	- application/xml
+//	- application/x-www-form-urlencoded
+//
+//	produces:
+//	- application/json
+//
+//	parameters:
+//	-
+//		name: url
+//		type: string
+//		description: Url of the web page.
+//		in: formData
+//		required: true
+//	-
+//		name: items
+//		type: string
+//		description: |-
+//			Selector of the item containers in the page. Selectors are XPath expressions
+//			when they start with "/", "." or "(", and CSS selectors otherwise.
+//		in: formData
+//		required: true
+//	-
+//		name: title
+//		type: string
+//		description: Selector of the item title, in its container.
+//		in: formData
+//		required: true
+//	-
+//		name: link
+//		type: string
+//		description: Selector of the item link (its href), in its container. The first link of the item if not set.
+//		in: formData
+//	-
+//		name: date
+//		type: string
+//		description: |-
+//			Selector of the item date (its datetime attribute or its text), in its container.
+//			Items without a date are dated when first seen.
+//		in: formData
+//	-
+//		name: summary
+//		type: string
+//		description: Selector of the item summary, in its container.
+//		in: formData
+//	-
+//		name: html
+//		type: string
+//		description: Saved html of the web page, read instead of fetching the url.
+//		in: formData
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- admin
+//
+//	responses:
+//		'200':
+//			description: The items read from the page.
+//			schema:
+//				type: array
+//				items:
+//					"$ref": "#/definitions/rssScrapedItem"
+//		'400':
+//			description: bad request
+//		'401':
+//			description: unauthorized
+//		'403':
+//			description: forbidden
+//		'406':
+//			description: not acceptable
+//		'500':
+//			description: internal server error
+func (m *Module) ScrapedFeedPreviewPOSTHandler(c *gin.Context) {
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorUnauthorized(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	if _, err := apiutil.NegotiateAccept(c, apiutil.JSONAcceptHeaders...); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	form := &apimodel.RssScrapedFeedRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorBadRequest(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	items, errWithCode := m.rssTooter.PreviewScrapedFeed(c.Request.Context(), authed.User, form)
+	if errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	apiutil.JSON(c, http.StatusOK, items)
+}
@@ -0,0 +1,480 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package feeds
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	apiutil "github.com/superseriousbusiness/gotosocial/internal/api/util"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// WebhooksGETHandler swagger:operation GET /api/v1/feeds/webhooks webhooksGet
+//
+// Get the server-wide webhooks, receiving the new statuses of every feed account.
+//
+//	---
+//	tags:
+//	- feeds
+//
+//	produces:
+//	- application/json
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- admin
+//
+//	responses:
+//		'200':
+//			description: The server-wide webhooks.
+//			schema:
+//				type: array
+//				items:
+//					"$ref": "#/definitions/rssWebhook"
+//		'401':
+//			description: unauthorized
+//		'403':
+//			description: forbidden
+//		'406':
+//			description: not acceptable
+//		'500':
+//			description: internal server error
+func (m *Module) WebhooksGETHandler(c *gin.Context) {
+	m.webhooksGetHandler(c, false)
+}
+
+// FeedWebhooksGETHandler swagger:operation GET /api/v1/feeds/{id}/webhooks feedWebhooksGet
+//
+// Get the webhooks receiving the new statuses of a feed account.
+//
+// Server-wide webhooks, which receive them too, are not included.
+//
+//	---
+//	tags:
+//	- feeds
+//
+//	produces:
+//	- application/json
+//
+//	parameters:
+//	-
+//		name: id
+//		type: string
+//		description: ID of the feed account.
+//		in: path
+//		required: true
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- read:accounts
+//
+//	responses:
+//		'200':
+//			description: The webhooks of the feed account.
+//			schema:
+//				type: array
+//				items:
+//					"$ref": "#/definitions/rssWebhook"
+//		'400':
+//			description: bad request
+//		'401':
+//			description: unauthorized
+//		'403':
+//			description: forbidden, requester is neither an admin nor a delegate of the feed account
+//		'404':
+//			description: not found
+//		'406':
+//			description: not acceptable
+//		'500':
+//			description: internal server error
+func (m *Module) FeedWebhooksGETHandler(c *gin.Context) {
+	m.webhooksGetHandler(c, true)
+}
+
+// WebhookPOSTHandler swagger:operation POST /api/v1/feeds/webhooks webhookCreate
+//
+// Create a server-wide webhook, receiving the new statuses of every feed account.
+//
+// Each status posted from a feed item is POSTed to the webhook url as JSON, with the item and
+// its feed, and signed with the returned secret. Failed deliveries are attempted again with
+// an exponential backoff, and given up on after 8 attempts.
+//
+//	---
+//	tags:
+//	- feeds
+//
+//	consumes:
+//	- application/json
+//	- application/xml
+//	- application/x-www-form-urlencoded
+//
+//	produces:
+//	- application/json
+//
+//	parameters:
+//	-
+//		name: url
+//		type: string
+//		description: Http(s) url receiving the POST requests.
+//		in: formData
+//		required: true
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- admin
+//
+//	responses:
+//		'200':
+//			description: The webhook, with its secret.
+//			schema:
+//				"$ref": "#/definitions/rssWebhook"
+//		'400':
+//			description: bad request
+//		'401':
+//			description: unauthorized
+//		'403':
+//			description: forbidden
+//		'406':
+//			description: not acceptable
+//		'500':
+//			description: internal server error
+func (m *Module) WebhookPOSTHandler(c *gin.Context) {
+	m.webhookPostHandler(c, false)
+}
+
+// FeedWebhookPOSTHandler swagger:operation POST /api/v1/feeds/{id}/webhooks feedWebhookCreate
+//
+// Create a webhook receiving the new statuses of a feed account.
+//
+// Each status posted from a feed item is POSTed to the webhook url as JSON, with the item and
+// its feed, and signed with the returned secret. Failed deliveries are attempted again with
+// an exponential backoff, and given up on after 8 attempts.
+//
+//	---
+//	tags:
+//	- feeds
+//
+//	consumes:
+//	- application/json
+//	- application/xml
+//	- application/x-www-form-urlencoded
+//
+//	produces:
+//	- application/json
+//
+//	parameters:
+//	-
+//		name: id
+//		type: string
+//		description: ID of the feed account.
+//		in: path
+//		required: true
+//	-
+//		name: url
+//		type: string
+//		description: Http(s) url receiving the POST requests.
+//		in: formData
+//		required: true
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- write:accounts
+//
+//	responses:
+//		'200':
+//			description: The webhook, with its secret.
+//			schema:
+//				"$ref": "#/definitions/rssWebhook"
+//		'400':
+//			description: bad request
+//		'401':
+//			description: unauthorized
+//		'403':
+//			description: forbidden, requester is neither an admin nor a delegate of the feed account
+//		'404':
+//			description: not found
+//		'406':
+//			description: not acceptable
+//		'500':
+//			description: internal server error
+func (m *Module) FeedWebhookPOSTHandler(c *gin.Context) {
+	m.webhookPostHandler(c, true)
+}
+
+// WebhookDELETEHandler swagger:operation DELETE /api/v1/feeds/webhooks/{webhook_id} webhookDelete
+//
+// Delete a webhook, of a feed account or server-wide, with its pending and failed deliveries.
+//
+//	---
+//	tags:
+//	- feeds
+//
+//	produces:
+//	- application/json
+//
+//	parameters:
+//	-
+//		name: webhook_id
+//		type: string
+//		description: ID of the webhook.
+//		in: path
+//		required: true
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- write:accounts
+//
+//	responses:
+//		'200':
+//			description: The webhook was deleted.
+//		'400':
+//			description: bad request
+//		'401':
+//			description: unauthorized
+//		'403':
+//			description: forbidden, requester is not allowed to manage the webhook
+//		'404':
+//			description: not found
+//		'406':
+//			description: not acceptable
+//		'500':
+//			description: internal server error
+func (m *Module) WebhookDELETEHandler(c *gin.Context) {
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorUnauthorized(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	if _, err := apiutil.NegotiateAccept(c, apiutil.JSONAcceptHeaders...); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	webhookID := c.Param(WebhookIDKey)
+	if webhookID == "" {
+		err := errors.New("no webhook id specified")
+		apiutil.ErrorHandler(c, gtserror.NewErrorBadRequest(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	if errWithCode := m.rssTooter.DeleteWebhook(c.Request.Context(), authed.User, webhookID); errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	apiutil.Data(c, http.StatusOK, apiutil.AppJSON, apiutil.EmptyJSONObject)
+}
+
+// WebhookDeliveriesGETHandler swagger:operation GET /api/v1/feeds/webhooks/deliveries webhookDeliveriesGet
+//
+// Get the webhook deliveries given up on after failing repeatedly, most recent first.
+//
+// They are kept for 30 days.
+//
+//	---
+//	tags:
+//	- feeds
+//
+//	produces:
+//	- application/json
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- admin
+//
+//	responses:
+//		'200':
+//			description: The failed deliveries.
+//			schema:
+//				type: array
+//				items:
+//					"$ref": "#/definitions/rssWebhookDelivery"
+//		'401':
+//			description: unauthorized
+//		'403':
+//			description: forbidden
+//		'406':
+//			description: not acceptable
+//		'500':
+//			description: internal server error
+func (m *Module) WebhookDeliveriesGETHandler(c *gin.Context) {
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorUnauthorized(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	if _, err := apiutil.NegotiateAccept(c, apiutil.JSONAcceptHeaders...); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	deliveries, errWithCode := m.rssTooter.GetFailedWebhookDeliveries(c.Request.Context(), authed.User)
+	if errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	apiutil.JSON(c, http.StatusOK, deliveries)
+}
+
+// WebhookDeliveryRetryPOSTHandler swagger:operation POST /api/v1/feeds/webhooks/deliveries/{delivery_id}/retry webhookDeliveryRetry
+//
+// Attempt again a webhook delivery given up on, with all its attempts.
+//
+//	---
+//	tags:
+//	- feeds
+//
+//	produces:
+//	- application/json
+//
+//	parameters:
+//	-
+//		name: delivery_id
+//		type: string
+//		description: ID of the delivery.
+//		in: path
+//		required: true
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- admin
+//
+//	responses:
+//		'200':
+//			description: The delivery, pending again.
+//			schema:
+//				"$ref": "#/definitions/rssWebhookDelivery"
+//		'400':
+//			description: bad request
+//		'401':
+//			description: unauthorized
+//		'403':
+//			description: forbidden
+//		'404':
+//			description: not found
+//		'406':
+//			description: not acceptable
+//		'422':
+//			description: unprocessable, the delivery is still pending
+//		'500':
+//			description: internal server error
+func (m *Module) WebhookDeliveryRetryPOSTHandler(c *gin.Context) {
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorUnauthorized(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	if _, err := apiutil.NegotiateAccept(c, apiutil.JSONAcceptHeaders...); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	deliveryID := c.Param(DeliveryIDKey)
+	if deliveryID == "" {
+		err := errors.New("no webhook delivery id specified")
+		apiutil.ErrorHandler(c, gtserror.NewErrorBadRequest(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	delivery, errWithCode := m.rssTooter.RetryWebhookDelivery(c.Request.Context(), authed.User, deliveryID)
+	if errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	apiutil.JSON(c, http.StatusOK, delivery)
+}
+
+// webhooksGetHandler returns the webhooks of the feed account
+// in the path if feed is set, or the server-wide ones.
+func (m *Module) webhooksGetHandler(c *gin.Context, feed bool) {
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorUnauthorized(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	if _, err := apiutil.NegotiateAccept(c, apiutil.JSONAcceptHeaders...); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	var accountID string
+	if feed {
+		accountID = c.Param(IDKey)
+		if accountID == "" {
+			err := errors.New("no feed account id specified")
+			apiutil.ErrorHandler(c, gtserror.NewErrorBadRequest(err, err.Error()), m.processor.InstanceGetV1)
+			return
+		}
+	}
+
+	webhooks, errWithCode := m.rssTooter.GetWebhooks(c.Request.Context(), authed.User, accountID)
+	if errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	apiutil.JSON(c, http.StatusOK, webhooks)
+}
+
+// webhookPostHandler creates a webhook of the feed account
+// in the path if feed is set, or a server-wide one.
+func (m *Module) webhookPostHandler(c *gin.Context, feed bool) {
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorUnauthorized(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	if _, err := apiutil.NegotiateAccept(c, apiutil.JSONAcceptHeaders...); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	var accountID string
+	if feed {
+		accountID = c.Param(IDKey)
+		if accountID == "" {
+			err := errors.New("no feed account id specified")
+			apiutil.ErrorHandler(c, gtserror.NewErrorBadRequest(err, err.Error()), m.processor.InstanceGetV1)
+			return
+		}
+	}
+
+	form := &apimodel.RssWebhookCreateRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorBadRequest(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	webhook, errWithCode := m.rssTooter.CreateWebhook(c.Request.Context(), authed.User, accountID, form)
+	if errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	apiutil.JSON(c, http.StatusOK, webhook)
+}
@@ -0,0 +1,355 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package reader
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	apiutil "github.com/superseriousbusiness/gotosocial/internal/api/util"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/rss"
+)
+
+const (
+	// feverAPIVersion is the version of the Fever API implemented.
+	feverAPIVersion = 3
+	// feverItemCount is the number of items Fever returns at once.
+	feverItemCount = 50
+)
+
+// FeverHandler serves the Fever API, authenticated by the md5 of
+// "username:password" of an app password (api_key). The sections of the
+// response are requested by the parameters of their name; marks (mark, as,
+// id and before) are applied before them.
+//
+// Fever ids are integers: feeds, groups and items are given the
+// ReaderItemID of the feed accounts, lists and statuses they stand for.
+func (m *Module) FeverHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	form := readerForm(c)
+
+	response := gin.H{"api_version": feverAPIVersion, "auth": 0}
+
+	account, errWithCode := m.rssTooter.FeverAuth(ctx, form.Get("api_key"))
+	if errWithCode != nil {
+		if errWithCode.Code() != http.StatusUnauthorized {
+			feverError(c, errWithCode)
+			return
+		}
+		// Fever reports failed logins in the body.
+		apiutil.JSON(c, http.StatusOK, response)
+		return
+	}
+	response["auth"] = 1
+	response["last_refreshed_on_time"] = time.Now().Unix()
+
+	if form.Has("mark") {
+		if errWithCode := m.feverMark(ctx, account, form, response); errWithCode != nil {
+			feverError(c, errWithCode)
+			return
+		}
+	}
+
+	if form.Has("groups") || form.Has("feeds") {
+		if errWithCode := m.feverFeeds(ctx, account, form, response); errWithCode != nil {
+			feverError(c, errWithCode)
+			return
+		}
+	}
+
+	if form.Has("favicons") {
+		response["favicons"] = []struct{}{}
+	}
+
+	if form.Has("items") {
+		if errWithCode := m.feverItems(ctx, account, form, response); errWithCode != nil {
+			feverError(c, errWithCode)
+			return
+		}
+	}
+
+	if form.Has("links") {
+		response["links"] = []struct{}{}
+	}
+
+	if form.Has("unread_item_ids") {
+		if errWithCode := m.feverItemIDs(ctx, account, "unread_item_ids", response); errWithCode != nil {
+			feverError(c, errWithCode)
+			return
+		}
+	}
+
+	if form.Has("saved_item_ids") {
+		if errWithCode := m.feverItemIDs(ctx, account, "saved_item_ids", response); errWithCode != nil {
+			feverError(c, errWithCode)
+			return
+		}
+	}
+
+	apiutil.JSON(c, http.StatusOK, response)
+}
+
+// feverMark marks an item as read, unread, saved or unsaved, or the items
+// of a feed or group received before a time in seconds as read. Read states
+// are not kept per feed: the items of every feed are marked read.
+func (m *Module) feverMark(ctx context.Context, account *gtsmodel.Account, form url.Values, response gin.H) gtserror.WithCode {
+	as := form.Get("as")
+
+	switch form.Get("mark") {
+	case "item":
+		itemID, err := strconv.ParseInt(form.Get("id"), 10, 64)
+		if err != nil {
+			return gtserror.NewErrorBadRequest(err, "invalid item id")
+		}
+
+		items, errWithCode := m.rssTooter.ReaderItemsByIDs(ctx, account, []int64{itemID})
+		if errWithCode != nil {
+			return errWithCode
+		}
+
+		switch as {
+		case "read":
+			errWithCode = m.markRead(ctx, account, items)
+		case "unread":
+			errWithCode = m.markUnread(ctx, account, items)
+		case "saved":
+			errWithCode = m.star(ctx, account, items, true)
+		case "unsaved":
+			errWithCode = m.star(ctx, account, items, false)
+		}
+		if errWithCode != nil {
+			return errWithCode
+		}
+
+		if as == "saved" || as == "unsaved" {
+			return m.feverItemIDs(ctx, account, "saved_item_ids", response)
+		}
+
+	case "feed", "group":
+		if as != "read" {
+			return nil
+		}
+
+		before := time.Now()
+		if b := form.Get("before"); b != "" {
+			seconds, err := strconv.ParseInt(b, 10, 64)
+			if err != nil {
+				return gtserror.NewErrorBadRequest(err, "invalid before time")
+			}
+			before = time.Unix(seconds, 0)
+		}
+
+		if errWithCode := m.updateMarker(ctx, account, rss.ReaderTimeID(before), true); errWithCode != nil {
+			return errWithCode
+		}
+
+	default:
+		return nil
+	}
+
+	return m.feverItemIDs(ctx, account, "unread_item_ids", response)
+}
+
+// feverFeeds adds the groups and feeds of account to response, with the
+// feeds of each group, as the subscriptions of the Google Reader API.
+func (m *Module) feverFeeds(ctx context.Context, account *gtsmodel.Account, form url.Values, response gin.H) gtserror.WithCode {
+	subscriptions, errWithCode := m.rssTooter.ReaderSubscriptions(ctx, account)
+	if errWithCode != nil {
+		return errWithCode
+	}
+
+	var (
+		groups      = make([]*apimodel.FeverGroup, 0)
+		groupFeeds  = make(map[int64][]string)
+		feeds       = make([]*apimodel.FeverFeed, 0, len(subscriptions))
+		feedsGroups = make([]*apimodel.FeverFeedsGroup, 0)
+	)
+	for _, subscription := range subscriptions {
+		feedID := rss.ReaderItemID(subscription.Account.ID)
+
+		for _, list := range subscription.Lists {
+			groupID := rss.ReaderItemID(list.ID)
+			if _, ok := groupFeeds[groupID]; !ok {
+				groups = append(groups, &apimodel.FeverGroup{ID: groupID, Title: list.Title})
+			}
+			groupFeeds[groupID] = append(groupFeeds[groupID], strconv.FormatInt(feedID, 10))
+		}
+
+		var lastUpdated int64
+		if !subscription.Feed.LastItemAt.IsZero() {
+			lastUpdated = subscription.Feed.LastItemAt.Unix()
+		}
+
+		feeds = append(feeds, &apimodel.FeverFeed{
+			ID:                feedID,
+			Title:             accountTitle(subscription.Account),
+			URL:               subscription.Feed.URL,
+			SiteURL:           subscription.Account.URL,
+			LastUpdatedOnTime: lastUpdated,
+		})
+	}
+
+	for _, group := range groups {
+		feedsGroups = append(feedsGroups, &apimodel.FeverFeedsGroup{
+			GroupID: group.ID,
+			FeedIDs: strings.Join(groupFeeds[group.ID], ","),
+		})
+	}
+
+	if form.Has("groups") {
+		response["groups"] = groups
+	}
+	if form.Has("feeds") {
+		response["feeds"] = feeds
+	}
+	response["feeds_groups"] = feedsGroups
+	return nil
+}
+
+// feverItems adds to response the items of account newer than since_id,
+// oldest first, older than max_id, or of the comma-separated with_ids.
+func (m *Module) feverItems(ctx context.Context, account *gtsmodel.Account, form url.Values, response gin.H) gtserror.WithCode {
+	counts, errWithCode := m.rssTooter.ReaderItemCounts(ctx, account, false)
+	if errWithCode != nil {
+		return errWithCode
+	}
+	var total int
+	for _, count := range counts {
+		total += count.Count
+	}
+
+	var items []*rss.ReaderItem
+	if withIDs := form.Get("with_ids"); withIDs != "" {
+		itemIDs := make([]int64, 0, feverItemCount)
+		for _, i := range strings.Split(withIDs, ",") {
+			itemID, err := strconv.ParseInt(strings.TrimSpace(i), 10, 64)
+			if err != nil {
+				return gtserror.NewErrorBadRequest(err, "invalid item id")
+			}
+			itemIDs = append(itemIDs, itemID)
+			if len(itemIDs) == feverItemCount {
+				break
+			}
+		}
+
+		items, errWithCode = m.rssTooter.ReaderItemsByIDs(ctx, account, itemIDs)
+	} else {
+		sinceID, _ := strconv.ParseInt(form.Get("since_id"), 10, 64)
+		maxID, _ := strconv.ParseInt(form.Get("max_id"), 10, 64)
+		minStatusID, maxStatusID := rss.ReaderItemIDBounds(sinceID, maxID)
+
+		items, _, errWithCode = m.rssTooter.ReaderItems(ctx, account, &rss.ReaderQuery{
+			Stream: rss.ReaderStreamReadingList,
+			MaxID:  maxStatusID,
+			MinID:  minStatusID,
+			Oldest: form.Has("since_id"),
+			Limit:  feverItemCount,
+		})
+	}
+	if errWithCode != nil {
+		return errWithCode
+	}
+
+	feverItems := make([]*apimodel.FeverItem, 0, len(items))
+	for _, item := range items {
+		feverItems = append(feverItems, feverItem(item))
+	}
+
+	response["total_items"] = total
+	response["items"] = feverItems
+	return nil
+}
+
+// feverItemIDs adds to response the comma-separated ids of the unread or saved items of account.
+func (m *Module) feverItemIDs(ctx context.Context, account *gtsmodel.Account, key string, response gin.H) gtserror.WithCode {
+	query := &rss.ReaderQuery{
+		Stream: rss.ReaderStreamReadingList,
+		Unread: true,
+		Limit:  rss.ReaderMaxItems,
+	}
+	if key == "saved_item_ids" {
+		query = &rss.ReaderQuery{
+			Stream: rss.ReaderStreamStarred,
+			Limit:  rss.ReaderMaxItems,
+		}
+	}
+
+	items, _, errWithCode := m.rssTooter.ReaderItems(ctx, account, query)
+	if errWithCode != nil {
+		return errWithCode
+	}
+
+	itemIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		itemIDs = append(itemIDs, strconv.FormatInt(item.ID, 10))
+	}
+
+	response[key] = strings.Join(itemIDs, ",")
+	return nil
+}
+
+// feverError responds with the status code of an error, Fever having no error format.
+func feverError(c *gin.Context, errWithCode gtserror.WithCode) {
+	if errWithCode.Code() >= http.StatusInternalServerError {
+		log.Errorf(c.Request.Context(), "error serving fever api: %v", errWithCode.Error())
+	}
+
+	apiutil.JSON(c, errWithCode.Code(), gin.H{"api_version": feverAPIVersion, "auth": 1, "error": errWithCode.Safe()})
+}
+
+func feverItem(item *rss.ReaderItem) *apimodel.FeverItem {
+	status := item.Status
+	content := status
+	if status.BoostOf != nil {
+		content = status.BoostOf
+	}
+
+	var author string
+	if content.Account != nil {
+		author = accountTitle(content.Account)
+	}
+
+	var saved, read int
+	if item.Starred {
+		saved = 1
+	}
+	if item.Read {
+		read = 1
+	}
+
+	return &apimodel.FeverItem{
+		ID:            item.ID,
+		FeedID:        rss.ReaderItemID(status.AccountID),
+		Title:         item.Title,
+		Author:        author,
+		HTML:          item.Content,
+		URL:           content.URL,
+		IsSaved:       saved,
+		IsRead:        read,
+		CreatedOnTime: content.CreatedAt.Unix(),
+	}
+}
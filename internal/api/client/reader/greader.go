@@ -0,0 +1,824 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package reader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	apiutil "github.com/superseriousbusiness/gotosocial/internal/api/util"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/rss"
+)
+
+// The Google Reader API isn't documented by its authors anymore: the
+// handlers below follow the subset implemented by the servers and apps
+// still speaking it, authenticated by app passwords.
+
+const (
+	// readerAccountKey is the key of the account authenticated by readerAuth.
+	readerAccountKey = "readerAccount"
+	// googleLoginPrefix prefixes the auth token in the Authorization header.
+	googleLoginPrefix = "GoogleLogin auth="
+	// itemIDPrefix prefixes the hexadecimal item ids of the long form.
+	itemIDPrefix = "tag:google.com,2005:reader/item/"
+	// defaultItemCount is the number of items of a stream when apps don't ask for one.
+	defaultItemCount = 20
+)
+
+// ClientLoginHandler logs in to the Google Reader API with the username or email
+// address of an account (Email) and one of its app passwords (Passwd), returning
+// the auth token of the Authorization header of the next requests.
+func (m *Module) ClientLoginHandler(c *gin.Context) {
+	form := readerForm(c)
+	password := form.Get("Passwd")
+
+	if _, errWithCode := m.rssTooter.ReaderLogin(c.Request.Context(), form.Get("Email"), password); errWithCode != nil {
+		if errWithCode.Code() == http.StatusUnauthorized {
+			apiutil.Data(c, http.StatusUnauthorized, apiutil.TextPlain, []byte("Error=BadAuthentication\n"))
+			return
+		}
+		readerError(c, errWithCode)
+		return
+	}
+
+	// The app password is the auth token.
+	if form.Get("output") == "json" {
+		apiutil.JSON(c, http.StatusOK, gin.H{"SID": password, "LSID": "none", "Auth": password})
+		return
+	}
+	apiutil.Data(c, http.StatusOK, apiutil.TextPlain, []byte("SID="+password+"\nLSID=none\nAuth="+password+"\n"))
+}
+
+// TokenGETHandler returns the token apps send back with their edits.
+// Requests being authenticated by their header, it is not checked.
+func (m *Module) TokenGETHandler(c *gin.Context) {
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), googleLoginPrefix)
+	apiutil.Data(c, http.StatusOK, apiutil.TextPlain, []byte(token+"\n"))
+}
+
+// UserInfoGETHandler returns the account logged in.
+func (m *Module) UserInfoGETHandler(c *gin.Context) {
+	account := readerAccount(c)
+
+	apiutil.JSON(c, http.StatusOK, &apimodel.ReaderUserInfo{
+		UserID:        account.ID,
+		UserName:      account.Username,
+		UserProfileID: account.ID,
+	})
+}
+
+// SubscriptionListGETHandler returns the followed feed accounts, with the lists including them as folders.
+func (m *Module) SubscriptionListGETHandler(c *gin.Context) {
+	subscriptions, errWithCode := m.rssTooter.ReaderSubscriptions(c.Request.Context(), readerAccount(c))
+	if errWithCode != nil {
+		readerError(c, errWithCode)
+		return
+	}
+
+	apiSubscriptions := make([]*apimodel.ReaderSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		apiSubscriptions = append(apiSubscriptions, apiSubscription(subscription))
+	}
+
+	apiutil.JSON(c, http.StatusOK, &apimodel.ReaderSubscriptionList{Subscriptions: apiSubscriptions})
+}
+
+// SubscriptionEditPOSTHandler follows (ac=subscribe) or unfollows (ac=unsubscribe) the feed
+// accounts of streams (s), feed/{account_id} or feed/{url}, and adds them to the lists of
+// labels (a) or removes them from the lists of labels (r), creating the missing lists.
+func (m *Module) SubscriptionEditPOSTHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	account := readerAccount(c)
+	form := readerForm(c)
+
+	action := form.Get("ac")
+	if action != "subscribe" && action != "unsubscribe" && action != "edit" {
+		err := fmt.Errorf("unknown subscription action %q", action)
+		readerError(c, gtserror.NewErrorBadRequest(err, err.Error()))
+		return
+	}
+
+	for _, stream := range form["s"] {
+		targetID, errWithCode := m.subscriptionAccountID(ctx, stream)
+		if errWithCode != nil {
+			readerError(c, errWithCode)
+			return
+		}
+
+		switch action {
+		case "subscribe":
+			_, errWithCode = m.processor.Account().FollowCreate(ctx, account, &apimodel.AccountFollowRequest{ID: targetID})
+		case "unsubscribe":
+			_, errWithCode = m.processor.Account().FollowRemove(ctx, account, targetID)
+		}
+		if errWithCode != nil {
+			readerError(c, errWithCode)
+			return
+		}
+
+		if action == "unsubscribe" {
+			continue
+		}
+
+		for _, label := range form["a"] {
+			if errWithCode := m.addToLabel(ctx, account, label, targetID); errWithCode != nil {
+				readerError(c, errWithCode)
+				return
+			}
+		}
+
+		for _, label := range form["r"] {
+			list, errWithCode := m.labelList(ctx, account, label, false)
+			if errWithCode == nil && list != nil {
+				errWithCode = m.processor.List().RemoveFromList(ctx, account, list.ID, []string{targetID})
+			}
+			if errWithCode != nil {
+				readerError(c, errWithCode)
+				return
+			}
+		}
+	}
+
+	readerOK(c)
+}
+
+// QuickAddPOSTHandler follows the feed account of a website or feed url (quickadd).
+func (m *Module) QuickAddPOSTHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	account := readerAccount(c)
+	query := readerForm(c).Get("quickadd")
+
+	feedAccount, errWithCode := m.rssTooter.ReaderSubscribe(ctx, strings.TrimPrefix(query, rss.ReaderStreamFeed))
+	if errWithCode != nil {
+		if errWithCode.Code() == http.StatusBadRequest {
+			// No feed found.
+			apiutil.JSON(c, http.StatusOK, &apimodel.ReaderQuickAdd{Query: query})
+			return
+		}
+		readerError(c, errWithCode)
+		return
+	}
+
+	if _, errWithCode := m.processor.Account().FollowCreate(ctx, account, &apimodel.AccountFollowRequest{ID: feedAccount.ID}); errWithCode != nil {
+		readerError(c, errWithCode)
+		return
+	}
+
+	apiutil.JSON(c, http.StatusOK, &apimodel.ReaderQuickAdd{
+		NumResults: 1,
+		Query:      query,
+		StreamID:   rss.ReaderStreamFeed + feedAccount.ID,
+		StreamName: accountTitle(feedAccount),
+	})
+}
+
+// TagListGETHandler returns the starred state and the lists, as folders.
+func (m *Module) TagListGETHandler(c *gin.Context) {
+	lists, errWithCode := m.processor.List().GetAll(c.Request.Context(), readerAccount(c))
+	if errWithCode != nil {
+		readerError(c, errWithCode)
+		return
+	}
+
+	tags := []*apimodel.ReaderTag{{ID: rss.ReaderStreamStarred}}
+	for _, list := range lists {
+		tags = append(tags, &apimodel.ReaderTag{ID: rss.ReaderStreamLabel + list.Title, Type: "folder"})
+	}
+
+	apiutil.JSON(c, http.StatusOK, &apimodel.ReaderTagList{Tags: tags})
+}
+
+// RenameTagPOSTHandler renames the list of a label (s or t) to the one of another label (dest).
+func (m *Module) RenameTagPOSTHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	account := readerAccount(c)
+	form := readerForm(c)
+
+	list, errWithCode := m.formLabelList(ctx, account, form)
+	if errWithCode != nil {
+		readerError(c, errWithCode)
+		return
+	}
+
+	title := strings.TrimPrefix(rss.ReaderStream(form.Get("dest")), rss.ReaderStreamLabel)
+	if title == "" {
+		err := errors.New("no destination label specified")
+		readerError(c, gtserror.NewErrorBadRequest(err, err.Error()))
+		return
+	}
+
+	if _, errWithCode := m.processor.List().Update(ctx, account, list.ID, &title, nil); errWithCode != nil {
+		readerError(c, errWithCode)
+		return
+	}
+
+	readerOK(c)
+}
+
+// DisableTagPOSTHandler deletes the list of a label (s or t).
+func (m *Module) DisableTagPOSTHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	account := readerAccount(c)
+
+	list, errWithCode := m.formLabelList(ctx, account, readerForm(c))
+	if errWithCode != nil {
+		readerError(c, errWithCode)
+		return
+	}
+
+	if errWithCode := m.processor.List().Delete(ctx, account, list.ID); errWithCode != nil {
+		readerError(c, errWithCode)
+		return
+	}
+
+	readerOK(c)
+}
+
+// UnreadCountGETHandler returns the number of unread items of the subscriptions, labels and reading list.
+func (m *Module) UnreadCountGETHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	account := readerAccount(c)
+
+	counts, errWithCode := m.rssTooter.ReaderItemCounts(ctx, account, true)
+	if errWithCode != nil {
+		readerError(c, errWithCode)
+		return
+	}
+
+	subscriptions, errWithCode := m.rssTooter.ReaderSubscriptions(ctx, account)
+	if errWithCode != nil {
+		readerError(c, errWithCode)
+		return
+	}
+	lists := make(map[string][]*gtsmodel.List, len(subscriptions))
+	for _, subscription := range subscriptions {
+		lists[subscription.Account.ID] = subscription.Lists
+	}
+
+	total := &rss.ReaderItemCount{}
+	labels := make(map[string]*rss.ReaderItemCount)
+	unreadCounts := make([]*apimodel.ReaderUnreadCount, 0, len(counts)+1)
+	for _, count := range counts {
+		unreadCounts = append(unreadCounts, apiUnreadCount(rss.ReaderStreamFeed+count.AccountID, count))
+		addCount(total, count)
+
+		for _, list := range lists[count.AccountID] {
+			if labels[list.Title] == nil {
+				labels[list.Title] = &rss.ReaderItemCount{}
+			}
+			addCount(labels[list.Title], count)
+		}
+	}
+	for title, count := range labels {
+		unreadCounts = append(unreadCounts, apiUnreadCount(rss.ReaderStreamLabel+title, count))
+	}
+	unreadCounts = append(unreadCounts, apiUnreadCount(rss.ReaderStreamReadingList, total))
+
+	apiutil.JSON(c, http.StatusOK, &apimodel.ReaderUnreadCounts{
+		Max:          rss.ReaderMaxItems,
+		UnreadCounts: unreadCounts,
+	})
+}
+
+// StreamItemIDsGETHandler returns the ids of the items of a stream (s).
+func (m *Module) StreamItemIDsGETHandler(c *gin.Context) {
+	form := readerForm(c)
+
+	query, errWithCode := streamQuery(form, form.Get("s"))
+	if errWithCode != nil {
+		readerError(c, errWithCode)
+		return
+	}
+
+	items, continuation, errWithCode := m.rssTooter.ReaderItems(c.Request.Context(), readerAccount(c), query)
+	if errWithCode != nil {
+		readerError(c, errWithCode)
+		return
+	}
+
+	itemRefs := make([]*apimodel.ReaderItemRef, 0, len(items))
+	for _, item := range items {
+		itemRefs = append(itemRefs, &apimodel.ReaderItemRef{
+			ID:              strconv.FormatInt(item.ID, 10),
+			DirectStreamIDs: []string{rss.ReaderStreamFeed + item.Status.AccountID},
+			TimestampUsec:   strconv.FormatInt(rss.ReaderIDTime(item.Status.ID).UnixMicro(), 10),
+		})
+	}
+
+	apiutil.JSON(c, http.StatusOK, &apimodel.ReaderItemRefs{
+		ItemRefs:     itemRefs,
+		Continuation: continuation,
+	})
+}
+
+// StreamItemContentsHandler returns the items of the given ids (i).
+func (m *Module) StreamItemContentsHandler(c *gin.Context) {
+	items, errWithCode := m.formItems(c.Request.Context(), readerAccount(c), readerForm(c))
+	if errWithCode != nil {
+		readerError(c, errWithCode)
+		return
+	}
+
+	apiutil.JSON(c, http.StatusOK, apiStreamContents(rss.ReaderStreamReadingList, items, ""))
+}
+
+// StreamContentsGETHandler returns the items of the stream of the path, or of s, the reading list by default.
+func (m *Module) StreamContentsGETHandler(c *gin.Context) {
+	form := readerForm(c)
+
+	stream := strings.TrimPrefix(c.Param(StreamKey), "/")
+	if stream == "" {
+		stream = form.Get("s")
+	}
+
+	query, errWithCode := streamQuery(form, stream)
+	if errWithCode != nil {
+		readerError(c, errWithCode)
+		return
+	}
+
+	items, continuation, errWithCode := m.rssTooter.ReaderItems(c.Request.Context(), readerAccount(c), query)
+	if errWithCode != nil {
+		readerError(c, errWithCode)
+		return
+	}
+
+	apiutil.JSON(c, http.StatusOK, apiStreamContents(query.Stream, items, continuation))
+}
+
+// EditTagPOSTHandler stars or marks as read the items of the given ids (i)
+// when adding the starred or read states (a), and unstars or marks them as
+// unread when removing them (r).
+//
+// Marking an item as read moves the home timeline marker to it: the older
+// items are marked read too. Marking it unread moves the marker before it.
+func (m *Module) EditTagPOSTHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	account := readerAccount(c)
+	form := readerForm(c)
+
+	items, errWithCode := m.formItems(ctx, account, form)
+	if errWithCode != nil {
+		readerError(c, errWithCode)
+		return
+	}
+
+	for _, tag := range form["a"] {
+		switch rss.ReaderStream(tag) {
+		case rss.ReaderStreamStarred:
+			errWithCode = m.star(ctx, account, items, true)
+		case rss.ReaderStreamRead:
+			errWithCode = m.markRead(ctx, account, items)
+		case rss.ReaderStreamKeptUnread:
+			errWithCode = m.markUnread(ctx, account, items)
+		}
+		if errWithCode != nil {
+			readerError(c, errWithCode)
+			return
+		}
+	}
+
+	for _, tag := range form["r"] {
+		switch rss.ReaderStream(tag) {
+		case rss.ReaderStreamStarred:
+			errWithCode = m.star(ctx, account, items, false)
+		case rss.ReaderStreamRead:
+			errWithCode = m.markUnread(ctx, account, items)
+		}
+		if errWithCode != nil {
+			readerError(c, errWithCode)
+			return
+		}
+	}
+
+	readerOK(c)
+}
+
+// MarkAllAsReadPOSTHandler marks the items received before a time in
+// microseconds (ts), or now, as read. Read states are not kept per stream:
+// the items of every stream are marked read.
+func (m *Module) MarkAllAsReadPOSTHandler(c *gin.Context) {
+	before := time.Now()
+	if ts := readerForm(c).Get("ts"); ts != "" {
+		usec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			err := fmt.Errorf("invalid timestamp %q", ts)
+			readerError(c, gtserror.NewErrorBadRequest(err, err.Error()))
+			return
+		}
+		before = time.UnixMicro(usec)
+	}
+
+	if errWithCode := m.updateMarker(c.Request.Context(), readerAccount(c), rss.ReaderTimeID(before), true); errWithCode != nil {
+		readerError(c, errWithCode)
+		return
+	}
+
+	readerOK(c)
+}
+
+// readerAuth authenticates the requests of the Google Reader API by the app password of their Authorization header.
+func (m *Module) readerAuth(c *gin.Context) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), googleLoginPrefix)
+	if !ok || token == "" {
+		err := errors.New("no auth token")
+		readerError(c, gtserror.NewErrorUnauthorized(err, err.Error()))
+		return
+	}
+
+	account, errWithCode := m.rssTooter.ReaderAuth(c.Request.Context(), token)
+	if errWithCode != nil {
+		readerError(c, errWithCode)
+		return
+	}
+
+	c.Set(readerAccountKey, account)
+}
+
+// readerAccount returns the account authenticated by readerAuth.
+func readerAccount(c *gin.Context) *gtsmodel.Account {
+	return c.MustGet(readerAccountKey).(*gtsmodel.Account)
+}
+
+// readerForm returns the parameters of a request, from its url and body,
+// as apps send them either way and repeat the parameters of lists.
+func readerForm(c *gin.Context) url.Values {
+	if err := c.Request.ParseForm(); err != nil {
+		log.Debugf(c.Request.Context(), "error parsing reader form: %v", err)
+	}
+	return c.Request.Form
+}
+
+// readerOK responds to a successful edit.
+func readerOK(c *gin.Context) {
+	apiutil.Data(c, http.StatusOK, apiutil.TextPlain, []byte("OK"))
+}
+
+// readerError responds with the text of an error, as apps expect, and aborts the request.
+func readerError(c *gin.Context, errWithCode gtserror.WithCode) {
+	if errWithCode.Code() >= http.StatusInternalServerError {
+		log.Errorf(c.Request.Context(), "error serving reader api: %v", errWithCode.Error())
+	}
+
+	c.Abort()
+	apiutil.Data(c, errWithCode.Code(), apiutil.TextPlain, []byte(errWithCode.Safe()+"\n"))
+}
+
+// streamQuery returns the query of the items of stream, from the parameters of a request:
+// their count (n), oldest first (r=o), exclusion of the read ones (xt), inclusion of the
+// starred ones only (it), oldest time in seconds (ot) and continuation (c).
+func streamQuery(form url.Values, stream string) (*rss.ReaderQuery, gtserror.WithCode) {
+	if stream == "" {
+		stream = rss.ReaderStreamReadingList
+	}
+
+	query := &rss.ReaderQuery{
+		Stream: rss.ReaderStream(stream),
+		Oldest: form.Get("r") == "o",
+		Limit:  defaultItemCount,
+	}
+
+	if n := form.Get("n"); n != "" {
+		count, err := strconv.Atoi(n)
+		if err != nil {
+			err := fmt.Errorf("invalid count %q", n)
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+		query.Limit = count
+	}
+
+	for _, exclude := range form["xt"] {
+		if rss.ReaderStream(exclude) == rss.ReaderStreamRead {
+			query.Unread = true
+		}
+	}
+
+	for _, include := range form["it"] {
+		if rss.ReaderStream(include) == rss.ReaderStreamStarred {
+			query.Stream = rss.ReaderStreamStarred
+		}
+	}
+
+	if ot := form.Get("ot"); ot != "" {
+		seconds, err := strconv.ParseInt(ot, 10, 64)
+		if err != nil {
+			err := fmt.Errorf("invalid oldest time %q", ot)
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+		query.MinID = rss.ReaderTimeID(time.Unix(seconds, 0))
+	}
+
+	// Continuations are the status ids to continue from.
+	if continuation := form.Get("c"); continuation != "" {
+		if !query.Oldest {
+			query.MaxID = continuation
+		} else if continuation > query.MinID {
+			query.MinID = continuation
+		}
+	}
+
+	return query, nil
+}
+
+// formItems returns the items of the ids of a request (i), in their long or short form.
+func (m *Module) formItems(ctx context.Context, account *gtsmodel.Account, form url.Values) ([]*rss.ReaderItem, gtserror.WithCode) {
+	itemIDs := make([]int64, 0, len(form["i"]))
+	for _, i := range form["i"] {
+		var (
+			itemID int64
+			err    error
+		)
+		if hexID, ok := strings.CutPrefix(i, itemIDPrefix); ok {
+			var unsigned uint64
+			unsigned, err = strconv.ParseUint(hexID, 16, 64)
+			itemID = int64(unsigned)
+		} else {
+			itemID, err = strconv.ParseInt(i, 10, 64)
+		}
+		if err != nil {
+			err := fmt.Errorf("invalid item id %q", i)
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+		itemIDs = append(itemIDs, itemID)
+	}
+
+	return m.rssTooter.ReaderItemsByIDs(ctx, account, itemIDs)
+}
+
+// subscriptionAccountID returns the id of the feed account of a feed stream,
+// with the id of the feed account or the url of a website or feed.
+func (m *Module) subscriptionAccountID(ctx context.Context, stream string) (string, gtserror.WithCode) {
+	target := strings.TrimPrefix(stream, rss.ReaderStreamFeed)
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		return target, nil
+	}
+
+	feedAccount, errWithCode := m.rssTooter.ReaderSubscribe(ctx, target)
+	if errWithCode != nil {
+		return "", errWithCode
+	}
+	return feedAccount.ID, nil
+}
+
+// labelList returns the list of account titled like label, creating it if create is set, nil if none.
+func (m *Module) labelList(ctx context.Context, account *gtsmodel.Account, label string, create bool) (*apimodel.List, gtserror.WithCode) {
+	title := strings.TrimPrefix(rss.ReaderStream(label), rss.ReaderStreamLabel)
+
+	lists, errWithCode := m.processor.List().GetAll(ctx, account)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+	for _, list := range lists {
+		if list.Title == title {
+			return list, nil
+		}
+	}
+
+	if !create || title == "" {
+		return nil, nil
+	}
+	return m.processor.List().Create(ctx, account, title, gtsmodel.RepliesPolicyFollowed)
+}
+
+// formLabelList returns the list of the label of a request (s or t).
+func (m *Module) formLabelList(ctx context.Context, account *gtsmodel.Account, form url.Values) (*apimodel.List, gtserror.WithCode) {
+	label := form.Get("s")
+	if label == "" {
+		label = rss.ReaderStreamLabel + form.Get("t")
+	}
+
+	list, errWithCode := m.labelList(ctx, account, label, false)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+	if list == nil {
+		err := fmt.Errorf("label %s not found", label)
+		return nil, gtserror.NewErrorNotFound(err, err.Error())
+	}
+	return list, nil
+}
+
+// addToLabel adds a feed account to the list of a label, creating it if needed.
+func (m *Module) addToLabel(ctx context.Context, account *gtsmodel.Account, label string, targetID string) gtserror.WithCode {
+	list, errWithCode := m.labelList(ctx, account, label, true)
+	if errWithCode != nil || list == nil {
+		return errWithCode
+	}
+
+	errWithCode = m.processor.List().AddToList(ctx, account, list.ID, []string{targetID})
+	if errWithCode != nil && errWithCode.Code() == http.StatusUnprocessableEntity {
+		// Already in the list.
+		return nil
+	}
+	return errWithCode
+}
+
+// star bookmarks the statuses of items, or removes their bookmark.
+func (m *Module) star(ctx context.Context, account *gtsmodel.Account, items []*rss.ReaderItem, starred bool) gtserror.WithCode {
+	for _, item := range items {
+		statusID := item.Status.ID
+		if item.Status.BoostOfID != "" {
+			statusID = item.Status.BoostOfID
+		}
+
+		var errWithCode gtserror.WithCode
+		if starred {
+			_, errWithCode = m.processor.Status().BookmarkCreate(ctx, account, statusID)
+		} else {
+			_, errWithCode = m.processor.Status().BookmarkRemove(ctx, account, statusID)
+		}
+		if errWithCode != nil {
+			return errWithCode
+		}
+	}
+	return nil
+}
+
+// markRead moves the home timeline marker to the newest of items.
+func (m *Module) markRead(ctx context.Context, account *gtsmodel.Account, items []*rss.ReaderItem) gtserror.WithCode {
+	var newest string
+	for _, item := range items {
+		if item.Status.ID > newest {
+			newest = item.Status.ID
+		}
+	}
+	if newest == "" {
+		return nil
+	}
+	return m.updateMarker(ctx, account, newest, true)
+}
+
+// markUnread moves the home timeline marker before the oldest of items.
+func (m *Module) markUnread(ctx context.Context, account *gtsmodel.Account, items []*rss.ReaderItem) gtserror.WithCode {
+	var oldest string
+	for _, item := range items {
+		if oldest == "" || item.Status.ID < oldest {
+			oldest = item.Status.ID
+		}
+	}
+	if oldest == "" {
+		return nil
+	}
+	return m.updateMarker(ctx, account, oldest, false)
+}
+
+// updateMarker moves the home timeline marker of account forward to statusID if read
+// is set, or back before it otherwise, unless the marker is past it already.
+func (m *Module) updateMarker(ctx context.Context, account *gtsmodel.Account, statusID string, read bool) gtserror.WithCode {
+	var (
+		marker      *gtsmodel.Marker
+		errWithCode gtserror.WithCode
+	)
+	if read {
+		marker, errWithCode = m.rssTooter.ReaderMarkRead(ctx, account, statusID)
+	} else {
+		marker, errWithCode = m.rssTooter.ReaderMarkUnread(ctx, account, statusID)
+	}
+	if errWithCode != nil || marker == nil {
+		return errWithCode
+	}
+
+	_, errWithCode = m.processor.Markers().Update(ctx, []*gtsmodel.Marker{marker})
+	return errWithCode
+}
+
+// addCount adds count to total, keeping the newest id.
+func addCount(total *rss.ReaderItemCount, count *rss.ReaderItemCount) {
+	total.Count += count.Count
+	if count.NewestID > total.NewestID {
+		total.NewestID = count.NewestID
+	}
+}
+
+// accountTitle returns the name of a feed account.
+func accountTitle(account *gtsmodel.Account) string {
+	if account.DisplayName != "" {
+		return account.DisplayName
+	}
+	return account.Username
+}
+
+func apiSubscription(subscription *rss.ReaderSubscription) *apimodel.ReaderSubscription {
+	categories := make([]*apimodel.ReaderCategory, 0, len(subscription.Lists))
+	for _, list := range subscription.Lists {
+		categories = append(categories, &apimodel.ReaderCategory{
+			ID:    rss.ReaderStreamLabel + list.Title,
+			Label: list.Title,
+		})
+	}
+
+	var iconURL string
+	if subscription.Account.AvatarMediaAttachment != nil {
+		iconURL = subscription.Account.AvatarMediaAttachment.URL
+	}
+
+	return &apimodel.ReaderSubscription{
+		ID:         rss.ReaderStreamFeed + subscription.Account.ID,
+		Title:      accountTitle(subscription.Account),
+		Categories: categories,
+		URL:        subscription.Feed.URL,
+		HTMLURL:    subscription.Account.URL,
+		IconURL:    iconURL,
+	}
+}
+
+func apiUnreadCount(stream string, count *rss.ReaderItemCount) *apimodel.ReaderUnreadCount {
+	var newest int64
+	if count.NewestID != "" {
+		newest = rss.ReaderIDTime(count.NewestID).UnixMicro()
+	}
+
+	return &apimodel.ReaderUnreadCount{
+		ID:                      stream,
+		Count:                   count.Count,
+		NewestItemTimestampUsec: strconv.FormatInt(newest, 10),
+	}
+}
+
+func apiStreamContents(stream string, items []*rss.ReaderItem, continuation string) *apimodel.ReaderStreamContents {
+	apiItems := make([]*apimodel.ReaderItem, 0, len(items))
+	for _, item := range items {
+		apiItems = append(apiItems, apiItem(item))
+	}
+
+	return &apimodel.ReaderStreamContents{
+		ID:           stream,
+		Updated:      time.Now().Unix(),
+		Items:        apiItems,
+		Continuation: continuation,
+	}
+}
+
+func apiItem(item *rss.ReaderItem) *apimodel.ReaderItem {
+	status := item.Status
+	content := status
+	if status.BoostOf != nil {
+		content = status.BoostOf
+	}
+
+	categories := []string{rss.ReaderStreamReadingList, rss.ReaderStreamFeed + status.AccountID}
+	if item.Read {
+		categories = append(categories, rss.ReaderStreamRead)
+	}
+	if item.Starred {
+		categories = append(categories, rss.ReaderStreamStarred)
+	}
+
+	links := make([]*apimodel.ReaderLink, 0, 1)
+	if content.URL != "" {
+		links = append(links, &apimodel.ReaderLink{Href: content.URL, Type: "text/html"})
+	}
+
+	var author string
+	if content.Account != nil {
+		author = accountTitle(content.Account)
+	}
+
+	origin := apimodel.ReaderOrigin{StreamID: rss.ReaderStreamFeed + status.AccountID}
+	if status.Account != nil {
+		origin.Title = accountTitle(status.Account)
+		origin.HTMLURL = status.Account.URL
+	}
+
+	received := rss.ReaderIDTime(status.ID)
+	return &apimodel.ReaderItem{
+		ID:            fmt.Sprintf("%s%016x", itemIDPrefix, item.ID),
+		CrawlTimeMsec: strconv.FormatInt(received.UnixMilli(), 10),
+		TimestampUsec: strconv.FormatInt(received.UnixMicro(), 10),
+		Published:     content.CreatedAt.Unix(),
+		Updated:       content.CreatedAt.Unix(),
+		Title:         item.Title,
+		Author:        author,
+		Categories:    categories,
+		Canonical:     links,
+		Alternate:     links,
+		Summary:       apimodel.ReaderContent{Content: item.Content},
+		Origin:        origin,
+	}
+}
@@ -0,0 +1,85 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package reader
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	"github.com/superseriousbusiness/gotosocial/internal/rss"
+)
+
+const (
+	// IDKey is for app password IDs
+	IDKey = "id"
+	// StreamKey is for the stream of the Google Reader API stream contents
+	StreamKey = "stream"
+	// AppPasswordsPath is the path for managing the app passwords of the authorized account, minus the 'api' prefix
+	AppPasswordsPath = "/v1/app_passwords"
+	// AppPasswordsPathWithID is the path for revoking an app password
+	AppPasswordsPathWithID = AppPasswordsPath + "/:" + IDKey
+	// GoogleReaderPath is the base path of the Google Reader API
+	GoogleReaderPath = "/greader"
+	// ClientLoginPath is the path apps log in to the Google Reader API at
+	ClientLoginPath = GoogleReaderPath + "/accounts/ClientLogin"
+	// ReaderAPIPath is the base path of the Google Reader API methods
+	ReaderAPIPath = GoogleReaderPath + "/reader/api/0"
+	// FeverPath is the path of the Fever API
+	FeverPath = "/fever"
+)
+
+type Module struct {
+	rssTooter rss.RssTooter
+	processor *processing.Processor
+}
+
+func New(rssTooter rss.RssTooter, processor *processing.Processor) *Module {
+	return &Module{
+		rssTooter: rssTooter,
+		processor: processor,
+	}
+}
+
+func (m *Module) Route(attachHandler func(method string, path string, f ...gin.HandlerFunc) gin.IRoutes) {
+	attachHandler(http.MethodGet, AppPasswordsPath, m.AppPasswordsGETHandler)
+	attachHandler(http.MethodPost, AppPasswordsPath, m.AppPasswordPOSTHandler)
+	attachHandler(http.MethodDelete, AppPasswordsPathWithID, m.AppPasswordDELETEHandler)
+
+	attachHandler(http.MethodGet, ClientLoginPath, m.ClientLoginHandler)
+	attachHandler(http.MethodPost, ClientLoginPath, m.ClientLoginHandler)
+	attachHandler(http.MethodGet, ReaderAPIPath+"/token", m.readerAuth, m.TokenGETHandler)
+	attachHandler(http.MethodGet, ReaderAPIPath+"/user-info", m.readerAuth, m.UserInfoGETHandler)
+	attachHandler(http.MethodGet, ReaderAPIPath+"/subscription/list", m.readerAuth, m.SubscriptionListGETHandler)
+	attachHandler(http.MethodPost, ReaderAPIPath+"/subscription/edit", m.readerAuth, m.SubscriptionEditPOSTHandler)
+	attachHandler(http.MethodPost, ReaderAPIPath+"/subscription/quickadd", m.readerAuth, m.QuickAddPOSTHandler)
+	attachHandler(http.MethodGet, ReaderAPIPath+"/tag/list", m.readerAuth, m.TagListGETHandler)
+	attachHandler(http.MethodPost, ReaderAPIPath+"/rename-tag", m.readerAuth, m.RenameTagPOSTHandler)
+	attachHandler(http.MethodPost, ReaderAPIPath+"/disable-tag", m.readerAuth, m.DisableTagPOSTHandler)
+	attachHandler(http.MethodGet, ReaderAPIPath+"/unread-count", m.readerAuth, m.UnreadCountGETHandler)
+	attachHandler(http.MethodGet, ReaderAPIPath+"/stream/items/ids", m.readerAuth, m.StreamItemIDsGETHandler)
+	attachHandler(http.MethodGet, ReaderAPIPath+"/stream/items/contents", m.readerAuth, m.StreamItemContentsHandler)
+	attachHandler(http.MethodPost, ReaderAPIPath+"/stream/items/contents", m.readerAuth, m.StreamItemContentsHandler)
+	attachHandler(http.MethodGet, ReaderAPIPath+"/stream/contents", m.readerAuth, m.StreamContentsGETHandler)
+	attachHandler(http.MethodGet, ReaderAPIPath+"/stream/contents/*"+StreamKey, m.readerAuth, m.StreamContentsGETHandler)
+	attachHandler(http.MethodPost, ReaderAPIPath+"/edit-tag", m.readerAuth, m.EditTagPOSTHandler)
+	attachHandler(http.MethodPost, ReaderAPIPath+"/mark-all-as-read", m.readerAuth, m.MarkAllAsReadPOSTHandler)
+
+	attachHandler(http.MethodGet, FeverPath, m.FeverHandler)
+	attachHandler(http.MethodPost, FeverPath, m.FeverHandler)
+}
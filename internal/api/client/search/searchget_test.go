@@ -22,6 +22,7 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -791,6 +792,112 @@ func (suite *SearchGetTestSuite) TestSearchStatusByURL() {
 	suite.NotNil(gotStatus)
 }
 
+// feedResolver resolves the urls of its map to the given feed account usernames.
+type feedResolver map[string]string
+
+func (f feedResolver) NewUser(ctx context.Context, resource string) (string, error) {
+	if username, ok := f[resource]; ok {
+		return username, nil
+	}
+	return "", errors.New("Can't find any feed")
+}
+
+func (suite *SearchGetTestSuite) TestSearchFeedByURL() {
+	var (
+		requestingAccount          = suite.testAccounts["local_account_1"]
+		token                      = suite.testTokens["local_account_1"]
+		user                       = suite.testUsers["local_account_1"]
+		maxID              *string = nil
+		minID              *string = nil
+		limit              *int    = nil
+		offset             *int    = nil
+		resolve            *bool   = func() *bool { i := true; return &i }()
+		query                      = "https://blog.example.org/"
+		queryType          *string = nil
+		following          *bool   = nil
+		fromAccountID      *string = nil
+		expectedHTTPStatus         = http.StatusOK
+		expectedBody               = ""
+	)
+
+	// The website isn't ActivityPub, so its feed account is returned.
+	suite.processor.Search().SetFeedResolver(feedResolver{
+		"https://blog.example.org/": suite.testAccounts["local_account_2"].Username,
+	})
+
+	searchResult, err := suite.getSearch(
+		requestingAccount,
+		token,
+		apiutil.APIv2,
+		user,
+		maxID,
+		minID,
+		limit,
+		offset,
+		query,
+		queryType,
+		resolve,
+		following,
+		fromAccountID,
+		expectedHTTPStatus,
+		expectedBody)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	if !suite.Len(searchResult.Accounts, 1) {
+		suite.FailNow("expected 1 account in search results but got 0")
+	}
+
+	suite.Equal(suite.testAccounts["local_account_2"].ID, searchResult.Accounts[0].ID)
+}
+
+func (suite *SearchGetTestSuite) TestSearchFeedByURLNoResolve() {
+	var (
+		requestingAccount          = suite.testAccounts["local_account_1"]
+		token                      = suite.testTokens["local_account_1"]
+		user                       = suite.testUsers["local_account_1"]
+		maxID              *string = nil
+		minID              *string = nil
+		limit              *int    = nil
+		offset             *int    = nil
+		resolve            *bool   = func() *bool { i := false; return &i }()
+		query                      = "https://blog.example.org/"
+		queryType          *string = nil
+		following          *bool   = nil
+		fromAccountID      *string = nil
+		expectedHTTPStatus         = http.StatusOK
+		expectedBody               = ""
+	)
+
+	// Feeds are only discovered when resolving.
+	suite.processor.Search().SetFeedResolver(feedResolver{
+		"https://blog.example.org/": suite.testAccounts["local_account_2"].Username,
+	})
+
+	searchResult, err := suite.getSearch(
+		requestingAccount,
+		token,
+		apiutil.APIv2,
+		user,
+		maxID,
+		minID,
+		limit,
+		offset,
+		query,
+		queryType,
+		resolve,
+		following,
+		fromAccountID,
+		expectedHTTPStatus,
+		expectedBody)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Len(searchResult.Accounts, 0)
+}
+
 func (suite *SearchGetTestSuite) TestSearchBlockedDomainURL() {
 	var (
 		requestingAccount          = suite.testAccounts["local_account_1"]
@@ -0,0 +1,118 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package timelinefeeds
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	apiutil "github.com/superseriousbusiness/gotosocial/internal/api/util"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+)
+
+// formatContentTypes are the content types of the feed documents, by format.
+var formatContentTypes = map[typeutils.FeedFormat]string{
+	typeutils.FeedFormatRSS:  apiutil.AppRSSXML + "; charset=utf-8",
+	typeutils.FeedFormatAtom: apiutil.AppAtomXML + "; charset=utf-8",
+	typeutils.FeedFormatJSON: apiutil.AppFeedJSON + "; charset=utf-8",
+}
+
+// TimelineFeedGETHandler swagger:operation GET /api/v1/timeline_feeds/{token}/{timeline} timelineFeedGet
+//
+// Get a timeline of the owner of a feed token as an RSS, Atom or JSON Feed document.
+//
+// The feed is authenticated by the token in its url rather than by OAuth, so that it can be
+// subscribed to from any feed reader: home.rss, public.atom, local.json, lists/{list_id}.rss
+// and tags/{tag_name}.rss are available, statuses being filtered as in the client timelines.
+//
+//	---
+//	tags:
+//	- timeline_feeds
+//
+//	produces:
+//	- application/rss+xml
+//	- application/atom+xml
+//	- application/feed+json
+//
+//	parameters:
+//	-
+//		name: token
+//		type: string
+//		description: Secret of a feed token.
+//		in: path
+//		required: true
+//	-
+//		name: timeline
+//		type: string
+//		description: >-
+//			home, public or local followed by the extension of the format (rss, atom or json),
+//			or lists / tags followed by the list id or tag name and the extension.
+//		in: path
+//		required: true
+//
+//	responses:
+//		'200':
+//			description: The feed document.
+//		'401':
+//			description: unauthorized
+//		'404':
+//			description: not found
+//		'500':
+//			description: internal server error
+func (m *Module) TimelineFeedGETHandler(c *gin.Context) {
+	var (
+		timeline = c.Param(TimelineKey)
+		name     = c.Param(NameKey)
+		document = timeline
+	)
+
+	if name != "" {
+		document = name
+	}
+
+	dot := strings.LastIndexByte(document, '.')
+	if dot < 0 {
+		err := errors.New("missing feed format extension")
+		apiutil.ErrorHandler(c, gtserror.NewErrorNotFound(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	format, ok := typeutils.ParseFeedFormat(document[dot+1:])
+	if !ok {
+		err := errors.New("unknown feed format extension " + document[dot+1:])
+		apiutil.ErrorHandler(c, gtserror.NewErrorNotFound(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	if name != "" {
+		name = name[:dot]
+	} else {
+		timeline = timeline[:dot]
+	}
+
+	feed, errWithCode := m.rssTooter.GetTimelineFeed(c.Request.Context(), c.Param(TokenKey), timeline, name, format)
+	if errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	c.Data(http.StatusOK, formatContentTypes[format], []byte(feed))
+}
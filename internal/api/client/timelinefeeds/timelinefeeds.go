@@ -0,0 +1,67 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package timelinefeeds
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	"github.com/superseriousbusiness/gotosocial/internal/rss"
+)
+
+const (
+	// IDKey is for feed token UUIDs
+	IDKey = "id"
+	// TokenKey is for the secret of a feed token
+	TokenKey = "token"
+	// TimelineKey is for the timeline of a feed, with its extension if it has no name
+	TimelineKey = "timeline"
+	// NameKey is for the list id or tag name of a feed, with its extension
+	NameKey = "name"
+	// BasePath is the base path for serving the timeline feeds API, minus the 'api' prefix
+	BasePath = "/v1/timeline_feeds"
+	// TokensPath is the path for managing the feed tokens of the authorized account
+	TokensPath = BasePath + "/tokens"
+	// TokensPathWithID is the path for revoking a feed token
+	TokensPathWithID = TokensPath + "/:" + IDKey
+	// FeedPath is the path of the home, public and local timeline feeds
+	FeedPath = BasePath + "/:" + TokenKey + "/:" + TimelineKey
+	// FeedPathWithName is the path of the list and tag timeline feeds
+	FeedPathWithName = FeedPath + "/:" + NameKey
+)
+
+type Module struct {
+	rssTooter rss.RssTooter
+	processor *processing.Processor
+}
+
+func New(rssTooter rss.RssTooter, processor *processing.Processor) *Module {
+	return &Module{
+		rssTooter: rssTooter,
+		processor: processor,
+	}
+}
+
+func (m *Module) Route(attachHandler func(method string, path string, f ...gin.HandlerFunc) gin.IRoutes) {
+	attachHandler(http.MethodGet, TokensPath, m.TokensGETHandler)
+	attachHandler(http.MethodPost, TokensPath, m.TokenPOSTHandler)
+	attachHandler(http.MethodDelete, TokensPathWithID, m.TokenDELETEHandler)
+	attachHandler(http.MethodGet, FeedPath, m.TimelineFeedGETHandler)
+	attachHandler(http.MethodGet, FeedPathWithName, m.TimelineFeedGETHandler)
+}
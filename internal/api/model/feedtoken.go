@@ -0,0 +1,40 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+// FeedToken models a secret giving feed readers access to the timeline feeds of an account.
+//
+// swagger:model feedToken
+type FeedToken struct {
+	// The ID of the token.
+	// example: 01FBVD42CQ3ZEEVMW180SBX03B
+	ID string `json:"id"`
+	// When the token was created (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	CreatedAt string `json:"created_at"`
+	// The secret token.
+	// Only returned when the token is created.
+	// example: 4zk1vb5gq8b2k1m0y7c3f6r9n2h8d4x5
+	Token string `json:"token,omitempty"`
+	// Base url of the timeline feeds accessed with this token. Append
+	// home.rss, public.atom, local.json, lists/{list_id}.rss or
+	// tags/{tag_name}.rss to get a feed.
+	// Only returned when the token is created.
+	// example: https://example.org/api/v1/timeline_feeds/4zk1vb5gq8b2k1m0y7c3f6r9n2h8d4x5
+	URL string `json:"url,omitempty"`
+}
@@ -0,0 +1,243 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+// AppPassword models a password for the feed reader apps speaking the Google Reader or Fever API.
+//
+// swagger:model appPassword
+type AppPassword struct {
+	// The ID of the app password.
+	// example: 01FBVD42CQ3ZEEVMW180SBX03B
+	ID string `json:"id"`
+	// When the app password was created (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	CreatedAt string `json:"created_at"`
+	// Name given to the app password, to remember the app using it.
+	// example: Reeder on my phone
+	Name string `json:"name"`
+	// The password, to log in with the username of the account.
+	// Only returned when the app password is created.
+	// example: 4zk1vb5gq8b2k1m0y7c3f6r9n2h8d4x5
+	Password string `json:"password,omitempty"`
+	// Url of the Google Reader API, to give to apps asking for a server url.
+	// example: https://example.org/api/greader
+	GoogleReaderURL string `json:"google_reader_url"`
+	// Url of the Fever API, to give to apps asking for a server url.
+	// example: https://example.org/api/fever
+	FeverURL string `json:"fever_url"`
+}
+
+// AppPasswordCreateRequest models a request to create an app password.
+//
+// swagger:ignore
+type AppPasswordCreateRequest struct {
+	// Name given to the app password.
+	Name string `form:"name" json:"name" xml:"name"`
+}
+
+// ReaderUserInfo models the user of the Google Reader API.
+//
+// swagger:ignore
+type ReaderUserInfo struct {
+	UserID        string `json:"userId"`
+	UserName      string `json:"userName"`
+	UserProfileID string `json:"userProfileId"`
+	UserEmail     string `json:"userEmail"`
+}
+
+// ReaderSubscriptionList models the subscriptions of the Google Reader API.
+//
+// swagger:ignore
+type ReaderSubscriptionList struct {
+	Subscriptions []*ReaderSubscription `json:"subscriptions"`
+}
+
+// ReaderSubscription models a subscription of the Google Reader API.
+//
+// swagger:ignore
+type ReaderSubscription struct {
+	ID         string            `json:"id"`
+	Title      string            `json:"title"`
+	Categories []*ReaderCategory `json:"categories"`
+	URL        string            `json:"url"`
+	HTMLURL    string            `json:"htmlUrl"`
+	IconURL    string            `json:"iconUrl"`
+}
+
+// ReaderCategory models a folder of subscriptions of the Google Reader API.
+//
+// swagger:ignore
+type ReaderCategory struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+// ReaderTagList models the folders and states of the Google Reader API.
+//
+// swagger:ignore
+type ReaderTagList struct {
+	Tags []*ReaderTag `json:"tags"`
+}
+
+// ReaderTag models a folder or state of the Google Reader API.
+//
+// swagger:ignore
+type ReaderTag struct {
+	ID   string `json:"id"`
+	Type string `json:"type,omitempty"`
+}
+
+// ReaderUnreadCounts models the unread counts of the Google Reader API.
+//
+// swagger:ignore
+type ReaderUnreadCounts struct {
+	Max          int                  `json:"max"`
+	UnreadCounts []*ReaderUnreadCount `json:"unreadcounts"`
+}
+
+// ReaderUnreadCount models the unread count of a stream of the Google Reader API.
+//
+// swagger:ignore
+type ReaderUnreadCount struct {
+	ID                      string `json:"id"`
+	Count                   int    `json:"count"`
+	NewestItemTimestampUsec string `json:"newestItemTimestampUsec"`
+}
+
+// ReaderItemRefs models the item ids of a stream of the Google Reader API.
+//
+// swagger:ignore
+type ReaderItemRefs struct {
+	ItemRefs     []*ReaderItemRef `json:"itemRefs"`
+	Continuation string           `json:"continuation,omitempty"`
+}
+
+// ReaderItemRef models an item id of the Google Reader API.
+//
+// swagger:ignore
+type ReaderItemRef struct {
+	ID              string   `json:"id"`
+	DirectStreamIDs []string `json:"directStreamIds"`
+	TimestampUsec   string   `json:"timestampUsec"`
+}
+
+// ReaderStreamContents models the items of a stream of the Google Reader API.
+//
+// swagger:ignore
+type ReaderStreamContents struct {
+	ID           string        `json:"id"`
+	Updated      int64         `json:"updated"`
+	Items        []*ReaderItem `json:"items"`
+	Continuation string        `json:"continuation,omitempty"`
+}
+
+// ReaderItem models an item of the Google Reader API.
+//
+// swagger:ignore
+type ReaderItem struct {
+	ID            string        `json:"id"`
+	CrawlTimeMsec string        `json:"crawlTimeMsec"`
+	TimestampUsec string        `json:"timestampUsec"`
+	Published     int64         `json:"published"`
+	Updated       int64         `json:"updated"`
+	Title         string        `json:"title"`
+	Author        string        `json:"author"`
+	Categories    []string      `json:"categories"`
+	Canonical     []*ReaderLink `json:"canonical"`
+	Alternate     []*ReaderLink `json:"alternate"`
+	Summary       ReaderContent `json:"summary"`
+	Origin        ReaderOrigin  `json:"origin"`
+}
+
+// ReaderLink models a link of an item of the Google Reader API.
+//
+// swagger:ignore
+type ReaderLink struct {
+	Href string `json:"href"`
+	Type string `json:"type,omitempty"`
+}
+
+// ReaderContent models the content of an item of the Google Reader API.
+//
+// swagger:ignore
+type ReaderContent struct {
+	Content string `json:"content"`
+}
+
+// ReaderOrigin models the subscription of an item of the Google Reader API.
+//
+// swagger:ignore
+type ReaderOrigin struct {
+	StreamID string `json:"streamId"`
+	Title    string `json:"title"`
+	HTMLURL  string `json:"htmlUrl"`
+}
+
+// ReaderQuickAdd models the result of a subscription by url of the Google Reader API.
+//
+// swagger:ignore
+type ReaderQuickAdd struct {
+	NumResults int    `json:"numResults"`
+	Query      string `json:"query"`
+	StreamID   string `json:"streamId,omitempty"`
+	StreamName string `json:"streamName,omitempty"`
+}
+
+// FeverGroup models a group of feeds of the Fever API.
+//
+// swagger:ignore
+type FeverGroup struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+// FeverFeedsGroup models the feeds of a group of the Fever API.
+//
+// swagger:ignore
+type FeverFeedsGroup struct {
+	GroupID int64  `json:"group_id"`
+	FeedIDs string `json:"feed_ids"`
+}
+
+// FeverFeed models a feed of the Fever API.
+//
+// swagger:ignore
+type FeverFeed struct {
+	ID                int64  `json:"id"`
+	FaviconID         int64  `json:"favicon_id"`
+	Title             string `json:"title"`
+	URL               string `json:"url"`
+	SiteURL           string `json:"site_url"`
+	IsSpark           int    `json:"is_spark"`
+	LastUpdatedOnTime int64  `json:"last_updated_on_time"`
+}
+
+// FeverItem models an item of the Fever API.
+//
+// swagger:ignore
+type FeverItem struct {
+	ID            int64  `json:"id"`
+	FeedID        int64  `json:"feed_id"`
+	Title         string `json:"title"`
+	Author        string `json:"author"`
+	HTML          string `json:"html"`
+	URL           string `json:"url"`
+	IsSaved       int    `json:"is_saved"`
+	IsRead        int    `json:"is_read"`
+	CreatedOnTime int64  `json:"created_on_time"`
+}
@@ -0,0 +1,351 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// RssFeed models the source feed of a feed account, and its settings.
+//
+// swagger:model rssFeed
+type RssFeed struct {
+	// The ID of the feed account.
+	// example: 01FBVD42CQ3ZEEVMW180SBX03B
+	AccountID string `json:"account_id"`
+	// Url of the polled feed.
+	// example: https://xkcd.com/atom.xml
+	URL string `json:"url"`
+	// Publication date of the most recent ingested item (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	LastItemAt *string `json:"last_item_at"`
+	// Period of the digest gathering new items in a single status.
+	// Empty if every item is published as its own status.
+	// example: daily
+	Digest string `json:"digest"`
+	// Visibility of the statuses created from the feed items.
+	// example: unlisted
+	Visibility Visibility `json:"visibility"`
+	// Statuses created from the feed items are not federated.
+	LocalOnly bool `json:"local_only"`
+	// Statuses created from the feed items are marked as sensitive.
+	Sensitive bool `json:"sensitive"`
+	// Content warning text of the statuses created from the feed items.
+	SpoilerText string `json:"spoiler_text"`
+	// The title of the items is used as content warning of their status.
+	SpoilerFromTitle bool `json:"spoiler_from_title"`
+	// Statuses created from the feed items can be boosted.
+	Boostable bool `json:"boostable"`
+	// Statuses created from the feed items can be liked.
+	Likeable bool `json:"likeable"`
+	// Statuses created from the feed items can be replied to.
+	Replyable bool `json:"replyable"`
+	// Url receiving a POST request for each reply to a feed status.
+	// example: https://blog.example.org/fediverse-replies
+	ReplyWebhookURL string `json:"reply_webhook_url"`
+	// Key of the HMAC-SHA256 signature of the reply webhook request bodies,
+	// sent hex encoded in the X-Feed-Signature header as sha256=<signature>.
+	// example: 6IOL3E6BWHPGJNWQ2W7CVSYU5DEBS7LT
+	ReplyWebhookSecret string `json:"reply_webhook_secret"`
+	// Email address receiving each reply to a feed status.
+	// example: blog@example.org
+	ReplyEmail string `json:"reply_email"`
+	// The authors of the items are credited in a byline of their status.
+	Byline bool `json:"byline"`
+	// Fediverse handles of the item authors, by author name,
+	// mentioned in the byline of their items.
+	AuthorHandles map[string]string `json:"author_handles"`
+	// Selectors of the items of the web page at url, for websites without
+	// any feed. Null for regular feeds.
+	Scraper *RssScraper `json:"scraper"`
+	// Days the statuses are kept for, by their publication date, 0 forever.
+	// Bookmarked, faved and pinned statuses are kept anyway.
+	// example: 90
+	RetentionDays int `json:"retention_days"`
+	// Number of most recent statuses kept, 0 all of them.
+	// Bookmarked, faved and pinned statuses are kept anyway.
+	// example: 500
+	RetentionItems int `json:"retention_items"`
+}
+
+// RssScraper models the selectors of the items of a web page without any feed.
+// Selectors are XPath expressions when they start with "/", "." or "(", and
+// CSS selectors otherwise. All but items are evaluated in the item container.
+//
+// swagger:model rssScraper
+type RssScraper struct {
+	// Selector of the item containers in the page.
+	// example: article.post
+	Items string `form:"items" json:"items"`
+	// Selector of the item title.
+	// example: h2
+	Title string `form:"title" json:"title"`
+	// Selector of the item link (its href), the first link of the item if empty.
+	// example: h2 a
+	Link string `form:"link" json:"link"`
+	// Selector of the item date (its datetime attribute or its text), optional.
+	// Items without a date are dated when first seen.
+	// example: time
+	Date string `form:"date" json:"date"`
+	// Selector of the item summary, optional.
+	// example: ./p[1]
+	Summary string `form:"summary" json:"summary"`
+}
+
+// RssScrapedItem models an item read from a web page by a scraper.
+//
+// swagger:model rssScrapedItem
+type RssScrapedItem struct {
+	// Title of the item.
+	// example: My first century ride
+	Title string `json:"title"`
+	// Link of the item.
+	// example: https://blog.example.org/century
+	Link string `json:"link"`
+	// Date of the item (ISO 8601 Datetime), null if none.
+	// example: 2021-07-30T09:20:25+00:00
+	Date *string `json:"date"`
+	// Summary of the item.
+	// example: A hundred miles on a sunny day
+	Summary string `json:"summary"`
+}
+
+// RssScrapedFeedRequest models a scraped feed, to create or to preview.
+//
+// swagger:ignore
+type RssScrapedFeedRequest struct {
+	RssScraper
+	// Url of the web page.
+	URL string `form:"url" json:"url"`
+	// Saved html of the web page, previewed instead of fetching the url.
+	HTML string `form:"html" json:"html"`
+}
+
+// RssFeedClaim models the request of a user to own a feed account.
+//
+// swagger:model rssFeedClaim
+type RssFeedClaim struct {
+	// The ID of the feed account.
+	// example: 01FBVD42CQ3ZEEVMW180SBX03B
+	AccountID string `json:"account_id"`
+	// Token to publish in a <meta name="rsstooter-claim"> tag of the feed
+	// website, or in a <link rel="rsstooter-claim" href="..."> or <meta
+	// name="rsstooter-claim" content="..."> element of the feed channel.
+	// example: rsstooter-claim-4zk1vb5gq8b2k1m0y7c3f6r9n2h8d4x5
+	Token string `json:"token"`
+	// When the token was found and the claim verified (ISO 8601 Datetime),
+	// null while the claim is pending.
+	// example: 2021-07-30T09:20:25+00:00
+	VerifiedAt *string `json:"verified_at"`
+	// When the claim expires if its token isn't found (ISO 8601 Datetime),
+	// null once the claim is verified.
+	// example: 2021-08-06T09:20:25+00:00
+	ExpiresAt *string `json:"expires_at"`
+}
+
+// RssNewsletter models an email newsletter published by a proxy account.
+//
+// swagger:model rssNewsletter
+type RssNewsletter struct {
+	// The account publishing the newsletter.
+	Account *Account `json:"account"`
+	// Email address to subscribe to the newsletter with.
+	// example: weekly.4zk1vb5g@newsletters.example.org
+	Address string `json:"address"`
+	// List-Unsubscribe link of the latest message, empty if none.
+	// example: https://newsletter.example.org/unsubscribe?id=1234
+	UnsubscribeURL string `json:"unsubscribe_url"`
+	// Link of the latest subscription confirmation message, to be visited to
+	// confirm the subscription. Confirmation messages are not published.
+	// example: https://newsletter.example.org/confirm?id=1234
+	ConfirmURL string `json:"confirm_url"`
+	// When the latest message was received (ISO 8601 Datetime), null if none.
+	// example: 2021-07-30T09:20:25+00:00
+	LastMessageAt *string `json:"last_message_at"`
+}
+
+// RssNewsletterCreateRequest models the creation of a newsletter account.
+//
+// swagger:ignore
+type RssNewsletterCreateRequest struct {
+	// Username of the newsletter account.
+	Username string `form:"username" json:"username"`
+	// Display name of the newsletter account.
+	DisplayName string `form:"display_name" json:"display_name"`
+	// Description of the newsletter account.
+	Note string `form:"note" json:"note"`
+}
+
+// RssFeedUpdateRequest models an update of the settings of a feed.
+//
+// The settings falling back to a server default are reset to
+// it by a null value, or an empty value in a form request.
+//
+// swagger:ignore
+type RssFeedUpdateRequest struct {
+	// Period of the feed digest: empty string, hourly, daily or weekly.
+	Digest *string `form:"digest" json:"digest"`
+	// Visibility of the statuses: public, unlisted or private,
+	// empty string to reset it to the server default.
+	Visibility RssSetting[string] `form:"visibility" json:"visibility"`
+	// Do not federate the statuses.
+	LocalOnly RssSetting[bool] `form:"local_only" json:"local_only"`
+	// Mark the statuses as sensitive.
+	Sensitive RssSetting[bool] `form:"sensitive" json:"sensitive"`
+	// Content warning text of the statuses.
+	SpoilerText RssSetting[string] `form:"spoiler_text" json:"spoiler_text"`
+	// Use the item title as content warning.
+	SpoilerFromTitle RssSetting[bool] `form:"spoiler_from_title" json:"spoiler_from_title"`
+	// Allow the statuses to be boosted.
+	Boostable RssSetting[bool] `form:"boostable" json:"boostable"`
+	// Allow the statuses to be liked.
+	Likeable RssSetting[bool] `form:"likeable" json:"likeable"`
+	// Allow the statuses to be replied to.
+	Replyable RssSetting[bool] `form:"replyable" json:"replyable"`
+	// Url receiving the replies, empty string to disable.
+	ReplyWebhookURL *string `form:"reply_webhook_url" json:"reply_webhook_url"`
+	// Email address receiving the replies, empty string to disable.
+	ReplyEmail *string `form:"reply_email" json:"reply_email"`
+	// Credit the item authors in a byline.
+	Byline RssSetting[bool] `form:"byline" json:"byline"`
+	// Fediverse handles (@user@domain) of the item authors, by author
+	// name, replacing the previous ones. JSON requests only.
+	AuthorHandles map[string]string `form:"-" json:"author_handles"`
+	// Days the statuses are kept for, 0 forever.
+	RetentionDays RssSetting[int] `form:"retention_days" json:"retention_days"`
+	// Number of most recent statuses kept, 0 all of them.
+	RetentionItems RssSetting[int] `form:"retention_items" json:"retention_items"`
+}
+
+// RssSetting is a feed setting of an update request, which
+// tells apart a setting left out from a setting being reset.
+//
+// swagger:ignore
+type RssSetting[T string | bool | int] struct {
+	// Set is true if the setting is part of the request.
+	Set bool
+	// Value of the setting, nil to reset it to the server default.
+	Value *T
+}
+
+// UnmarshalJSON implements json.Unmarshaler, null resetting the setting.
+func (s *RssSetting[T]) UnmarshalJSON(data []byte) error {
+	s.Set = true
+	if string(data) == "null" {
+		s.Value = nil
+		return nil
+	}
+
+	s.Value = new(T)
+	return json.Unmarshal(data, s.Value)
+}
+
+// UnmarshalParam implements binding.BindUnmarshaler
+// for form requests, an empty value resetting the setting.
+func (s *RssSetting[T]) UnmarshalParam(param string) error {
+	s.Set = true
+	if param == "" {
+		s.Value = nil
+		return nil
+	}
+
+	s.Value = new(T)
+	switch v := any(s.Value).(type) {
+	case *string:
+		*v = param
+	case *bool:
+		b, err := strconv.ParseBool(param)
+		if err != nil {
+			return err
+		}
+		*v = b
+	case *int:
+		i, err := strconv.Atoi(param)
+		if err != nil {
+			return err
+		}
+		*v = i
+	}
+
+	return nil
+}
+
+// RssWebhook models a url receiving a signed POST for each status posted from the
+// items of a feed account, or of every feed account when server-wide.
+//
+// swagger:model rssWebhook
+type RssWebhook struct {
+	// The ID of the webhook.
+	// example: 01FBVD42CQ3ZEEVMW180SBX03B
+	ID string `json:"id"`
+	// When the webhook was created (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	CreatedAt string `json:"created_at"`
+	// ID of the feed account, null for a server-wide webhook.
+	// example: 01FBVD42CQ3ZEEVMW180SBX03B
+	AccountID *string `json:"account_id"`
+	// Url receiving the POST requests.
+	// example: https://chat.example.org/hooks/feeds
+	URL string `json:"url"`
+	// Key of the HMAC-SHA256 signature of the request bodies,
+	// sent hex encoded in the X-Feed-Signature header as sha256=<signature>.
+	// example: 6IOL3E6BWHPGJNWQ2W7CVSYU5DEBS7LT
+	Secret string `json:"secret"`
+}
+
+// RssWebhookCreateRequest models the creation of a webhook.
+//
+// swagger:ignore
+type RssWebhookCreateRequest struct {
+	// Url receiving the POST requests.
+	URL string `form:"url" json:"url"`
+}
+
+// RssWebhookDelivery models a POST to a webhook, given up on after failing repeatedly.
+//
+// swagger:model rssWebhookDelivery
+type RssWebhookDelivery struct {
+	// The ID of the delivery.
+	// example: 01FBVD42CQ3ZEEVMW180SBX03B
+	ID string `json:"id"`
+	// When the status was posted (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	CreatedAt string `json:"created_at"`
+	// The webhook, with the feed account id, url and secret
+	// of the reply webhook for reply.created deliveries.
+	Webhook *RssWebhook `json:"webhook"`
+	// Event delivered, status.created or reply.created.
+	// example: status.created
+	Event string `json:"event"`
+	// ID of the posted status, or of the forwarded reply.
+	// example: 01FBVD42CQ3ZEEVMW180SBX03B
+	StatusID string `json:"status_id"`
+	// Number of failed attempts.
+	// example: 8
+	Attempts int `json:"attempts"`
+	// When the last attempt failed (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	LastAttemptAt string `json:"last_attempt_at"`
+	// Error of the last attempt.
+	// example: webhook returned 502 Bad Gateway
+	LastError string `json:"last_error"`
+	// When the delivery was given up on (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	FailedAt string `json:"failed_at"`
+}
@@ -26,6 +26,8 @@ const (
 	appXMLText        = `text/xml` // AppXML is only *recommended* in RFC7303
 	AppXMLXRD         = `application/xrd+xml`
 	AppRSSXML         = `application/rss+xml`
+	AppAtomXML        = `application/atom+xml`
+	AppFeedJSON       = `application/feed+json` // https://www.jsonfeed.org/version/1.1/
 	AppActivityJSON   = `application/activity+json`
 	appActivityLDJSON = `application/ld+json` // without profile
 	AppActivityLDJSON = appActivityLDJSON + `; profile="https://www.w3.org/ns/activitystreams"`
@@ -35,6 +37,7 @@ const (
 	TextXML           = `text/xml`
 	TextHTML          = `text/html`
 	TextCSS           = `text/css`
+	TextPlain         = `text/plain`
 )
 
 // JSONContentType returns whether is application/json(;charset=utf-8)? content-type.
@@ -63,7 +63,12 @@ const (
 
 	/* Web endpoint keys */
 
-	WebStatusIDKey = "status"
+	WebStatusIDKey      = "status"
+	WebFeedRepliesKey   = "replies"
+	WebFeedBoostsKey    = "boosts"
+	WebFeedMediaOnlyKey = "media_only"
+	WebFeedTagKey       = "tag"
+	WebFeedSummaryKey   = "summary"
 
 	/* Domain permission keys */
 
@@ -188,6 +193,22 @@ func ParseAdminStaff(value string, defaultValue bool) (bool, gtserror.WithCode)
 	return parseBool(value, defaultValue, AdminStaffKey)
 }
 
+func ParseWebFeedReplies(value string, defaultValue bool) (bool, gtserror.WithCode) {
+	return parseBool(value, defaultValue, WebFeedRepliesKey)
+}
+
+func ParseWebFeedBoosts(value string, defaultValue bool) (bool, gtserror.WithCode) {
+	return parseBool(value, defaultValue, WebFeedBoostsKey)
+}
+
+func ParseWebFeedMediaOnly(value string, defaultValue bool) (bool, gtserror.WithCode) {
+	return parseBool(value, defaultValue, WebFeedMediaOnlyKey)
+}
+
+func ParseWebFeedSummary(value string, defaultValue bool) (bool, gtserror.WithCode) {
+	return parseBool(value, defaultValue, WebFeedSummaryKey)
+}
+
 /*
 	Parse functions for *REQUIRED* parameters.
 */
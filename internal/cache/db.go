@@ -1089,6 +1089,7 @@ func (c *Caches) initStatus() {
 		s2.BoostOf = nil
 		s2.BoostOfAccount = nil
 		s2.Poll = nil
+		s2.PreviewCard = nil
 		s2.Attachments = nil
 		s2.Tags = nil
 		s2.Mentions = nil
@@ -374,6 +374,15 @@ func (m *Media) pruneUnused(ctx context.Context, media *gtsmodel.MediaAttachment
 		}
 	}
 
+	// Check whether used as the image of a preview card.
+	cardImage, err := m.state.DB.IsPreviewCardImage(ctx, media.ID)
+	if err != nil {
+		return false, gtserror.Newf("error checking preview card image %s: %w", media.ID, err)
+	} else if cardImage {
+		l.Debug("skipping as preview card image")
+		return false, nil
+	}
+
 	// Media totally unused, delete it.
 	l.Debug("deleting unused media")
 	return true, m.delete(ctx, media)
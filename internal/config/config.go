@@ -165,9 +165,31 @@ type Configuration struct {
 	AdvancedCSPExtraURIs         []string      `name:"advanced-csp-extra-uris" usage:"Additional URIs to allow when building content-security-policy for media + images."`
 	AdvancedHeaderFilterMode     string        `name:"advanced-header-filter-mode" usage:"Set incoming request header filtering mode."`
 
-	RssUserPassword     string   	`name:"rss-user-password" usage:"Password to use for the created user"`
 	RssPollFrequency    int   		`name:"rss-poll-frequency" usage:"Polling frequency in minutes"`
 
+	RssBackfillCount                  int    `name:"rss-backfill-count" usage:"Number of existing feed items imported as statuses when a feed account is created"`
+	RssBackfillArchivePages           int    `name:"rss-backfill-archive-pages" usage:"Maximum number of RFC 5005 paged or archived feed documents followed when backfilling"`
+	RssFetchComments                  bool   `name:"rss-fetch-comments" usage:"Ingest the comment feeds (wfw:commentRss) of feed items as replies to the item status"`
+	RssProfileRefreshFrequency        int    `name:"rss-profile-refresh-frequency" usage:"Refresh frequency of the feed accounts profile (name, description, avatar, header) in hours, 0 to disable"`
+	RssFetchAuthorHandles             bool   `name:"rss-fetch-author-handles" usage:"Read the fediverse:creator meta tag of item pages to credit their author, mentioned if their account lists the website among its attribution domains"`
+	RssPreviewCards                   bool   `name:"rss-preview-cards" usage:"Attach a preview card, read from the OpenGraph and Twitter card metadata of the item page, to feed statuses"`
+	RssPreviewCardsLocal              bool   `name:"rss-preview-cards-local" usage:"Also attach a preview card of their first link to the statuses of local accounts"`
+	RssDefaultVisibility              string `name:"rss-default-visibility" usage:"Default visibility of feed statuses: public, unlisted or private"`
+	RssDefaultLocalOnly               bool   `name:"rss-default-local-only" usage:"Do not federate feed statuses by default"`
+	RssDefaultSensitive               bool   `name:"rss-default-sensitive" usage:"Mark feed statuses media as sensitive by default"`
+	RssDefaultContentWarning          string `name:"rss-default-content-warning" usage:"Default content warning text of feed statuses"`
+	RssDefaultContentWarningFromTitle bool   `name:"rss-default-content-warning-from-title" usage:"Use the item title as content warning of feed statuses by default"`
+	RssDefaultBoostable               bool   `name:"rss-default-boostable" usage:"Allow feed statuses to be boosted by default"`
+	RssDefaultLikeable                bool   `name:"rss-default-likeable" usage:"Allow feed statuses to be liked by default"`
+	RssDefaultReplyable               bool   `name:"rss-default-replyable" usage:"Allow replies to feed statuses by default"`
+	RssDefaultByline                  bool   `name:"rss-default-byline" usage:"Credit the item authors in a byline of feed statuses by default"`
+	RssDefaultRetentionDays           int    `name:"rss-default-retention-days" usage:"Delete feed statuses older than this number of days by default, 0 to keep them"`
+	RssDefaultRetentionItems          int    `name:"rss-default-retention-items" usage:"Delete feed statuses beyond the most recent ones of each feed by default, 0 to keep them"`
+	RssDeduplicate                    bool   `name:"rss-deduplicate" usage:"Boost the status of an article already posted by another feed account instead of posting it again"`
+	RssDeduplicateCanonical           bool   `name:"rss-deduplicate-canonical" usage:"Fetch the page of new items to read their rel=canonical url when looking for duplicate articles"`
+	RssNewsletterMaildir              string `name:"rss-newsletter-maildir" usage:"Maildir receiving the email newsletters published by newsletter accounts, empty to disable"`
+	RssNewsletterDomain               string `name:"rss-newsletter-domain" usage:"Domain of the email addresses of newsletter accounts, defaults to the host"`
+
 	// HTTPClient configuration vars.
 	HTTPClient HTTPClientConfiguration `name:"http-client"`
 
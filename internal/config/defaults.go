@@ -136,6 +136,32 @@ var Defaults = Configuration{
 	AdvancedCSPExtraURIs:         []string{},
 	AdvancedHeaderFilterMode:     RequestHeaderFilterModeDisabled,
 
+	RssBackfillCount:           20,
+	RssBackfillArchivePages:    0,
+	RssFetchComments:           false,
+	RssProfileRefreshFrequency: 24,
+	RssFetchAuthorHandles:      false,
+	RssPreviewCards:            true,
+	RssPreviewCardsLocal:       false,
+
+	RssDefaultVisibility:              "public",
+	RssDefaultLocalOnly:               false,
+	RssDefaultSensitive:               false,
+	RssDefaultContentWarning:          "",
+	RssDefaultContentWarningFromTitle: false,
+	RssDefaultBoostable:               true,
+	RssDefaultLikeable:                true,
+	RssDefaultReplyable:               true,
+	RssDefaultByline:                  true,
+	RssDefaultRetentionDays:           0,
+	RssDefaultRetentionItems:          0,
+
+	RssDeduplicate:          true,
+	RssDeduplicateCanonical: true,
+
+	RssNewsletterMaildir: "",
+	RssNewsletterDomain:  "",
+
 	Cache: CacheConfiguration{
 		// Rough memory target that the total
 		// size of all State.Caches will attempt
@@ -4075,52 +4075,579 @@ func GetRequestIDHeader() string { return global.GetRequestIDHeader() }
 // SetRequestIDHeader safely sets the value for global configuration 'RequestIDHeader' field
 func SetRequestIDHeader(v string) { global.SetRequestIDHeader(v) }
 
-// GetRssUserPassword safely fetches the Configuration value for state's 'RssUserPassword' field
-func (st *ConfigState) GetRssUserPassword() (v string) {
+// GetRssPollFrequency safely fetches the Configuration value for state's 'RssPollFrequency' field
+func (st *ConfigState) GetRssPollFrequency() (v int) {
 	st.mutex.Lock()
-	v = st.config.RssUserPassword
+	v = st.config.RssPollFrequency
 	st.mutex.Unlock()
 	return
 }
 
-// SetRssUserPassword safely sets the Configuration value for state's 'RssUserPassword' field
-func (st *ConfigState) SetRssUserPassword(v string) {
+// SetRssPollFrequency safely sets the Configuration value for state's 'RssPollFrequency' field
+func (st *ConfigState) SetRssPollFrequency(v int) {
 	st.mutex.Lock()
 	defer st.mutex.Unlock()
-	st.config.RssUserPassword = v
+	st.config.RssPollFrequency = v
 	st.reloadToViper()
 }
 
-// RssUserPasswordFlag returns the flag name for the 'RssUserPassword' field
-func RssUserPasswordFlag() string { return "napp-user-password" }
+// RssPollFrequencyFlag returns the flag name for the 'RssPollFrequency' field
+func RssPollFrequencyFlag() string { return "napp-poll-frequency" }
 
-// GetRssUserPassword safely fetches the value for global configuration 'RssUserPassword' field
-func GetRssUserPassword() string { return global.GetRssUserPassword() }
+// GetRssPollFrequency safely fetches the value for global configuration 'RssPollFrequency' field
+func GetRssPollFrequency() int { return global.GetRssPollFrequency() }
 
-// SetRssUserPassword safely sets the value for global configuration 'RssUserPassword' field
-func SetRssUserPassword(v string) { global.SetRssUserPassword(v) }
+// SetRssPollFrequency safely sets the value for global configuration 'RssPollFrequency' field
+func SetRssPollFrequency(v int) { global.SetRssPollFrequency(v) }
 
-// GetRssPollFrequency safely fetches the Configuration value for state's 'RssPollFrequency' field
-func (st *ConfigState) GetRssPollFrequency() (v int) {
+// GetRssBackfillCount safely fetches the Configuration value for state's 'RssBackfillCount' field
+func (st *ConfigState) GetRssBackfillCount() (v int) {
 	st.mutex.Lock()
-	v = st.config.RssPollFrequency
+	v = st.config.RssBackfillCount
 	st.mutex.Unlock()
 	return
 }
 
-// SetRssPollFrequency safely sets the Configuration value for state's 'RssPollFrequency' field
-func (st *ConfigState) SetRssPollFrequency(v int) {
+// SetRssBackfillCount safely sets the Configuration value for state's 'RssBackfillCount' field
+func (st *ConfigState) SetRssBackfillCount(v int) {
 	st.mutex.Lock()
 	defer st.mutex.Unlock()
-	st.config.RssPollFrequency = v
+	st.config.RssBackfillCount = v
 	st.reloadToViper()
 }
 
-// RssPollFrequencyFlag returns the flag name for the 'RssPollFrequency' field
-func RssPollFrequencyFlag() string { return "napp-poll-frequency" }
+// RssBackfillCountFlag returns the flag name for the 'RssBackfillCount' field
+func RssBackfillCountFlag() string { return "rss-backfill-count" }
 
-// GetRssPollFrequency safely fetches the value for global configuration 'RssPollFrequency' field
-func GetRssPollFrequency() int { return global.GetRssPollFrequency() }
+// GetRssBackfillCount safely fetches the value for global configuration 'RssBackfillCount' field
+func GetRssBackfillCount() int { return global.GetRssBackfillCount() }
 
-// SetRssPollFrequency safely sets the value for global configuration 'RssPollFrequency' field
-func SetRssPollFrequency(v int) { global.SetRssPollFrequency(v) }
+// SetRssBackfillCount safely sets the value for global configuration 'RssBackfillCount' field
+func SetRssBackfillCount(v int) { global.SetRssBackfillCount(v) }
+
+// GetRssBackfillArchivePages safely fetches the Configuration value for state's 'RssBackfillArchivePages' field
+func (st *ConfigState) GetRssBackfillArchivePages() (v int) {
+	st.mutex.Lock()
+	v = st.config.RssBackfillArchivePages
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssBackfillArchivePages safely sets the Configuration value for state's 'RssBackfillArchivePages' field
+func (st *ConfigState) SetRssBackfillArchivePages(v int) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssBackfillArchivePages = v
+	st.reloadToViper()
+}
+
+// RssBackfillArchivePagesFlag returns the flag name for the 'RssBackfillArchivePages' field
+func RssBackfillArchivePagesFlag() string { return "rss-backfill-archive-pages" }
+
+// GetRssBackfillArchivePages safely fetches the value for global configuration 'RssBackfillArchivePages' field
+func GetRssBackfillArchivePages() int { return global.GetRssBackfillArchivePages() }
+
+// SetRssBackfillArchivePages safely sets the value for global configuration 'RssBackfillArchivePages' field
+func SetRssBackfillArchivePages(v int) { global.SetRssBackfillArchivePages(v) }
+
+// GetRssFetchComments safely fetches the Configuration value for state's 'RssFetchComments' field
+func (st *ConfigState) GetRssFetchComments() (v bool) {
+	st.mutex.Lock()
+	v = st.config.RssFetchComments
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssFetchComments safely sets the Configuration value for state's 'RssFetchComments' field
+func (st *ConfigState) SetRssFetchComments(v bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssFetchComments = v
+	st.reloadToViper()
+}
+
+// RssFetchCommentsFlag returns the flag name for the 'RssFetchComments' field
+func RssFetchCommentsFlag() string { return "rss-fetch-comments" }
+
+// GetRssFetchComments safely fetches the value for global configuration 'RssFetchComments' field
+func GetRssFetchComments() bool { return global.GetRssFetchComments() }
+
+// SetRssFetchComments safely sets the value for global configuration 'RssFetchComments' field
+func SetRssFetchComments(v bool) { global.SetRssFetchComments(v) }
+
+// GetRssDefaultVisibility safely fetches the Configuration value for state's 'RssDefaultVisibility' field
+func (st *ConfigState) GetRssDefaultVisibility() (v string) {
+	st.mutex.Lock()
+	v = st.config.RssDefaultVisibility
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssDefaultVisibility safely sets the Configuration value for state's 'RssDefaultVisibility' field
+func (st *ConfigState) SetRssDefaultVisibility(v string) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssDefaultVisibility = v
+	st.reloadToViper()
+}
+
+// RssDefaultVisibilityFlag returns the flag name for the 'RssDefaultVisibility' field
+func RssDefaultVisibilityFlag() string { return "rss-default-visibility" }
+
+// GetRssDefaultVisibility safely fetches the value for global configuration 'RssDefaultVisibility' field
+func GetRssDefaultVisibility() string { return global.GetRssDefaultVisibility() }
+
+// SetRssDefaultVisibility safely sets the value for global configuration 'RssDefaultVisibility' field
+func SetRssDefaultVisibility(v string) { global.SetRssDefaultVisibility(v) }
+
+// GetRssDefaultLocalOnly safely fetches the Configuration value for state's 'RssDefaultLocalOnly' field
+func (st *ConfigState) GetRssDefaultLocalOnly() (v bool) {
+	st.mutex.Lock()
+	v = st.config.RssDefaultLocalOnly
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssDefaultLocalOnly safely sets the Configuration value for state's 'RssDefaultLocalOnly' field
+func (st *ConfigState) SetRssDefaultLocalOnly(v bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssDefaultLocalOnly = v
+	st.reloadToViper()
+}
+
+// RssDefaultLocalOnlyFlag returns the flag name for the 'RssDefaultLocalOnly' field
+func RssDefaultLocalOnlyFlag() string { return "rss-default-local-only" }
+
+// GetRssDefaultLocalOnly safely fetches the value for global configuration 'RssDefaultLocalOnly' field
+func GetRssDefaultLocalOnly() bool { return global.GetRssDefaultLocalOnly() }
+
+// SetRssDefaultLocalOnly safely sets the value for global configuration 'RssDefaultLocalOnly' field
+func SetRssDefaultLocalOnly(v bool) { global.SetRssDefaultLocalOnly(v) }
+
+// GetRssDefaultSensitive safely fetches the Configuration value for state's 'RssDefaultSensitive' field
+func (st *ConfigState) GetRssDefaultSensitive() (v bool) {
+	st.mutex.Lock()
+	v = st.config.RssDefaultSensitive
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssDefaultSensitive safely sets the Configuration value for state's 'RssDefaultSensitive' field
+func (st *ConfigState) SetRssDefaultSensitive(v bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssDefaultSensitive = v
+	st.reloadToViper()
+}
+
+// RssDefaultSensitiveFlag returns the flag name for the 'RssDefaultSensitive' field
+func RssDefaultSensitiveFlag() string { return "rss-default-sensitive" }
+
+// GetRssDefaultSensitive safely fetches the value for global configuration 'RssDefaultSensitive' field
+func GetRssDefaultSensitive() bool { return global.GetRssDefaultSensitive() }
+
+// SetRssDefaultSensitive safely sets the value for global configuration 'RssDefaultSensitive' field
+func SetRssDefaultSensitive(v bool) { global.SetRssDefaultSensitive(v) }
+
+// GetRssDefaultContentWarning safely fetches the Configuration value for state's 'RssDefaultContentWarning' field
+func (st *ConfigState) GetRssDefaultContentWarning() (v string) {
+	st.mutex.Lock()
+	v = st.config.RssDefaultContentWarning
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssDefaultContentWarning safely sets the Configuration value for state's 'RssDefaultContentWarning' field
+func (st *ConfigState) SetRssDefaultContentWarning(v string) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssDefaultContentWarning = v
+	st.reloadToViper()
+}
+
+// RssDefaultContentWarningFlag returns the flag name for the 'RssDefaultContentWarning' field
+func RssDefaultContentWarningFlag() string { return "rss-default-content-warning" }
+
+// GetRssDefaultContentWarning safely fetches the value for global configuration 'RssDefaultContentWarning' field
+func GetRssDefaultContentWarning() string { return global.GetRssDefaultContentWarning() }
+
+// SetRssDefaultContentWarning safely sets the value for global configuration 'RssDefaultContentWarning' field
+func SetRssDefaultContentWarning(v string) { global.SetRssDefaultContentWarning(v) }
+
+// GetRssDefaultContentWarningFromTitle safely fetches the Configuration value for state's 'RssDefaultContentWarningFromTitle' field
+func (st *ConfigState) GetRssDefaultContentWarningFromTitle() (v bool) {
+	st.mutex.Lock()
+	v = st.config.RssDefaultContentWarningFromTitle
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssDefaultContentWarningFromTitle safely sets the Configuration value for state's 'RssDefaultContentWarningFromTitle' field
+func (st *ConfigState) SetRssDefaultContentWarningFromTitle(v bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssDefaultContentWarningFromTitle = v
+	st.reloadToViper()
+}
+
+// RssDefaultContentWarningFromTitleFlag returns the flag name for the 'RssDefaultContentWarningFromTitle' field
+func RssDefaultContentWarningFromTitleFlag() string { return "rss-default-content-warning-from-title" }
+
+// GetRssDefaultContentWarningFromTitle safely fetches the value for global configuration 'RssDefaultContentWarningFromTitle' field
+func GetRssDefaultContentWarningFromTitle() bool {
+	return global.GetRssDefaultContentWarningFromTitle()
+}
+
+// SetRssDefaultContentWarningFromTitle safely sets the value for global configuration 'RssDefaultContentWarningFromTitle' field
+func SetRssDefaultContentWarningFromTitle(v bool) { global.SetRssDefaultContentWarningFromTitle(v) }
+
+// GetRssDefaultBoostable safely fetches the Configuration value for state's 'RssDefaultBoostable' field
+func (st *ConfigState) GetRssDefaultBoostable() (v bool) {
+	st.mutex.Lock()
+	v = st.config.RssDefaultBoostable
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssDefaultBoostable safely sets the Configuration value for state's 'RssDefaultBoostable' field
+func (st *ConfigState) SetRssDefaultBoostable(v bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssDefaultBoostable = v
+	st.reloadToViper()
+}
+
+// RssDefaultBoostableFlag returns the flag name for the 'RssDefaultBoostable' field
+func RssDefaultBoostableFlag() string { return "rss-default-boostable" }
+
+// GetRssDefaultBoostable safely fetches the value for global configuration 'RssDefaultBoostable' field
+func GetRssDefaultBoostable() bool { return global.GetRssDefaultBoostable() }
+
+// SetRssDefaultBoostable safely sets the value for global configuration 'RssDefaultBoostable' field
+func SetRssDefaultBoostable(v bool) { global.SetRssDefaultBoostable(v) }
+
+// GetRssDefaultLikeable safely fetches the Configuration value for state's 'RssDefaultLikeable' field
+func (st *ConfigState) GetRssDefaultLikeable() (v bool) {
+	st.mutex.Lock()
+	v = st.config.RssDefaultLikeable
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssDefaultLikeable safely sets the Configuration value for state's 'RssDefaultLikeable' field
+func (st *ConfigState) SetRssDefaultLikeable(v bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssDefaultLikeable = v
+	st.reloadToViper()
+}
+
+// RssDefaultLikeableFlag returns the flag name for the 'RssDefaultLikeable' field
+func RssDefaultLikeableFlag() string { return "rss-default-likeable" }
+
+// GetRssDefaultLikeable safely fetches the value for global configuration 'RssDefaultLikeable' field
+func GetRssDefaultLikeable() bool { return global.GetRssDefaultLikeable() }
+
+// SetRssDefaultLikeable safely sets the value for global configuration 'RssDefaultLikeable' field
+func SetRssDefaultLikeable(v bool) { global.SetRssDefaultLikeable(v) }
+
+// GetRssDefaultReplyable safely fetches the Configuration value for state's 'RssDefaultReplyable' field
+func (st *ConfigState) GetRssDefaultReplyable() (v bool) {
+	st.mutex.Lock()
+	v = st.config.RssDefaultReplyable
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssDefaultReplyable safely sets the Configuration value for state's 'RssDefaultReplyable' field
+func (st *ConfigState) SetRssDefaultReplyable(v bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssDefaultReplyable = v
+	st.reloadToViper()
+}
+
+// RssDefaultReplyableFlag returns the flag name for the 'RssDefaultReplyable' field
+func RssDefaultReplyableFlag() string { return "rss-default-replyable" }
+
+// GetRssDefaultReplyable safely fetches the value for global configuration 'RssDefaultReplyable' field
+func GetRssDefaultReplyable() bool { return global.GetRssDefaultReplyable() }
+
+// SetRssDefaultReplyable safely sets the value for global configuration 'RssDefaultReplyable' field
+func SetRssDefaultReplyable(v bool) { global.SetRssDefaultReplyable(v) }
+
+// GetRssProfileRefreshFrequency safely fetches the Configuration value for state's 'RssProfileRefreshFrequency' field
+func (st *ConfigState) GetRssProfileRefreshFrequency() (v int) {
+	st.mutex.Lock()
+	v = st.config.RssProfileRefreshFrequency
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssProfileRefreshFrequency safely sets the Configuration value for state's 'RssProfileRefreshFrequency' field
+func (st *ConfigState) SetRssProfileRefreshFrequency(v int) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssProfileRefreshFrequency = v
+	st.reloadToViper()
+}
+
+// RssProfileRefreshFrequencyFlag returns the flag name for the 'RssProfileRefreshFrequency' field
+func RssProfileRefreshFrequencyFlag() string { return "rss-profile-refresh-frequency" }
+
+// GetRssProfileRefreshFrequency safely fetches the value for global configuration 'RssProfileRefreshFrequency' field
+func GetRssProfileRefreshFrequency() int { return global.GetRssProfileRefreshFrequency() }
+
+// SetRssProfileRefreshFrequency safely sets the value for global configuration 'RssProfileRefreshFrequency' field
+func SetRssProfileRefreshFrequency(v int) { global.SetRssProfileRefreshFrequency(v) }
+
+// GetRssDefaultByline safely fetches the Configuration value for state's 'RssDefaultByline' field
+func (st *ConfigState) GetRssDefaultByline() (v bool) {
+	st.mutex.Lock()
+	v = st.config.RssDefaultByline
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssDefaultByline safely sets the Configuration value for state's 'RssDefaultByline' field
+func (st *ConfigState) SetRssDefaultByline(v bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssDefaultByline = v
+	st.reloadToViper()
+}
+
+// RssDefaultBylineFlag returns the flag name for the 'RssDefaultByline' field
+func RssDefaultBylineFlag() string { return "rss-default-byline" }
+
+// GetRssDefaultByline safely fetches the value for global configuration 'RssDefaultByline' field
+func GetRssDefaultByline() bool { return global.GetRssDefaultByline() }
+
+// SetRssDefaultByline safely sets the value for global configuration 'RssDefaultByline' field
+func SetRssDefaultByline(v bool) { global.SetRssDefaultByline(v) }
+
+// GetRssFetchAuthorHandles safely fetches the Configuration value for state's 'RssFetchAuthorHandles' field
+func (st *ConfigState) GetRssFetchAuthorHandles() (v bool) {
+	st.mutex.Lock()
+	v = st.config.RssFetchAuthorHandles
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssFetchAuthorHandles safely sets the Configuration value for state's 'RssFetchAuthorHandles' field
+func (st *ConfigState) SetRssFetchAuthorHandles(v bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssFetchAuthorHandles = v
+	st.reloadToViper()
+}
+
+// RssFetchAuthorHandlesFlag returns the flag name for the 'RssFetchAuthorHandles' field
+func RssFetchAuthorHandlesFlag() string { return "rss-fetch-author-handles" }
+
+// GetRssFetchAuthorHandles safely fetches the value for global configuration 'RssFetchAuthorHandles' field
+func GetRssFetchAuthorHandles() bool { return global.GetRssFetchAuthorHandles() }
+
+// SetRssFetchAuthorHandles safely sets the value for global configuration 'RssFetchAuthorHandles' field
+func SetRssFetchAuthorHandles(v bool) { global.SetRssFetchAuthorHandles(v) }
+
+// GetRssPreviewCards safely fetches the Configuration value for state's 'RssPreviewCards' field
+func (st *ConfigState) GetRssPreviewCards() (v bool) {
+	st.mutex.Lock()
+	v = st.config.RssPreviewCards
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssPreviewCards safely sets the Configuration value for state's 'RssPreviewCards' field
+func (st *ConfigState) SetRssPreviewCards(v bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssPreviewCards = v
+	st.reloadToViper()
+}
+
+// RssPreviewCardsFlag returns the flag name for the 'RssPreviewCards' field
+func RssPreviewCardsFlag() string { return "rss-preview-cards" }
+
+// GetRssPreviewCards safely fetches the value for global configuration 'RssPreviewCards' field
+func GetRssPreviewCards() bool { return global.GetRssPreviewCards() }
+
+// SetRssPreviewCards safely sets the value for global configuration 'RssPreviewCards' field
+func SetRssPreviewCards(v bool) { global.SetRssPreviewCards(v) }
+
+// GetRssPreviewCardsLocal safely fetches the Configuration value for state's 'RssPreviewCardsLocal' field
+func (st *ConfigState) GetRssPreviewCardsLocal() (v bool) {
+	st.mutex.Lock()
+	v = st.config.RssPreviewCardsLocal
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssPreviewCardsLocal safely sets the Configuration value for state's 'RssPreviewCardsLocal' field
+func (st *ConfigState) SetRssPreviewCardsLocal(v bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssPreviewCardsLocal = v
+	st.reloadToViper()
+}
+
+// RssPreviewCardsLocalFlag returns the flag name for the 'RssPreviewCardsLocal' field
+func RssPreviewCardsLocalFlag() string { return "rss-preview-cards-local" }
+
+// GetRssPreviewCardsLocal safely fetches the value for global configuration 'RssPreviewCardsLocal' field
+func GetRssPreviewCardsLocal() bool { return global.GetRssPreviewCardsLocal() }
+
+// SetRssPreviewCardsLocal safely sets the value for global configuration 'RssPreviewCardsLocal' field
+func SetRssPreviewCardsLocal(v bool) { global.SetRssPreviewCardsLocal(v) }
+
+// GetRssNewsletterMaildir safely fetches the Configuration value for state's 'RssNewsletterMaildir' field
+func (st *ConfigState) GetRssNewsletterMaildir() (v string) {
+	st.mutex.Lock()
+	v = st.config.RssNewsletterMaildir
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssNewsletterMaildir safely sets the Configuration value for state's 'RssNewsletterMaildir' field
+func (st *ConfigState) SetRssNewsletterMaildir(v string) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssNewsletterMaildir = v
+	st.reloadToViper()
+}
+
+// RssNewsletterMaildirFlag returns the flag name for the 'RssNewsletterMaildir' field
+func RssNewsletterMaildirFlag() string { return "rss-newsletter-maildir" }
+
+// GetRssNewsletterMaildir safely fetches the value for global configuration 'RssNewsletterMaildir' field
+func GetRssNewsletterMaildir() string { return global.GetRssNewsletterMaildir() }
+
+// SetRssNewsletterMaildir safely sets the value for global configuration 'RssNewsletterMaildir' field
+func SetRssNewsletterMaildir(v string) { global.SetRssNewsletterMaildir(v) }
+
+// GetRssNewsletterDomain safely fetches the Configuration value for state's 'RssNewsletterDomain' field
+func (st *ConfigState) GetRssNewsletterDomain() (v string) {
+	st.mutex.Lock()
+	v = st.config.RssNewsletterDomain
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssNewsletterDomain safely sets the Configuration value for state's 'RssNewsletterDomain' field
+func (st *ConfigState) SetRssNewsletterDomain(v string) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssNewsletterDomain = v
+	st.reloadToViper()
+}
+
+// RssNewsletterDomainFlag returns the flag name for the 'RssNewsletterDomain' field
+func RssNewsletterDomainFlag() string { return "rss-newsletter-domain" }
+
+// GetRssNewsletterDomain safely fetches the value for global configuration 'RssNewsletterDomain' field
+func GetRssNewsletterDomain() string { return global.GetRssNewsletterDomain() }
+
+// SetRssNewsletterDomain safely sets the value for global configuration 'RssNewsletterDomain' field
+func SetRssNewsletterDomain(v string) { global.SetRssNewsletterDomain(v) }
+
+// GetRssDefaultRetentionDays safely fetches the Configuration value for state's 'RssDefaultRetentionDays' field
+func (st *ConfigState) GetRssDefaultRetentionDays() (v int) {
+	st.mutex.Lock()
+	v = st.config.RssDefaultRetentionDays
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssDefaultRetentionDays safely sets the Configuration value for state's 'RssDefaultRetentionDays' field
+func (st *ConfigState) SetRssDefaultRetentionDays(v int) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssDefaultRetentionDays = v
+	st.reloadToViper()
+}
+
+// RssDefaultRetentionDaysFlag returns the flag name for the 'RssDefaultRetentionDays' field
+func RssDefaultRetentionDaysFlag() string { return "rss-default-retention-days" }
+
+// GetRssDefaultRetentionDays safely fetches the value for global configuration 'RssDefaultRetentionDays' field
+func GetRssDefaultRetentionDays() int { return global.GetRssDefaultRetentionDays() }
+
+// SetRssDefaultRetentionDays safely sets the value for global configuration 'RssDefaultRetentionDays' field
+func SetRssDefaultRetentionDays(v int) { global.SetRssDefaultRetentionDays(v) }
+
+// GetRssDefaultRetentionItems safely fetches the Configuration value for state's 'RssDefaultRetentionItems' field
+func (st *ConfigState) GetRssDefaultRetentionItems() (v int) {
+	st.mutex.Lock()
+	v = st.config.RssDefaultRetentionItems
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssDefaultRetentionItems safely sets the Configuration value for state's 'RssDefaultRetentionItems' field
+func (st *ConfigState) SetRssDefaultRetentionItems(v int) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssDefaultRetentionItems = v
+	st.reloadToViper()
+}
+
+// RssDefaultRetentionItemsFlag returns the flag name for the 'RssDefaultRetentionItems' field
+func RssDefaultRetentionItemsFlag() string { return "rss-default-retention-items" }
+
+// GetRssDefaultRetentionItems safely fetches the value for global configuration 'RssDefaultRetentionItems' field
+func GetRssDefaultRetentionItems() int { return global.GetRssDefaultRetentionItems() }
+
+// SetRssDefaultRetentionItems safely sets the value for global configuration 'RssDefaultRetentionItems' field
+func SetRssDefaultRetentionItems(v int) { global.SetRssDefaultRetentionItems(v) }
+
+// GetRssDeduplicate safely fetches the Configuration value for state's 'RssDeduplicate' field
+func (st *ConfigState) GetRssDeduplicate() (v bool) {
+	st.mutex.Lock()
+	v = st.config.RssDeduplicate
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssDeduplicate safely sets the Configuration value for state's 'RssDeduplicate' field
+func (st *ConfigState) SetRssDeduplicate(v bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssDeduplicate = v
+	st.reloadToViper()
+}
+
+// RssDeduplicateFlag returns the flag name for the 'RssDeduplicate' field
+func RssDeduplicateFlag() string { return "rss-deduplicate" }
+
+// GetRssDeduplicate safely fetches the value for global configuration 'RssDeduplicate' field
+func GetRssDeduplicate() bool { return global.GetRssDeduplicate() }
+
+// SetRssDeduplicate safely sets the value for global configuration 'RssDeduplicate' field
+func SetRssDeduplicate(v bool) { global.SetRssDeduplicate(v) }
+
+// GetRssDeduplicateCanonical safely fetches the Configuration value for state's 'RssDeduplicateCanonical' field
+func (st *ConfigState) GetRssDeduplicateCanonical() (v bool) {
+	st.mutex.Lock()
+	v = st.config.RssDeduplicateCanonical
+	st.mutex.Unlock()
+	return
+}
+
+// SetRssDeduplicateCanonical safely sets the Configuration value for state's 'RssDeduplicateCanonical' field
+func (st *ConfigState) SetRssDeduplicateCanonical(v bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.RssDeduplicateCanonical = v
+	st.reloadToViper()
+}
+
+// RssDeduplicateCanonicalFlag returns the flag name for the 'RssDeduplicateCanonical' field
+func RssDeduplicateCanonicalFlag() string { return "rss-deduplicate-canonical" }
+
+// GetRssDeduplicateCanonical safely fetches the value for global configuration 'RssDeduplicateCanonical' field
+func GetRssDeduplicateCanonical() bool { return global.GetRssDeduplicateCanonical() }
+
+// SetRssDeduplicateCanonical safely sets the value for global configuration 'RssDeduplicateCanonical' field
+func SetRssDeduplicateCanonical(v bool) { global.SetRssDeduplicateCanonical(v) }
@@ -124,6 +124,12 @@ type Account interface {
 	// In the case of no statuses, this function will return db.ErrNoEntries.
 	GetAccountWebStatuses(ctx context.Context, accountID string, limit int, maxID string) ([]*gtsmodel.Status, error)
 
+	// GetAccountFeedStatuses is similar to GetAccountWebStatuses, but for the syndication feeds of an account,
+	// which can optionally include replies and boosts, or only statuses with media or with the given tagID.
+	//
+	// In the case of no statuses, this function will return db.ErrNoEntries.
+	GetAccountFeedStatuses(ctx context.Context, accountID string, limit int, includeReplies bool, includeBoosts bool, mediaOnly bool, tagID string, maxID string) ([]*gtsmodel.Status, error)
+
 	// SetAccountHeaderOrAvatar sets the header or avatar for the given accountID to the given media attachment.
 	SetAccountHeaderOrAvatar(ctx context.Context, mediaAttachment *gtsmodel.MediaAttachment, accountID string) error
 
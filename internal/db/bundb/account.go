@@ -946,27 +946,7 @@ func (a *accountDB) GetAccountStatuses(ctx context.Context, accountID string, li
 	}
 
 	if mediaOnly {
-		// Attachments are stored as a json object; this
-		// implementation differs between SQLite and Postgres,
-		// so we have to be thorough to cover all eventualities
-		q = q.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
-			switch a.db.Dialect().Name() {
-			case dialect.PG:
-				return q.
-					Where("? IS NOT NULL", bun.Ident("status.attachments")).
-					Where("? != '{}'", bun.Ident("status.attachments"))
-			case dialect.SQLite:
-				return q.
-					Where("? IS NOT NULL", bun.Ident("status.attachments")).
-					Where("? != ''", bun.Ident("status.attachments")).
-					Where("? != 'null'", bun.Ident("status.attachments")).
-					Where("? != '{}'", bun.Ident("status.attachments")).
-					Where("? != '[]'", bun.Ident("status.attachments"))
-			default:
-				log.Panic(ctx, "db dialect was neither pg nor sqlite")
-				return q
-			}
-		})
+		q = a.whereHasAttachments(ctx, q)
 	}
 
 	if publicOnly {
@@ -1094,6 +1074,96 @@ func (a *accountDB) GetAccountWebStatuses(ctx context.Context, accountID string,
 	return a.state.DB.GetStatusesByIDs(ctx, statusIDs)
 }
 
+func (a *accountDB) GetAccountFeedStatuses(ctx context.Context, accountID string, limit int, includeReplies bool, includeBoosts bool, mediaOnly bool, tagID string, maxID string) ([]*gtsmodel.Status, error) {
+	// Ensure reasonable
+	if limit < 0 {
+		limit = 0
+	}
+
+	// Make educated guess for slice size
+	statusIDs := make([]string, 0, limit)
+
+	q := a.db.
+		NewSelect().
+		TableExpr("? AS ?", bun.Ident("statuses"), bun.Ident("status")).
+		// Select only IDs from table
+		Column("status.id").
+		Where("? = ?", bun.Ident("status.account_id"), accountID).
+		// Only Public statuses.
+		Where("? = ?", bun.Ident("status.visibility"), gtsmodel.VisibilityPublic).
+		// Don't show local-only statuses in feeds.
+		Where("? = ?", bun.Ident("status.federated"), true)
+
+	if !includeReplies {
+		q = q.Where("? IS NULL", bun.Ident("status.in_reply_to_uri"))
+	}
+
+	if !includeBoosts {
+		q = q.Where("? IS NULL", bun.Ident("status.boost_of_id"))
+	}
+
+	if mediaOnly {
+		q = a.whereHasAttachments(ctx, q)
+	}
+
+	if tagID != "" {
+		// Only statuses using the tag.
+		q = q.Where("? IN (?)", bun.Ident("status.id"), a.db.
+			NewSelect().
+			TableExpr("? AS ?", bun.Ident("status_to_tags"), bun.Ident("status_to_tag")).
+			Column("status_to_tag.status_id").
+			Where("? = ?", bun.Ident("status_to_tag.tag_id"), tagID))
+	}
+
+	// return only statuses LOWER (ie., older) than maxID
+	if maxID == "" {
+		maxID = id.Highest
+	}
+	q = q.Where("? < ?", bun.Ident("status.id"), maxID)
+
+	if limit > 0 {
+		// limit amount of statuses returned
+		q = q.Limit(limit)
+	}
+
+	q = q.Order("status.id DESC")
+
+	if err := q.Scan(ctx, &statusIDs); err != nil {
+		return nil, err
+	}
+
+	if len(statusIDs) == 0 {
+		return nil, db.ErrNoEntries
+	}
+
+	return a.state.DB.GetStatusesByIDs(ctx, statusIDs)
+}
+
+// whereHasAttachments extends a status query to only return statuses with media attachments.
+func (a *accountDB) whereHasAttachments(ctx context.Context, q *bun.SelectQuery) *bun.SelectQuery {
+	// Attachments are stored as a json object; this
+	// implementation differs between SQLite and Postgres,
+	// so we have to be thorough to cover all eventualities
+	return q.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+		switch a.db.Dialect().Name() {
+		case dialect.PG:
+			return q.
+				Where("? IS NOT NULL", bun.Ident("status.attachments")).
+				Where("? != '{}'", bun.Ident("status.attachments"))
+		case dialect.SQLite:
+			return q.
+				Where("? IS NOT NULL", bun.Ident("status.attachments")).
+				Where("? != ''", bun.Ident("status.attachments")).
+				Where("? != 'null'", bun.Ident("status.attachments")).
+				Where("? != '{}'", bun.Ident("status.attachments")).
+				Where("? != '[]'", bun.Ident("status.attachments"))
+		default:
+			log.Panic(ctx, "db dialect was neither pg nor sqlite")
+			return q
+		}
+	})
+}
+
 func (a *accountDB) GetAccountSettings(
 	ctx context.Context,
 	accountID string,
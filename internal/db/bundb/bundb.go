@@ -68,8 +68,10 @@ type DBService struct {
 	db.Move
 	db.Notification
 	db.Poll
+	db.PreviewCard
 	db.Relationship
 	db.Report
+	db.RssFeed
 	db.Rule
 	db.Search
 	db.Session
@@ -235,6 +237,10 @@ func NewBunDBService(ctx context.Context, state *state.State) (db.DB, error) {
 			db:    db,
 			state: state,
 		},
+		PreviewCard: &previewCardDB{
+			db:    db,
+			state: state,
+		},
 		Relationship: &relationshipDB{
 			db:    db,
 			state: state,
@@ -243,6 +249,10 @@ func NewBunDBService(ctx context.Context, state *state.State) (db.DB, error) {
 			db:    db,
 			state: state,
 		},
+		RssFeed: &rssFeedDB{
+			db:    db,
+			state: state,
+		},
 		Rule: &ruleDB{
 			db:    db,
 			state: state,
@@ -0,0 +1,99 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	up := func(ctx context.Context, db *bun.DB) error {
+		log.Info(ctx, "creating feed records for existing feed accounts, please wait...")
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			// Create the new rss feeds table.
+			if _, err := tx.
+				NewCreateTable().
+				Model(&gtsmodel.RssFeed{}).
+				IfNotExists().
+				Exec(ctx); err != nil {
+				return err
+			}
+
+			// Select each existing feed account, they
+			// were all created with a dummy user email.
+			feeds := []struct {
+				ID         string    `bun:"id"`
+				URL        string    `bun:"url"`
+				LastItemAt time.Time `bun:"last_item_at"`
+			}{}
+			if err := tx.
+				NewSelect().
+				TableExpr("? AS ?", bun.Ident("accounts"), bun.Ident("account")).
+				Column("account.id", "account.url").
+				ColumnExpr("MAX(?) AS ?", bun.Ident("status.created_at"), bun.Ident("last_item_at")).
+				Join(
+					"JOIN ? AS ? ON ? = ?",
+					bun.Ident("users"), bun.Ident("user"),
+					bun.Ident("user.account_id"), bun.Ident("account.id"),
+				).
+				Join(
+					"LEFT JOIN ? AS ? ON ? = ?",
+					bun.Ident("statuses"), bun.Ident("status"),
+					bun.Ident("status.account_id"), bun.Ident("account.id"),
+				).
+				Where("? LIKE ?", bun.Ident("user.email"), "%@rss.tooter.com").
+				Group("account.id", "account.url").
+				Scan(ctx, &feeds); err != nil {
+				return err
+			}
+
+			// Create a feed entry for each of them.
+			for _, feed := range feeds {
+				if _, err := tx.
+					NewInsert().
+					Model(&gtsmodel.RssFeed{
+						ID:         id.NewULID(),
+						AccountID:  feed.ID,
+						URL:        feed.URL,
+						LastItemAt: feed.LastItemAt,
+					}).
+					Exec(ctx); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	down := func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			return nil
+		})
+	}
+
+	if err := Migrations.Register(up, down); err != nil {
+		panic(err)
+	}
+}
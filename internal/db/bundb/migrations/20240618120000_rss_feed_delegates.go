@@ -0,0 +1,78 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package migrations
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	up := func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			// Create the new feed delegates table.
+			if _, err := tx.
+				NewCreateTable().
+				Model(&gtsmodel.RssFeedDelegate{}).
+				IfNotExists().
+				Exec(ctx); err != nil {
+				return err
+			}
+
+			feedUsers := tx.
+				NewSelect().
+				Table("users").
+				Column("id").
+				Where("? IN (?)", bun.Ident("account_id"), tx.NewSelect().Table("rss_feeds").Column("account_id"))
+
+			// Feed accounts were created with a shared
+			// password and a made up email: revoke the
+			// tokens obtained with them...
+			if _, err := tx.
+				NewDelete().
+				Table("tokens").
+				Where("? IN (?)", bun.Ident("user_id"), feedUsers).
+				Exec(ctx); err != nil {
+				return err
+			}
+
+			// ...and leave the users without login, the
+			// password hash isn't one bcrypt can match.
+			_, err := tx.
+				NewUpdate().
+				Table("users").
+				Set("? = ?", bun.Ident("encrypted_password"), "!").
+				Set("? = NULL", bun.Ident("email")).
+				Where("? IN (?)", bun.Ident("account_id"), tx.NewSelect().Table("rss_feeds").Column("account_id")).
+				Exec(ctx)
+			return err
+		})
+	}
+
+	down := func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			return nil
+		})
+	}
+
+	if err := Migrations.Register(up, down); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,93 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	up := func(ctx context.Context, db *bun.DB) error {
+		// App passwords and Fever API keys are stored hashed from now on: their
+		// columns are renamed, unless app_passwords was created with the current
+		// model. Not in a transaction, as postgres would abort it on error.
+		_, err := db.ExecContext(ctx, "ALTER TABLE ? RENAME COLUMN ? TO ?", bun.Ident("app_passwords"), bun.Ident("password"), bun.Ident("password_hash"))
+		if err != nil {
+			if strings.Contains(err.Error(), "no such column") || strings.Contains(err.Error(), "does not exist") {
+				return nil
+			}
+			return err
+		}
+		if _, err := db.ExecContext(ctx, "ALTER TABLE ? RENAME COLUMN ? TO ?", bun.Ident("app_passwords"), bun.Ident("fever_key"), bun.Ident("fever_key_hash")); err != nil {
+			return err
+		}
+
+		// The passwords and keys stored until now are hashed, and the ones
+		// created by delegates acting as feed accounts revoked.
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			if _, err := tx.NewDelete().
+				Table("app_passwords").
+				Where("? IN (?)", bun.Ident("account_id"), tx.NewSelect().Table("rss_feeds").Column("account_id")).
+				Exec(ctx); err != nil {
+				return err
+			}
+
+			var passwords []struct {
+				ID           string `bun:"id"`
+				PasswordHash string `bun:"password_hash"`
+				FeverKeyHash string `bun:"fever_key_hash"`
+			}
+			if err := tx.NewSelect().
+				Table("app_passwords").
+				Column("id", "password_hash", "fever_key_hash").
+				Scan(ctx, &passwords); err != nil {
+				return err
+			}
+
+			for _, password := range passwords {
+				passwordHash := sha256.Sum256([]byte(password.PasswordHash))
+				feverKeyHash := sha256.Sum256([]byte(password.FeverKeyHash))
+				if _, err := tx.NewUpdate().
+					Table("app_passwords").
+					Set("? = ?", bun.Ident("password_hash"), hex.EncodeToString(passwordHash[:])).
+					Set("? = ?", bun.Ident("fever_key_hash"), hex.EncodeToString(feverKeyHash[:])).
+					Where("? = ?", bun.Ident("id"), password.ID).
+					Exec(ctx); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	down := func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			return nil
+		})
+	}
+
+	if err := Migrations.Register(up, down); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,109 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package migrations
+
+import (
+	"context"
+	"strings"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/uris"
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	up := func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			// Create the new feed items table.
+			if _, err := tx.
+				NewCreateTable().
+				Model(&gtsmodel.RssItem{}).
+				IfNotExists().
+				Exec(ctx); err != nil {
+				return err
+			}
+
+			if _, err := tx.
+				NewCreateIndex().
+				Model(&gtsmodel.RssItem{}).
+				Index("rss_items_account_id_link_idx").
+				Column("account_id", "link").
+				IfNotExists().
+				Exec(ctx); err != nil {
+				return err
+			}
+
+			// Record the items already posted by feed accounts: their statuses
+			// aren't boosts, and their url is the item link instead of the local
+			// one of the statuses posted on this server, by delegates or digests.
+			var statuses []struct {
+				ID        string `bun:"id"`
+				AccountID string `bun:"account_id"`
+				URL       string `bun:"url"`
+				Username  string `bun:"username"`
+			}
+			if err := tx.NewSelect().
+				TableExpr("? AS ?", bun.Ident("statuses"), bun.Ident("status")).
+				ColumnExpr("? AS ?", bun.Ident("status.id"), bun.Ident("id")).
+				ColumnExpr("? AS ?", bun.Ident("status.account_id"), bun.Ident("account_id")).
+				ColumnExpr("? AS ?", bun.Ident("status.url"), bun.Ident("url")).
+				ColumnExpr("? AS ?", bun.Ident("account.username"), bun.Ident("username")).
+				Join("JOIN ? AS ? ON ? = ?", bun.Ident("accounts"), bun.Ident("account"), bun.Ident("account.id"), bun.Ident("status.account_id")).
+				Where("? IN (?)", bun.Ident("status.account_id"), tx.NewSelect().Table("rss_feeds").Column("account_id")).
+				Where("? IS NULL", bun.Ident("status.boost_of_id")).
+				Scan(ctx, &statuses); err != nil {
+				return err
+			}
+
+			items := make([]*gtsmodel.RssItem, 0, len(statuses))
+			for _, status := range statuses {
+				if strings.HasPrefix(status.URL, uris.GenerateURIsForAccount(status.Username).StatusesURL+"/") {
+					continue
+				}
+				items = append(items, &gtsmodel.RssItem{
+					ID:        id.NewULID(),
+					AccountID: status.AccountID,
+					StatusID:  status.ID,
+					Link:      status.URL,
+				})
+			}
+
+			for start := 0; start < len(items); start += 500 {
+				batch := items[start:min(start+500, len(items))]
+				if _, err := tx.NewInsert().
+					Model(&batch).
+					Exec(ctx); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	down := func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			return nil
+		})
+	}
+
+	if err := Migrations.Register(up, down); err != nil {
+		panic(err)
+	}
+}
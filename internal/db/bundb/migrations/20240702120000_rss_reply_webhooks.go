@@ -0,0 +1,89 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package migrations
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	up := func(ctx context.Context, db *bun.DB) error {
+		// Reply webhooks are delivered through the webhook delivery queue,
+		// signed with a secret of their feed. These columns already exist
+		// if the tables were created with the current model.
+		// Not in a transaction, as postgres would abort it on error.
+		for _, column := range []struct {
+			table      string
+			name       string
+			columnType string
+		}{
+			{"rss_feeds", "reply_webhook_secret", "VARCHAR"},
+			{"rss_webhook_deliveries", "event", "VARCHAR NOT NULL DEFAULT 'status.created'"},
+		} {
+			_, err := db.ExecContext(ctx, "ALTER TABLE ? ADD COLUMN ? "+column.columnType, bun.Ident(column.table), bun.Ident(column.name))
+			if err != nil && !(strings.Contains(err.Error(), "already exists") || strings.Contains(err.Error(), "duplicate column name") || strings.Contains(err.Error(), "SQLSTATE 42701")) {
+				return err
+			}
+		}
+
+		// Generate the secret of the reply webhooks already set.
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			var feedIDs []string
+			if err := tx.NewSelect().
+				Table("rss_feeds").
+				Column("id").
+				Where("? IS NOT NULL", bun.Ident("reply_webhook_url")).
+				Where("? IS NULL", bun.Ident("reply_webhook_secret")).
+				Scan(ctx, &feedIDs); err != nil {
+				return err
+			}
+
+			encoding := base32.NewEncoding("0123456789abcdefghjkmnpqrstvwxyz").WithPadding(base32.NoPadding)
+			for _, feedID := range feedIDs {
+				secret := make([]byte, 20)
+				if _, err := rand.Read(secret); err != nil {
+					return err
+				}
+				if _, err := tx.NewUpdate().
+					Table("rss_feeds").
+					Set("? = ?", bun.Ident("reply_webhook_secret"), encoding.EncodeToString(secret)).
+					Where("? = ?", bun.Ident("id"), feedID).
+					Exec(ctx); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	down := func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			return nil
+		})
+	}
+
+	if err := Migrations.Register(up, down); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,79 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	up := func(ctx context.Context, db *bun.DB) error {
+		// Feed tokens are stored hashed from now on: the token column
+		// is renamed, unless feed_tokens was created with the current
+		// model. Not in a transaction, as postgres would abort it on error.
+		_, err := db.ExecContext(ctx, "ALTER TABLE ? RENAME COLUMN ? TO ?", bun.Ident("feed_tokens"), bun.Ident("token"), bun.Ident("token_hash"))
+		if err != nil {
+			if strings.Contains(err.Error(), "no such column") || strings.Contains(err.Error(), "does not exist") {
+				return nil
+			}
+			return err
+		}
+
+		// The tokens stored until now are hashed.
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			var tokens []struct {
+				ID        string `bun:"id"`
+				TokenHash string `bun:"token_hash"`
+			}
+			if err := tx.NewSelect().
+				Table("feed_tokens").
+				Column("id", "token_hash").
+				Scan(ctx, &tokens); err != nil {
+				return err
+			}
+
+			for _, token := range tokens {
+				hash := sha256.Sum256([]byte(token.TokenHash))
+				if _, err := tx.NewUpdate().
+					Table("feed_tokens").
+					Set("? = ?", bun.Ident("token_hash"), hex.EncodeToString(hash[:])).
+					Where("? = ?", bun.Ident("id"), token.ID).
+					Exec(ctx); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	down := func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			return nil
+		})
+	}
+
+	if err := Migrations.Register(up, down); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,80 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package bundb
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/uptrace/bun"
+)
+
+type previewCardDB struct {
+	db    *bun.DB
+	state *state.State
+}
+
+func (p *previewCardDB) GetPreviewCardByID(ctx context.Context, id string) (*gtsmodel.PreviewCard, error) {
+	return p.getPreviewCard(ctx, "id", id)
+}
+
+func (p *previewCardDB) GetPreviewCardByURL(ctx context.Context, url string) (*gtsmodel.PreviewCard, error) {
+	return p.getPreviewCard(ctx, "url", url)
+}
+
+func (p *previewCardDB) getPreviewCard(ctx context.Context, column string, value string) (*gtsmodel.PreviewCard, error) {
+	var card gtsmodel.PreviewCard
+
+	q := p.db.
+		NewSelect().
+		Model(&card).
+		Where("? = ?", bun.Ident("preview_card."+column), value)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	if card.ImageID != "" {
+		// Populate the card image.
+		image, err := p.state.DB.GetAttachmentByID(ctx, card.ImageID)
+		if err != nil {
+			return nil, gtserror.Newf("error populating preview card image: %w", err)
+		}
+		card.Image = image
+	}
+
+	return &card, nil
+}
+
+func (p *previewCardDB) PutPreviewCard(ctx context.Context, card *gtsmodel.PreviewCard) error {
+	_, err := p.db.
+		NewInsert().
+		Model(card).
+		Exec(ctx)
+	return err
+}
+
+func (p *previewCardDB) IsPreviewCardImage(ctx context.Context, attachmentID string) (bool, error) {
+	return p.db.
+		NewSelect().
+		TableExpr("? AS ?", bun.Ident("preview_cards"), bun.Ident("preview_card")).
+		Where("? = ?", bun.Ident("preview_card.image_id"), attachmentID).
+		Exists(ctx)
+}
@@ -0,0 +1,55 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package bundb
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/uptrace/bun"
+)
+
+type rssFeedDB struct {
+	db    *bun.DB
+	state *state.State
+}
+
+func (r *rssFeedDB) GetRssFeedByAccountID(ctx context.Context, accountID string) (*gtsmodel.RssFeed, error) {
+	var feed gtsmodel.RssFeed
+
+	q := r.db.
+		NewSelect().
+		Model(&feed).
+		Where("? = ?", bun.Ident("rss_feed.account_id"), accountID)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	return &feed, nil
+}
+
+func (r *rssFeedDB) IsRssFeedDelegate(ctx context.Context, accountID string, delegateAccountID string) (bool, error) {
+	return r.db.
+		NewSelect().
+		TableExpr("? AS ?", bun.Ident("rss_feed_delegates"), bun.Ident("rss_feed_delegate")).
+		Where("? = ?", bun.Ident("rss_feed_delegate.account_id"), accountID).
+		Where("? = ?", bun.Ident("rss_feed_delegate.delegate_account_id"), delegateAccountID).
+		Exists(ctx)
+}
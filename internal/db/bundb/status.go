@@ -181,7 +181,7 @@ func (s *statusDB) getStatus(ctx context.Context, lookup string, dbQuery func(*g
 func (s *statusDB) PopulateStatus(ctx context.Context, status *gtsmodel.Status) error {
 	var (
 		err  error
-		errs = gtserror.NewMultiError(9)
+		errs = gtserror.NewMultiError(10)
 	)
 
 	if status.Account == nil {
@@ -254,6 +254,17 @@ func (s *statusDB) PopulateStatus(ctx context.Context, status *gtsmodel.Status)
 		}
 	}
 
+	if status.PreviewCardID != "" && status.PreviewCard == nil {
+		// Status preview card is not set, fetch from database.
+		status.PreviewCard, err = s.state.DB.GetPreviewCardByID(
+			ctx,
+			status.PreviewCardID,
+		)
+		if err != nil {
+			errs.Appendf("error populating status preview card: %w", err)
+		}
+	}
+
 	if !status.AttachmentsPopulated() {
 		// Status attachments are out-of-date with IDs, repopulate.
 		status.Attachments, err = s.state.DB.GetAttachmentsByIDs(
@@ -44,8 +44,10 @@ type DB interface {
 	Move
 	Notification
 	Poll
+	PreviewCard
 	Relationship
 	Report
+	RssFeed
 	Rule
 	Search
 	Session
@@ -0,0 +1,39 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// PreviewCard handles getting/creation of the preview cards of status links.
+type PreviewCard interface {
+	// GetPreviewCardByID gets one preview card by its db id.
+	GetPreviewCardByID(ctx context.Context, id string) (*gtsmodel.PreviewCard, error)
+
+	// GetPreviewCardByURL gets the preview card of the page at url.
+	GetPreviewCardByURL(ctx context.Context, url string) (*gtsmodel.PreviewCard, error)
+
+	// PutPreviewCard puts the given preview card in the database.
+	PutPreviewCard(ctx context.Context, card *gtsmodel.PreviewCard) error
+
+	// IsPreviewCardImage returns whether the media attachment with the given id is the image of a preview card.
+	IsPreviewCardImage(ctx context.Context, attachmentID string) (bool, error)
+}
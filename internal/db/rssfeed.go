@@ -0,0 +1,33 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// RssFeed handles getting the source feeds of local feed accounts, and their delegates.
+type RssFeed interface {
+	// GetRssFeedByAccountID gets the source feed of the feed account with the given id.
+	GetRssFeedByAccountID(ctx context.Context, accountID string) (*gtsmodel.RssFeed, error)
+
+	// IsRssFeedDelegate returns whether the local account delegateAccountID may act on behalf of the feed account accountID.
+	IsRssFeedDelegate(ctx context.Context, accountID string, delegateAccountID string) (bool, error)
+}
@@ -0,0 +1,46 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package email
+
+var (
+	feedReplyTemplate = "email_feed_reply.tmpl"
+	feedReplySubject  = "GoToSocial Feed Reply"
+)
+
+type FeedReplyData struct {
+	// URL of the instance to present to the receiver.
+	InstanceURL string
+	// Name of the instance to present to the receiver.
+	InstanceName string
+	// Display name of the feed account.
+	FeedName string
+	// URL of the feed item replied to.
+	ItemURL string
+	// Account which posted the reply, as @username@domain.
+	ReplyAccount string
+	// URL of the reply.
+	ReplyURL string
+	// Content warning of the reply, if any.
+	ReplySpoiler string
+	// Plaintext content of the reply.
+	ReplyText string
+}
+
+func (s *sender) SendFeedReplyEmail(toAddress string, data FeedReplyData) error {
+	return s.sendTemplate(feedReplyTemplate, feedReplySubject, data, toAddress)
+}
@@ -80,6 +80,10 @@ func (s *noopSender) SendSignupRejectedEmail(toAddress string, data SignupReject
 	return s.sendTemplate(signupRejectedTemplate, signupRejectedSubject, data, toAddress)
 }
 
+func (s *noopSender) SendFeedReplyEmail(toAddress string, data FeedReplyData) error {
+	return s.sendTemplate(feedReplyTemplate, feedReplySubject, data, toAddress)
+}
+
 func (s *noopSender) sendTemplate(template string, subject string, data any, toAddresses ...string) error {
 	buf := &bytes.Buffer{}
 	if err := s.template.ExecuteTemplate(buf, template, data); err != nil {
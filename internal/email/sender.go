@@ -61,6 +61,10 @@ type Sender interface {
 	// SendSignupRejectedEmail sends an email to the given address
 	// that their sign-up request has been rejected by a moderator.
 	SendSignupRejectedEmail(toAddress string, data SignupRejectedData) error
+
+	// SendFeedReplyEmail sends an email to the given address, forwarding
+	// a reply received by a status of a feed account to the feed owner.
+	SendFeedReplyEmail(toAddress string, data FeedReplyData) error
 }
 
 // NewSender returns a new email Sender interface with the given configuration, or an error if something goes wrong.
@@ -736,7 +736,7 @@ func (d *Dereferencer) enrichAccount(
 	}
 
 	// Ensure the account's avatar media is populated, passing in existing to check for changes.
-	if err := d.fetchRemoteAccountHeader(ctx, tsport, account, latestAcc); err != nil {
+	if err := d.FetchRemoteAccountHeader(ctx, tsport, account, latestAcc); err != nil {
 		log.Errorf(ctx, "error fetching remote header for account %s: %v", uri, err)
 	}
 
@@ -851,7 +851,7 @@ func (d *Dereferencer) FetchRemoteAccountAvatar(
 	return nil
 }
 
-func (d *Dereferencer) fetchRemoteAccountHeader(
+func (d *Dereferencer) FetchRemoteAccountHeader(
 	ctx context.Context,
 	tsport transport.Transport,
 	existingAcc *gtsmodel.Account,
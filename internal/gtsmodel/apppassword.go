@@ -0,0 +1,32 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gtsmodel
+
+import "time"
+
+// AppPassword is a revocable password for apps speaking the Google Reader
+// or Fever API, which can't log in with OAuth.
+type AppPassword struct {
+	ID           string    `bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                    // id of this item in the database
+	CreatedAt    time.Time `bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	AccountID    string    `bun:"type:CHAR(26),nullzero,notnull"`                              // id of the account logging in with this password
+	Account      *Account  `bun:"-"`                                                           // account corresponding to accountID
+	Name         string    `bun:",nullzero"`                                                   // name given to remember the app using it
+	PasswordHash string    `bun:",nullzero,notnull,unique"`                                    // sha256 of the secret, which is also the token of Google Reader API sessions
+	FeverKeyHash string    `bun:",nullzero,notnull,unique"`                                    // sha256 of the key of the Fever API, the md5 of "username:password"
+}
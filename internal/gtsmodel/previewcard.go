@@ -0,0 +1,53 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gtsmodel
+
+import "time"
+
+// PreviewCard is the preview of a link of a status, read from the
+// OpenGraph and Twitter card metadata of the linked page.
+type PreviewCard struct {
+	ID           string           `bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                    // id of this item in the database
+	CreatedAt    time.Time        `bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt    time.Time        `bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	URL          string           `bun:",nullzero,notnull,unique"`                                    // url of the previewed page
+	Title        string           `bun:",nullzero"`                                                   // title of the page
+	Description  string           `bun:",nullzero"`                                                   // description of the page
+	Type         PreviewCardType  `bun:",nullzero,notnull"`                                           // type of the previewed resource
+	AuthorName   string           `bun:",nullzero"`                                                   // author of the page
+	AuthorURL    string           `bun:",nullzero"`                                                   // link to the author of the page
+	ProviderName string           `bun:",nullzero"`                                                   // name of the website of the page
+	ProviderURL  string           `bun:",nullzero"`                                                   // url of the website of the page
+	HTML         string           `bun:",nullzero"`                                                   // html of the embedded player, for videos
+	Width        int              `bun:",nullzero"`                                                   // width of the image, or of the player
+	Height       int              `bun:",nullzero"`                                                   // height of the image, or of the player
+	EmbedURL     string           `bun:",nullzero"`                                                   // url of the embedded player or photo
+	ImageID      string           `bun:"type:CHAR(26),nullzero"`                                      // id of the media attachment caching the image of the page
+	Image        *MediaAttachment `bun:"-"`                                                           // media attachment corresponding to imageID
+}
+
+// PreviewCardType is the type of resource previewed by a card.
+type PreviewCardType string
+
+// PreviewCardType values.
+const (
+	PreviewCardTypeLink  PreviewCardType = "link"
+	PreviewCardTypePhoto PreviewCardType = "photo"
+	PreviewCardTypeVideo PreviewCardType = "video"
+	PreviewCardTypeRich  PreviewCardType = "rich"
+)
@@ -0,0 +1,188 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gtsmodel
+
+import "time"
+
+// RssFeed models the source feed of a local feed account, and its per-feed settings.
+type RssFeed struct {
+	ID                      string            `bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                    // id of this item in the database
+	CreatedAt               time.Time         `bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt               time.Time         `bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	AccountID               string            `bun:"type:CHAR(26),nullzero,notnull,unique"`                       // id of the feed account
+	Account                 *Account          `bun:"-"`                                                           // feed account corresponding to accountID
+	URL                     string            `bun:",nullzero,notnull"`                                           // url of the polled feed
+	MediaType               string            `bun:",nullzero"`                                                   // media type of the feed document, such as application/rss+xml
+	LastItemAt              time.Time         `bun:"type:timestamptz,nullzero"`                                   // publication date of the most recent ingested item
+	Digest                  RssDigest         `bun:",nullzero"`                                                   // publish items as a periodic digest instead of one status each
+	DigestedAt              time.Time         `bun:"type:timestamptz,nullzero"`                                   // when was the last digest published (or digest mode enabled)
+	DigestLastStatusID      string            `bun:"type:CHAR(26),nullzero"`                                      // statuses of the account with a greater id are waiting for the next digest
+	Visibility              Visibility        `bun:",nullzero"`                                                   // visibility of the feed statuses, server default if not set
+	Federated               *bool             `bun:",nullzero"`                                                   // federate the feed statuses, server default if not set
+	Sensitive               *bool             `bun:",nullzero"`                                                   // mark the feed statuses as sensitive, server default if not set
+	ContentWarning          *string           `bun:",nullzero"`                                                   // content warning text of the feed statuses, server default if not set
+	ContentWarningFromTitle *bool             `bun:",nullzero"`                                                   // use the item title as content warning, server default if not set
+	Boostable               *bool             `bun:",nullzero"`                                                   // feed statuses can be boosted, server default if not set
+	Likeable                *bool             `bun:",nullzero"`                                                   // feed statuses can be liked, server default if not set
+	Replyable               *bool             `bun:",nullzero"`                                                   // feed statuses can be replied to, server default if not set
+	ReplyWebhookURL         string            `bun:",nullzero"`                                                   // url receiving a POST for each reply to a feed status
+	ReplyWebhookSecret      string            `bun:",nullzero"`                                                   // key of the HMAC-SHA256 signature of the reply webhook requests
+	ReplyEmail              string            `bun:",nullzero"`                                                   // email address receiving each reply to a feed status
+	RepliesForwardedID      string            `bun:"type:CHAR(26),nullzero"`                                      // notifications of the account with a greater id have not been forwarded yet
+	Byline                  *bool             `bun:",nullzero"`                                                   // credit the item authors in the feed statuses, server default if not set
+	AuthorHandles           map[string]string `bun:",nullzero"`                                                   // fediverse handles (@user@domain) of the item authors, by author name
+	Scraper                 *RssScraper       `bun:",nullzero"`                                                   // selectors of the items of the web page at url, for websites without any feed
+	RetentionDays           *int              `bun:",nullzero"`                                                   // days the feed statuses are kept for, server default if not set, 0 forever
+	RetentionItems          *int              `bun:",nullzero"`                                                   // number of most recent feed statuses kept, server default if not set, 0 all
+	ProfileDisplayName      string            `bun:",nullzero"`                                                   // display name of the feed account as last set from the feed, not refreshed anymore once edited
+	ProfileNote             string            `bun:",nullzero"`                                                   // note of the feed account as last set from the feed, not refreshed anymore once edited
+}
+
+// RssFeedDelegate allows a local account to act on behalf of a feed account,
+// which can't log in, through its own OAuth tokens.
+type RssFeedDelegate struct {
+	ID                string    `bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                                          // id of this item in the database
+	CreatedAt         time.Time `bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                       // when was item created
+	AccountID         string    `bun:"type:CHAR(26),unique:rss_feed_delegates_account_id_delegate_uniq,notnull,nullzero"` // id of the feed account
+	DelegateAccountID string    `bun:"type:CHAR(26),unique:rss_feed_delegates_account_id_delegate_uniq,notnull,nullzero"` // id of the local account acting on behalf of the feed account
+	DelegateAccount   *Account  `bun:"-"`                                                                                 // account corresponding to delegateAccountID
+}
+
+// RssFeedClaim is the request of a local account to own a feed account, verified once its
+// token is found in the feed document, or in a meta tag or rel="me" link of the feed website.
+type RssFeedClaim struct {
+	ID                string    `bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                                       // id of this item in the database
+	CreatedAt         time.Time `bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                    // when was item created
+	AccountID         string    `bun:"type:CHAR(26),unique:rss_feed_claims_account_id_claimant_uniq,notnull,nullzero"` // id of the feed account
+	ClaimantAccountID string    `bun:"type:CHAR(26),unique:rss_feed_claims_account_id_claimant_uniq,notnull,nullzero"` // id of the local account claiming the feed account
+	Token             string    `bun:",nullzero,notnull,unique"`                                                       // token to publish on the feed or its website
+	VerifiedAt        time.Time `bun:"type:timestamptz,nullzero"`                                                      // when was the token found, zero while pending
+}
+
+// RssArticle indexes the canonical url of an article ingested from a feed,
+// so that other feeds carrying it boost its status instead of posting it again.
+type RssArticle struct {
+	ID        string    `bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                    // id of this item in the database
+	CreatedAt time.Time `bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	URL       string    `bun:",nullzero,notnull,unique"`                                    // canonical url of the article
+	StatusID  string    `bun:"type:CHAR(26),nullzero,notnull"`                              // id of the status posted for the article
+	AccountID string    `bun:"type:CHAR(26),nullzero,notnull"`                              // id of the feed account which posted it
+}
+
+// RssItem records an item ingested from a feed, and is kept once its status is
+// deleted: retention only deletes the statuses of feed items, and scraped pages
+// still carrying the item of a deleted status don't post it again.
+type RssItem struct {
+	ID        string    `bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                    // id of this item in the database
+	CreatedAt time.Time `bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	AccountID string    `bun:"type:CHAR(26),nullzero,notnull"`                              // id of the feed account which posted it
+	StatusID  string    `bun:"type:CHAR(26),nullzero,notnull,unique"`                       // id of the status posted for the item
+	Link      string    `bun:",nullzero"`                                                   // link of the item, empty for newsletter messages
+}
+
+// RssCommentFeed keeps the cache headers of the comment feed of a recent item,
+// so that it is fetched with a conditional request on each poll.
+type RssCommentFeed struct {
+	ID           string    `bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                                    // id of this item in the database
+	CreatedAt    time.Time `bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                 // when was item created
+	AccountID    string    `bun:"type:CHAR(26),unique:rss_comment_feeds_account_id_url_uniq,notnull,nullzero"` // id of the feed account of the item
+	URL          string    `bun:",unique:rss_comment_feeds_account_id_url_uniq,notnull,nullzero"`              // url of the comment feed
+	ETag         string    `bun:",nullzero"`                                                                   // ETag of the last fetched document
+	LastModified time.Time `bun:"type:timestamptz,nullzero"`                                                   // Last-Modified date of the last fetched document
+}
+
+// RssCursor keeps the position of a background job of the feed
+// poller going through the statuses, across restarts.
+type RssCursor struct {
+	Name      string    `bun:",pk,nullzero,notnull,unique"`                                 // name of the job
+	UpdatedAt time.Time `bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	StatusID  string    `bun:"type:CHAR(26),nullzero,notnull"`                              // id of the last status the job went through
+}
+
+// RssWebhook receives a signed POST for each status posted from the items
+// of a feed account, or of every feed account when server-wide.
+type RssWebhook struct {
+	ID        string    `bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                    // id of this item in the database
+	CreatedAt time.Time `bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	AccountID string    `bun:"type:CHAR(26),nullzero"`                                      // id of the feed account, empty for a server-wide webhook
+	URL       string    `bun:",nullzero,notnull"`                                           // url receiving the POST requests
+	Secret    string    `bun:",nullzero,notnull"`                                           // key of the HMAC-SHA256 signature of the requests
+}
+
+// RssWebhookDelivery is a POST to a webhook waiting to be delivered, or given up on.
+type RssWebhookDelivery struct {
+	ID            string    `bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                    // id of this item in the database
+	CreatedAt     time.Time `bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	WebhookID     string    `bun:"type:CHAR(26),nullzero,notnull"`                              // id of the webhook, or of the feed for its reply webhook
+	Event         string    `bun:",nullzero,notnull,default:'status.created'"`                  // event delivered, status.created or reply.created
+	StatusID      string    `bun:"type:CHAR(26),nullzero,notnull"`                              // id of the posted status, or of the forwarded reply
+	Payload       string    `bun:",nullzero,notnull"`                                           // JSON body, rendered when the status was posted
+	Attempts      int       `bun:",notnull,default:0"`                                          // number of failed attempts so far
+	NextAttemptAt time.Time `bun:"type:timestamptz,nullzero"`                                   // when is the next attempt due, zero once given up on
+	LastAttemptAt time.Time `bun:"type:timestamptz,nullzero"`                                   // when was the last failed attempt
+	LastError     string    `bun:",nullzero"`                                                   // error of the last failed attempt
+	FailedAt      time.Time `bun:"type:timestamptz,nullzero"`                                   // when was the delivery given up on, zero while pending
+}
+
+// RssScraper selects the items of a web page without any feed. Selectors are XPath
+// expressions when they start with "/", "." or "(", and CSS selectors otherwise.
+type RssScraper struct {
+	Items   string `json:"items"`   // selector of the item containers in the page
+	Title   string `json:"title"`   // selector of the item title, in its container
+	Link    string `json:"link"`    // selector of the item link (its href), in its container; the first link if empty
+	Date    string `json:"date"`    // selector of the item date (its datetime attribute or its text), in its container; optional
+	Summary string `json:"summary"` // selector of the item summary, in its container; optional
+}
+
+// RssNewsletter is an email newsletter published by a proxy account,
+// whose messages are received at its own email address.
+type RssNewsletter struct {
+	ID             string    `bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                    // id of this item in the database
+	CreatedAt      time.Time `bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	AccountID      string    `bun:"type:CHAR(26),notnull,nullzero,unique"`                       // id of the newsletter account
+	Address        string    `bun:",nullzero,notnull,unique"`                                    // email address subscribed to the newsletter, lowercase
+	UnsubscribeURL string    `bun:",nullzero"`                                                   // List-Unsubscribe link of the latest message
+	ConfirmURL     string    `bun:",nullzero"`                                                   // link of the latest subscription confirmation message, to be visited by an admin
+	LastMessageAt  time.Time `bun:"type:timestamptz,nullzero"`                                   // when was the latest message received
+}
+
+// RssDigest is the period between two digests of a feed.
+type RssDigest string
+
+// RssDigest values.
+const (
+	RssDigestNone   RssDigest = ""
+	RssDigestHourly RssDigest = "hourly"
+	RssDigestDaily  RssDigest = "daily"
+	RssDigestWeekly RssDigest = "weekly"
+)
+
+// Period returns the duration between two digests,
+// or 0 if items are not published as a digest.
+func (d RssDigest) Period() time.Duration {
+	switch d {
+	case RssDigestHourly:
+		return time.Hour
+	case RssDigestDaily:
+		return 24 * time.Hour
+	case RssDigestWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
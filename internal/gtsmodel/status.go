@@ -57,6 +57,8 @@ type Status struct {
 	ThreadID                 string             `bun:"type:CHAR(26),nullzero"`                                      // id of the thread to which this status belongs; only set for remote statuses if a local account is involved at some point in the thread, otherwise null
 	PollID                   string             `bun:"type:CHAR(26),nullzero"`                                      //
 	Poll                     *Poll              `bun:"-"`                                                           //
+	PreviewCardID            string             `bun:"type:CHAR(26),nullzero"`                                      // id of the preview card of the link of this status
+	PreviewCard              *PreviewCard       `bun:"-"`                                                           // preview card corresponding to previewCardID
 	ContentWarning           string             `bun:",nullzero"`                                                   // cw string for this status
 	Visibility               Visibility         `bun:",nullzero,notnull"`                                           // visibility entry for this status
 	Sensitive                *bool              `bun:",nullzero,notnull,default:false"`                             // mark the status as sensitive?
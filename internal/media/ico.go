@@ -0,0 +1,150 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/bmp"
+)
+
+// icoToPNG converts an ico file to a png of its largest
+// image, as the rest of the pipeline can't decode icos.
+func icoToPNG(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := decodeICO(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decodeICO decodes the largest image of an ico file,
+// stored either as png or as a headerless bmp.
+func decodeICO(data []byte) (image.Image, error) {
+	if len(data) < 6 || binary.LittleEndian.Uint16(data[2:4]) != 1 {
+		return nil, errors.New("invalid ico header")
+	}
+
+	count := int(binary.LittleEndian.Uint16(data[4:6]))
+	if len(data) < 6+16*count {
+		return nil, errors.New("truncated ico directory")
+	}
+
+	var entry []byte
+	bestSize, bestBpp := 0, 0
+	for i := 0; i < count; i++ {
+		dir := data[6+16*i : 6+16*(i+1)]
+
+		// A width of 0 means 256 pixels.
+		size := int(dir[0])
+		if size == 0 {
+			size = 256
+		}
+		bpp := int(binary.LittleEndian.Uint16(dir[6:8]))
+		length := int(binary.LittleEndian.Uint32(dir[8:12]))
+		offset := int(binary.LittleEndian.Uint32(dir[12:16]))
+		if offset < 0 || length <= 0 || offset+length > len(data) {
+			continue
+		}
+
+		if size > bestSize || (size == bestSize && bpp > bestBpp) {
+			entry = data[offset : offset+length]
+			bestSize, bestBpp = size, bpp
+		}
+	}
+
+	if entry == nil {
+		return nil, errors.New("no valid image in ico")
+	}
+
+	if bytes.HasPrefix(entry, []byte("\x89PNG")) {
+		return png.Decode(bytes.NewReader(entry))
+	}
+
+	return decodeICOBitmap(entry)
+}
+
+// decodeICOBitmap decodes the bmp of an ico entry: a bitmap info
+// header whose height counts the image and its transparency mask,
+// then the pixels, bottom-up.
+func decodeICOBitmap(entry []byte) (image.Image, error) {
+	if len(entry) < 40 {
+		return nil, errors.New("truncated ico bitmap")
+	}
+
+	headerLen := int(binary.LittleEndian.Uint32(entry[0:4]))
+	width := int(int32(binary.LittleEndian.Uint32(entry[4:8])))
+	height := int(int32(binary.LittleEndian.Uint32(entry[8:12]))) / 2
+	bpp := binary.LittleEndian.Uint16(entry[14:16])
+	if width <= 0 || height <= 0 || headerLen < 40 || headerLen > len(entry) {
+		return nil, errors.New("invalid ico bitmap header")
+	}
+
+	if bpp != 32 {
+		// Let the bmp decoder read lower depths (opaque), once
+		// given the file header and the height of the image alone.
+		header := make([]byte, 14, 14+len(entry))
+		copy(header, "BM")
+		binary.LittleEndian.PutUint32(header[2:6], uint32(14+len(entry)))
+		offset := 14 + headerLen
+		if bpp == 8 {
+			colors := int(binary.LittleEndian.Uint32(entry[32:36]))
+			if colors == 0 {
+				colors = 256
+			}
+			offset += 4 * colors
+		}
+		binary.LittleEndian.PutUint32(header[10:14], uint32(offset))
+
+		file := append(header, entry...)
+		binary.LittleEndian.PutUint32(file[14+8:14+12], uint32(height))
+		return bmp.Decode(bytes.NewReader(file))
+	}
+
+	pixels := entry[headerLen:]
+	if len(pixels) < width*height*4 {
+		return nil, errors.New("truncated ico bitmap")
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		row := pixels[(height-1-y)*width*4:]
+		for x := 0; x < width; x++ {
+			p := row[x*4 : x*4+4]
+			img.SetNRGBA(x, y, color.NRGBA{R: p[2], G: p[1], B: p[0], A: p[3]})
+		}
+	}
+
+	return img, nil
+}
@@ -262,6 +262,18 @@ func (p *ProcessingMedia) store(ctx context.Context) error {
 	case "gif":
 		// No problem
 
+	case "ico":
+		// Icons (such as the favicons of feed accounts)
+		// are converted to png to be processed further.
+		data, err := icoToPNG(r)
+		if err != nil {
+			return gtserror.Newf("error converting ico: %w", err)
+		}
+		r = bytes.NewReader(data)
+		fileSize = len(data)
+		p.media.File.FileSize = fileSize
+		info = filetype.GetType("png")
+
 	case "jpg", "jpeg", "png", "webp":
 		if fileSize > 0 {
 			// A file size was provided so we can clean
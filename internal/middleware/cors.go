@@ -59,6 +59,9 @@ func CORS() gin.HandlerFunc {
 			//   - https://github.com/superseriousbusiness/gotosocial/issues/1664
 			"Idempotency-Key",
 
+			// needed to act on behalf of feed accounts
+			ActAsHeader,
+
 			// needed for websocket upgrade requests
 			"Upgrade",
 			"Sec-WebSocket-Extensions",
@@ -27,6 +27,24 @@ import (
 	"github.com/superseriousbusiness/oauth2/v4"
 )
 
+// ActAsHeader is the request header holding the ID of the feed
+// account the user of the token acts on behalf of, as its delegate.
+const ActAsHeader = "X-Act-As-Account"
+
+// actAsRoutes are the routes a delegate may call on behalf of a feed
+// account, by method then full path: posting, pinning, and editing the
+// profile. Requests to any other route with an ActAsHeader are refused.
+var actAsRoutes = map[string]map[string]bool{
+	http.MethodPost: {
+		"/api/v1/statuses":           true,
+		"/api/v1/statuses/:id/pin":   true,
+		"/api/v1/statuses/:id/unpin": true,
+	},
+	http.MethodPatch: {
+		"/api/v1/accounts/update_credentials": true,
+	},
+}
+
 // TokenCheck returns a new gin middleware for validating oauth tokens in requests.
 //
 // The middleware checks the request Authorization header for a valid oauth Bearer token.
@@ -40,7 +58,9 @@ import (
 // gin context for further processing by other functions.
 //
 // Next, it will look up the *gtsmodel.Account for the User. If the Account has been suspended, then the
-// middleware will return early. Otherwise, it will set the Account on the gin context too.
+// middleware will return early. Otherwise, it will set the Account on the gin context too. If the request
+// has an ActAsHeader, the feed account it names is set instead, provided the User is one of its delegates
+// and the route is one of actAsRoutes: the request is refused otherwise.
 //
 // Finally, it will check the client ID of the token to see if a *gtsmodel.Application can be retrieved
 // for that client ID. This will also be set on the gin context.
@@ -116,7 +136,41 @@ func TokenCheck(dbConn db.DB, validateBearerToken func(r *http.Request) (oauth2.
 				return
 			}
 
-			c.Set(oauth.SessionAuthorizedAccount, user.Account)
+			account := user.Account
+			if actAs := c.Request.Header.Get(ActAsHeader); actAs != "" && actAs != user.AccountID {
+				// The user acts on behalf of a feed account.
+				if !actAsRoutes[c.Request.Method][c.FullPath()] {
+					log.Warnf(ctx, "authenticated user %s can't act as account %s on %s %s", userID, actAs, c.Request.Method, c.FullPath())
+					respondBlocked(c)
+					return
+				}
+
+				delegate, err := dbConn.IsRssFeedDelegate(ctx, actAs, user.AccountID)
+				if err != nil {
+					respondInternalServerError(c, err)
+					return
+				}
+
+				if !delegate {
+					log.Warnf(ctx, "authenticated user %s is not a delegate of account %s", userID, actAs)
+					respondBlocked(c)
+					return
+				}
+
+				account, err = dbConn.GetAccountByID(ctx, actAs)
+				if err != nil {
+					respondInternalServerError(c, err)
+					return
+				}
+
+				if !account.SuspendedAt.IsZero() {
+					log.Warnf(ctx, "delegated account %s has been suspended", actAs)
+					respondBlocked(c)
+					return
+				}
+			}
+
+			c.Set(oauth.SessionAuthorizedAccount, account)
 		}
 
 		// check for application token
@@ -21,6 +21,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/gorilla/feeds"
@@ -28,6 +29,8 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 )
 
 const (
@@ -36,6 +39,45 @@ const (
 
 type GetRSSFeed func() (string, gtserror.WithCode)
 
+// FeedOptions select the statuses of an account feed.
+type FeedOptions struct {
+	// MaxID pages the feed to the statuses older than MaxID.
+	MaxID string
+	// Replies includes the replies of the account.
+	Replies bool
+	// Boosts includes the statuses boosted by the account.
+	Boosts bool
+	// MediaOnly restricts the feed to statuses with media attachments.
+	MediaOnly bool
+	// Tag restricts the feed to statuses with this hashtag.
+	Tag string
+	// Summary leaves the content out of the items, keeping
+	// their title and description.
+	Summary bool
+}
+
+// Query returns the url query selecting these options, without MaxID,
+// in a stable order so that it can be used as a cache key.
+func (o FeedOptions) Query() url.Values {
+	query := url.Values{}
+	if o.Replies {
+		query.Set("replies", "true")
+	}
+	if o.Boosts {
+		query.Set("boosts", "true")
+	}
+	if o.MediaOnly {
+		query.Set("media_only", "true")
+	}
+	if o.Tag != "" {
+		query.Set("tag", o.Tag)
+	}
+	if o.Summary {
+		query.Set("summary", "true")
+	}
+	return query
+}
+
 // GetRSSFeedForUsername returns a function to return the RSS feed of a local account
 // with the given username, and the last-modified time (time that the account last
 // posted a status eligible to be included in the rss feed).
@@ -46,6 +88,15 @@ type GetRSSFeed func() (string, gtserror.WithCode)
 // If the account has not yet posted an RSS-eligible status, the returned last-modified
 // time will be zero, and the GetRSSFeed func will return a valid RSS xml with no items.
 func (p *Processor) GetRSSFeedForUsername(ctx context.Context, username string) (GetRSSFeed, time.Time, gtserror.WithCode) {
+	return p.GetFeedForUsername(ctx, username, typeutils.FeedFormatRSS, FeedOptions{})
+}
+
+// GetFeedForUsername is like GetRSSFeedForUsername, with the returned function
+// serializing to format the page of the feed selected by opts.
+//
+// Feeds are paged as described by RFC 5005: every page links
+// to the first one and, unless it's the last, to the next one.
+func (p *Processor) GetFeedForUsername(ctx context.Context, username string, format typeutils.FeedFormat, opts FeedOptions) (GetRSSFeed, time.Time, gtserror.WithCode) {
 	var (
 		never = time.Time{}
 	)
@@ -77,6 +128,28 @@ func (p *Processor) GetRSSFeedForUsername(ctx context.Context, username string)
 		}
 	}
 
+	// Resolve the tag to restrict the feed to, if any.
+	var tagID string
+	if opts.Tag != "" {
+		tagName, ok := text.NormalizeHashtag(opts.Tag)
+		if !ok {
+			err = gtserror.Newf("string '%s' could not be normalized to a valid hashtag", opts.Tag)
+			return nil, never, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+
+		tag, err := p.state.DB.GetTagByName(ctx, tagName)
+		if err != nil {
+			if errors.Is(err, db.ErrNoEntries) {
+				err = gtserror.Newf("tag %s not found", opts.Tag)
+				return nil, never, gtserror.NewErrorNotFound(err)
+			}
+
+			err = gtserror.Newf("db error getting tag %s: %w", opts.Tag, err)
+			return nil, never, gtserror.NewErrorInternalError(err)
+		}
+		tagID = tag.ID
+	}
+
 	// LastModified time is needed by callers to check freshness for cacheing.
 	// This might be a zero time.Time if account has never posted a status that's
 	// eligible to appear in the RSS feed; that's fine.
@@ -85,6 +158,7 @@ func (p *Processor) GetRSSFeedForUsername(ctx context.Context, username string)
 	return func() (string, gtserror.WithCode) {
 		// Assemble author namestring once only.
 		author := "@" + account.Username + "@" + config.GetAccountDomain()
+		links := feedLinks(account.URL+"/feed."+format.Extension(), opts, "")
 
 		// Derive image/thumbnail for this account (may be nil).
 		image, errWithCode := p.rssImageForAccount(ctx, account, author)
@@ -108,7 +182,7 @@ func (p *Processor) GetRSSFeedForUsername(ctx context.Context, username string)
 		// since we already know there's no eligible statuses.
 		if lastPostAt.IsZero() {
 			feed.Updated = account.CreatedAt
-			return stringifyFeed(feed)
+			return stringifyFeed(feed, format, links, nil)
 		}
 
 		// Account has posted at least one status that's
@@ -117,25 +191,55 @@ func (p *Processor) GetRSSFeedForUsername(ctx context.Context, username string)
 		// Reuse the lastPostAt value for feed.Updated.
 		feed.Updated = lastPostAt
 
-		// Retrieve latest statuses as they'd be shown on the web view of the account profile.
-		statuses, err := p.state.DB.GetAccountWebStatuses(ctx, account.ID, rssFeedLength, "")
+		// Retrieve the page of statuses, as they'd be shown
+		// on the web view of the account profile by default.
+		statuses, err := p.state.DB.GetAccountFeedStatuses(ctx,
+			account.ID,
+			rssFeedLength,
+			opts.Replies,
+			opts.Boosts,
+			opts.MediaOnly,
+			tagID,
+			opts.MaxID,
+		)
 		if err != nil && !errors.Is(err, db.ErrNoEntries) {
-			err = fmt.Errorf("db error getting account web statuses: %w", err)
+			err = fmt.Errorf("db error getting account feed statuses: %w", err)
 			return "", gtserror.NewErrorInternalError(err)
 		}
 
-		// Add each status to the rss feed.
+		// A full page may be followed by older statuses.
+		if len(statuses) == rssFeedLength {
+			links = feedLinks(account.URL+"/feed."+format.Extension(), opts, statuses[len(statuses)-1].ID)
+		}
+
+		// Boosted statuses are listed as items of their own.
+		items := make([]*gtsmodel.Status, 0, len(statuses))
 		for _, status := range statuses {
+			if status.BoostOfID != "" {
+				if status.BoostOf == nil || status.BoostOf.Visibility != gtsmodel.VisibilityPublic {
+					continue
+				}
+				status = status.BoostOf
+			}
+			items = append(items, status)
+		}
+
+		// Add each status to the rss feed.
+		for _, status := range items {
 			item, err := p.converter.StatusToRSSItem(ctx, status)
 			if err != nil {
 				err = gtserror.Newf("error converting status to feed item: %w", err)
 				return "", gtserror.NewErrorInternalError(err)
 			}
 
+			if opts.Summary {
+				item.Content = ""
+			}
+
 			feed.Add(item)
 		}
 
-		return stringifyFeed(feed)
+		return stringifyFeed(feed, format, links, items)
 	}, lastPostAt, nil
 }
 
@@ -168,14 +272,48 @@ func (p *Processor) rssImageForAccount(ctx context.Context, account *gtsmodel.Ac
 	}, nil
 }
 
-func stringifyFeed(feed *feeds.Feed) (string, gtserror.WithCode) {
+// feedLinks returns the links of the page of an account feed served
+// at feedURL with opts. nextMaxID is the MaxID of the next page, if any.
+func feedLinks(feedURL string, opts FeedOptions, nextMaxID string) typeutils.FeedLinks {
+	query := opts.Query()
+	links := typeutils.FeedLinks{Self: feedURL}
+
+	// Unpaged, unfiltered feeds are
+	// served without paging links.
+	if len(query) == 0 && opts.MaxID == "" && nextMaxID == "" {
+		return links
+	}
+
+	links.First = feedURL
+	if len(query) > 0 {
+		links.First += "?" + query.Encode()
+	}
+
+	if opts.MaxID != "" {
+		query.Set("max_id", opts.MaxID)
+		links.Self = feedURL + "?" + query.Encode()
+	} else {
+		links.Self = links.First
+	}
+
+	if nextMaxID != "" {
+		query.Set("max_id", nextMaxID)
+		links.Next = feedURL + "?" + query.Encode()
+	}
+
+	return links
+}
+
+// stringifyFeed serializes the feed to format. The
+// items of the feed must be the converted statuses.
+func stringifyFeed(feed *feeds.Feed, format typeutils.FeedFormat, links typeutils.FeedLinks, statuses []*gtsmodel.Status) (string, gtserror.WithCode) {
 	// Stringify the feed. Even with no statuses,
-	// this will still produce valid rss xml.
-	rss, err := feed.ToRss()
+	// this will still produce a valid document.
+	str, err := typeutils.FeedToString(feed, format, links, statuses)
 	if err != nil {
-		err := gtserror.Newf("error converting feed to rss string: %w", err)
+		err := gtserror.Newf("error converting feed to string: %w", err)
 		return "", gtserror.NewErrorInternalError(err)
 	}
 
-	return rss, nil
+	return str, nil
 }
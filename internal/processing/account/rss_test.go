@@ -22,6 +22,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/processing/account"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 )
 
 type GetRSSTestSuite struct {
@@ -73,6 +75,60 @@ func (suite *GetRSSTestSuite) TestGetAccountRSSZorkNoPosts() {
 	suite.Equal("<?xml version=\"1.0\" encoding=\"UTF-8\"?><rss version=\"2.0\" xmlns:content=\"http://purl.org/rss/1.0/modules/content/\">\n  <channel>\n    <title>Posts from @the_mighty_zork@localhost:8080</title>\n    <link>http://localhost:8080/@the_mighty_zork</link>\n    <description>Posts from @the_mighty_zork@localhost:8080</description>\n    <pubDate>Fri, 20 May 2022 11:09:18 +0000</pubDate>\n    <lastBuildDate>Fri, 20 May 2022 11:09:18 +0000</lastBuildDate>\n    <image>\n      <url>http://localhost:8080/fileserver/01F8MH1H7YV1Z7D2C8K2730QBF/avatar/small/01F8MH58A357CV5K7R7TJMSH6S.jpg</url>\n      <title>Avatar for @the_mighty_zork@localhost:8080</title>\n      <link>http://localhost:8080/@the_mighty_zork</link>\n    </image>\n  </channel>\n</rss>", feed)
 }
 
+func (suite *GetRSSTestSuite) TestGetAccountAtomAdmin() {
+	getFeed, lastModified, err := suite.accountProcessor.GetFeedForUsername(context.Background(), "admin", typeutils.FeedFormatAtom, account.FeedOptions{})
+	suite.NoError(err)
+	suite.EqualValues(1634726497, lastModified.Unix())
+
+	feed, err := getFeed()
+	suite.NoError(err)
+	suite.Contains(feed, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	suite.Contains(feed, `<updated>2021-10-20T10:41:37Z</updated>`)
+	suite.Contains(feed, `<summary type="text">open to see some puppies</summary>`)
+	suite.Contains(feed, `<published>2021-10-20T11:36:45Z</published>`)
+	suite.Contains(feed, `<link href="http://localhost:8080/fileserver/01F8MH17FWEB39HZJ76B6VXSKF/attachment/original/01F8MH6NEM8D7527KZAECTCR76.jpg" rel="enclosure" type="image/jpeg" length="62529"></link>`)
+	suite.NotContains(feed, `<link href="" rel="enclosure"></link>`)
+}
+
+func (suite *GetRSSTestSuite) TestGetAccountJSONAdmin() {
+	getFeed, lastModified, err := suite.accountProcessor.GetFeedForUsername(context.Background(), "admin", typeutils.FeedFormatJSON, account.FeedOptions{})
+	suite.NoError(err)
+	suite.EqualValues(1634726497, lastModified.Unix())
+
+	feed, err := getFeed()
+	suite.NoError(err)
+	suite.Contains(feed, `"feed_url": "http://localhost:8080/@admin/feed.json"`)
+	suite.Contains(feed, `"summary": "open to see some puppies"`)
+	suite.Contains(feed, `"url": "http://localhost:8080/fileserver/01F8MH17FWEB39HZJ76B6VXSKF/attachment/original/01F8MH6NEM8D7527KZAECTCR76.jpg"`)
+	suite.Contains(feed, `"mime_type": "image/jpeg"`)
+	suite.Contains(feed, `"date_published": "2021-10-20T11:36:45Z"`)
+}
+
+func (suite *GetRSSTestSuite) TestGetAccountRSSFiltered() {
+	getFeed, _, err := suite.accountProcessor.GetFeedForUsername(context.Background(), "admin", typeutils.FeedFormatRSS, account.FeedOptions{
+		MediaOnly: true,
+		Tag:       "Welcome",
+		Summary:   true,
+	})
+	suite.NoError(err)
+
+	feed, err := getFeed()
+	suite.NoError(err)
+	suite.Contains(feed, `<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/" xmlns:atom="http://www.w3.org/2005/Atom">`)
+	suite.Contains(feed, `<atom:link href="http://localhost:8080/@admin/feed.rss?media_only=true&amp;summary=true&amp;tag=Welcome" rel="first"></atom:link>`)
+	suite.Contains(feed, `<guid>http://localhost:8080/@admin/statuses/01F8MH75CBF9JFX4ZAD54N0W0R</guid>`)
+	suite.NotContains(feed, `<guid>http://localhost:8080/@admin/statuses/01F8MHAAY43M6RJ473VQFCVH37</guid>`)
+	suite.NotContains(feed, `rel="next"`)
+	suite.NotContains(feed, `<content:encoded>`)
+}
+
+func (suite *GetRSSTestSuite) TestGetAccountRSSUnknownTag() {
+	_, _, err := suite.accountProcessor.GetFeedForUsername(context.Background(), "admin", typeutils.FeedFormatRSS, account.FeedOptions{
+		Tag: "doesnotexist",
+	})
+	suite.Error(err)
+}
+
 func TestGetRSSTestSuite(t *testing.T) {
 	suite.Run(t, new(GetRSSTestSuite))
 }
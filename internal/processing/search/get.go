@@ -462,6 +462,8 @@ func (p *Processor) accountByUsernameDomain(
 // byURI looks for account(s) or a status with the given URI
 // set as either its URL or ActivityPub URI. If it gets hits, it
 // will call the provided append functions to return results.
+// When resolving, URIs which are neither fall back to feed
+// discovery, returning the feed account of the website.
 //
 // The boolean return value indicates to the caller whether the
 // search should continue (true) or stop (false). False will be
@@ -539,11 +541,47 @@ func (p *Processor) byURI(
 		}
 	}
 
+	if resolve && includeAccounts(queryType) {
+		// Check if URI points to a website or feed.
+		if foundAccount := p.accountByFeedURI(ctx, uri); foundAccount != nil {
+			appendAccount(foundAccount)
+			return nil
+		}
+	}
+
 	// No errors, but no hits
 	// either; that's fine.
 	return nil
 }
 
+// accountByFeedURI returns the feed account of the website or
+// feed at the given URI, creating it through the feed resolver
+// if necessary, or nil if the URI doesn't lead to any feed.
+func (p *Processor) accountByFeedURI(
+	ctx context.Context,
+	uri *url.URL,
+) *gtsmodel.Account {
+	if p.feeds == nil || uri.Host == config.GetHost() || uri.Host == config.GetAccountDomain() {
+		// No feeds, or our own
+		// pages, which aren't.
+		return nil
+	}
+
+	username, err := p.feeds.NewUser(ctx, uri.String())
+	if err != nil || username == "" {
+		log.Debugf(ctx, "no feed found at %s: %v", uri, err)
+		return nil
+	}
+
+	account, err := p.state.DB.GetAccountByUsernameDomain(ctx, username, "")
+	if err != nil {
+		log.Errorf(ctx, "error getting feed account %s: %v", username, err)
+		return nil
+	}
+
+	return account
+}
+
 // accountByURI looks for one account with the given URI.
 // If resolve is false, it will only look in the database.
 // If resolve is true, it will try to resolve the account
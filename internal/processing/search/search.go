@@ -18,6 +18,8 @@
 package search
 
 import (
+	"context"
+
 	"github.com/superseriousbusiness/gotosocial/internal/federation"
 	"github.com/superseriousbusiness/gotosocial/internal/filter/visibility"
 	"github.com/superseriousbusiness/gotosocial/internal/state"
@@ -29,6 +31,15 @@ type Processor struct {
 	federator *federation.Federator
 	converter *typeutils.Converter
 	filter    *visibility.Filter
+	feeds     FeedResolver
+}
+
+// FeedResolver finds or creates the feed account
+// of a website or feed url that isn't ActivityPub.
+type FeedResolver interface {
+	// NewUser returns the username of the feed account of resource,
+	// creating it if the feed is not followed by this instance yet.
+	NewUser(ctx context.Context, resource string) (string, error)
 }
 
 // New returns a new status processor.
@@ -40,3 +51,9 @@ func New(state *state.State, federator *federation.Federator, converter *typeuti
 		filter:    filter,
 	}
 }
+
+// SetFeedResolver sets the resolver of the urls which
+// don't point to ActivityPub accounts or statuses.
+func (p *Processor) SetFeedResolver(feeds FeedResolver) {
+	p.feeds = feeds
+}
@@ -763,16 +763,16 @@ func (f *federate) UpdateAccount(ctx context.Context, account *gtsmodel.Account)
 		return err
 	}
 
-	// Convert account to ActivityStreams Person.
-	person, err := f.converter.AccountToAS(ctx, account)
+	// Convert account to ActivityStreams actor.
+	accountable, err := f.converter.AccountToAS(ctx, account)
 	if err != nil {
-		return gtserror.Newf("error converting account to Person: %w", err)
+		return gtserror.Newf("error converting account to actor: %w", err)
 	}
 
-	// Use ActivityStreams Person as Object of Update.
-	update, err := f.converter.WrapPersonInUpdate(person, account)
+	// Use ActivityStreams actor as Object of Update.
+	update, err := f.converter.WrapAccountableInUpdate(accountable, account)
 	if err != nil {
-		return gtserror.Newf("error wrapping Person in Update: %w", err)
+		return gtserror.Newf("error wrapping actor in Update: %w", err)
 	}
 
 	// Send the Update via the Actor's outbox.
@@ -0,0 +1,274 @@
+package rss
+
+import (
+	"context"
+	"encoding/json"
+	"html"
+	"io"
+	netUrl "net/url"
+	"strings"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/mmcdole/gofeed"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtscontext"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+// maxActorSize is the maximum size of the actor of a credited
+// account, read for its attribution domains.
+const maxActorSize = 1 << 20
+
+// itemAuthor is an author credited in the byline of an item, with
+// the mention of their fediverse account if their handle is known.
+type itemAuthor struct {
+	Name   string
+	Handle string
+	// Mapped is true if the handle comes from the author handles of the feed,
+	// rather than from the fediverse:creator meta tag of the item page.
+	Mapped  bool
+	Mention *gtsmodel.Mention
+}
+
+// byline returns the paragraph crediting the authors of item (its author
+// or dc:creator elements, and the creator advertised by its page), and the mentions of the fediverse accounts of
+// those whose handle is mapped by feed. Handles advertised by the item
+// page are only mentioned if their account lists the website among its
+// attribution domains, and credited as text otherwise.
+func (n *rssTooter) byline(ctx context.Context, account *gtsmodel.Account, statusID string, feed *gtsmodel.RssFeed, item *gofeed.Item, page *itemPage) (string, []*gtsmodel.Mention) {
+	var authors []*itemAuthor
+	for _, name := range itemAuthorNames(item) {
+		handle := authorHandle(feed, name)
+		authors = append(authors, &itemAuthor{Name: name, Handle: handle, Mapped: handle != ""})
+	}
+
+	if creator := n.pageCreator(ctx, page); creator != "" {
+		known := false
+		for _, author := range authors {
+			known = known || strings.EqualFold(author.Handle, creator)
+		}
+
+		switch {
+		case known:
+			// Already credited through the feed mapping.
+		case len(authors) == 1 && authors[0].Handle == "":
+			// The page is the one of the only author.
+			authors[0].Handle = creator
+		default:
+			authors = append(authors, &itemAuthor{Handle: creator})
+		}
+	}
+
+	if len(authors) == 0 {
+		return "", nil
+	}
+
+	var mentions []*gtsmodel.Mention
+	credits := make([]string, 0, len(authors))
+	for _, author := range authors {
+		if author.Handle != "" {
+			mention, err := n.mentionHandle(ctx, account, statusID, author.Handle)
+			switch {
+			case err != nil:
+				log.Warnf(ctx, "Failed to mention author %s: %s", author.Handle, err)
+			case !author.Mapped && !n.attributes(ctx, mention.TargetAccount, item.Link):
+				log.Debugf(ctx, "Account %s doesn't list %s among its attribution domains", author.Handle, item.Link)
+			default:
+				author.Mention = mention
+				if !mentioned(mentions, mention) {
+					mentions = append(mentions, mention)
+				}
+			}
+		}
+		credits = append(credits, author.credit())
+	}
+
+	return "<p>By " + strings.Join(credits, ", ") + "</p>", mentions
+}
+
+// credit returns the html crediting the author in a byline.
+func (a *itemAuthor) credit() string {
+	if a.Mention == nil {
+		switch {
+		case a.Handle == "":
+			return html.EscapeString(a.Name)
+		case a.Name == "":
+			return html.EscapeString(a.Handle)
+		default:
+			return html.EscapeString(a.Name) + " (" + html.EscapeString(a.Handle) + ")"
+		}
+	}
+
+	mention := `<span class="h-card"><a href="` + html.EscapeString(a.Mention.TargetAccount.URL) +
+		`" class="u-url mention">@<span>` + html.EscapeString(a.Mention.TargetAccount.Username) + `</span></a></span>`
+	if a.Name == "" {
+		return mention
+	}
+	return html.EscapeString(a.Name) + " (" + mention + ")"
+}
+
+// itemAuthorNames returns the distinct names of the authors of
+// item, including every dc:creator while gofeed only keeps the first.
+func itemAuthorNames(item *gofeed.Item) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	add := func(name string) {
+		name = strings.TrimSpace(name)
+		if name != "" && !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			names = append(names, name)
+		}
+	}
+
+	for _, person := range item.Authors {
+		if person == nil {
+			continue
+		}
+		if person.Name != "" {
+			add(person.Name)
+		} else {
+			add(person.Email)
+		}
+	}
+
+	if item.DublinCoreExt != nil {
+		for _, creator := range item.DublinCoreExt.Creator {
+			add(creator)
+		}
+	}
+
+	return names
+}
+
+// authorHandle returns the handle mapped to the author name by feed, if any.
+func authorHandle(feed *gtsmodel.RssFeed, name string) string {
+	if feed == nil {
+		return ""
+	}
+	for author, handle := range feed.AuthorHandles {
+		if strings.EqualFold(author, name) {
+			return handle
+		}
+	}
+	return ""
+}
+
+// pageCreator returns the handle of the fediverse:creator
+// meta tag of the item page, empty if there is none.
+func (n *rssTooter) pageCreator(ctx context.Context, page *itemPage) string {
+	if !config.GetRssFetchAuthorHandles() || page.link == "" {
+		return ""
+	}
+
+	doc, err := page.load(ctx, n.fetcher)
+	if err != nil {
+		log.Debugf(ctx, "Failed to load item page %s: %s", page.link, err)
+		return ""
+	}
+
+	node := htmlquery.FindOne(doc, "//head/meta[@name='fediverse:creator']")
+	if node == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(htmlquery.SelectAttr(node, "content"))
+}
+
+// attributes returns whether the actor of target lists the website at
+// link among its attributionDomains, allowing it to credit the account
+// through its fediverse:creator meta tag. Local accounts list none.
+func (n *rssTooter) attributes(ctx context.Context, target *gtsmodel.Account, link string) bool {
+	u, err := netUrl.Parse(link)
+	if err != nil || u.Hostname() == "" || target.IsLocal() {
+		return false
+	}
+
+	uri, err := netUrl.Parse(target.URI)
+	if err != nil {
+		return false
+	}
+
+	tsport, err := n.transportController.NewTransportForUsername(ctx, "")
+	if err != nil {
+		log.Errorf(ctx, "Failed to create transport: %s", err)
+		return false
+	}
+
+	rsp, err := tsport.Dereference(gtscontext.SetFastFail(ctx), uri)
+	if err != nil {
+		log.Debugf(ctx, "Failed to dereference %s: %s", target.URI, err)
+		return false
+	}
+	defer rsp.Body.Close()
+
+	var actor struct {
+		AttributionDomains []string `json:"attributionDomains"`
+	}
+	if err := json.NewDecoder(io.LimitReader(rsp.Body, maxActorSize)).Decode(&actor); err != nil {
+		log.Debugf(ctx, "Failed to decode %s: %s", target.URI, err)
+		return false
+	}
+
+	return attributionDomain(actor.AttributionDomains, u.Hostname())
+}
+
+// attributionDomain returns whether host is one of
+// domains, or a subdomain of one of them.
+func attributionDomain(domains []string, host string) bool {
+	host = strings.ToLower(host)
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain != "" && (host == domain || strings.HasSuffix(host, "."+domain)) {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionHandle returns a mention by the status statusID of account of the
+// account with the given handle, dereferencing remote ones. As for mentions
+// parsed from statuses, it isn't stored.
+func (n *rssTooter) mentionHandle(ctx context.Context, account *gtsmodel.Account, statusID string, handle string) (*gtsmodel.Mention, error) {
+	username, host, err := util.ExtractNamestringParts(handle)
+	if err != nil {
+		return nil, err
+	}
+
+	var target *gtsmodel.Account
+	if host == "" || host == config.GetHost() || host == config.GetAccountDomain() {
+		target, err = n.state.DB.GetAccountByUsernameDomain(ctx, username, "")
+	} else {
+		target, _, err = n.dereferencer.GetAccountByUsernameDomain(gtscontext.SetFastFail(ctx), account.Username, username, host)
+	}
+	if err != nil {
+		return nil, gtserror.Newf("couldn't get account %s: %w", handle, err)
+	}
+
+	return &gtsmodel.Mention{
+		ID:               id.NewULID(),
+		StatusID:         statusID,
+		OriginAccountID:  account.ID,
+		OriginAccountURI: account.URI,
+		OriginAccount:    account,
+		TargetAccountID:  target.ID,
+		TargetAccountURI: target.URI,
+		TargetAccountURL: target.URL,
+		TargetAccount:    target,
+		NameString:       handle,
+	}, nil
+}
+
+// mentioned returns whether mentions already mention the target of mention.
+func mentioned(mentions []*gtsmodel.Mention, mention *gtsmodel.Mention) bool {
+	for _, m := range mentions {
+		if m.TargetAccountID == mention.TargetAccountID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package rss
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+type AuthorTestSuite struct {
+	RssStandardTestSuite
+}
+
+// createItem creates the status of the item of authors.xml with the given guid,
+// by local_account_1 turned into the feed account of the group blog.
+func (suite *AuthorTestSuite) createItem(guid string, feed *gtsmodel.RssFeed) *gtsmodel.Status {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_1"]
+
+	feed.ID = id.NewULID()
+	feed.AccountID = account.ID
+	feed.URL = suite.fixtureURL("authors.xml")
+	if err := suite.tooter.PutRssFeed(ctx, feed); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	httpFeed, err := suite.tooter.fetcher.FetchFeed(ctx, feed.URL, "", nil)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	for _, item := range httpFeed.Feed.Items {
+		if item.GUID == guid {
+			status, err := suite.tooter.createStatus(ctx, &ToCreate{Account: account, Item: item})
+			if err != nil {
+				suite.FailNow(err.Error())
+			}
+			return status
+		}
+	}
+
+	suite.FailNow("no item " + guid)
+	return nil
+}
+
+func (suite *AuthorTestSuite) TestBylineMappedHandle() {
+	status := suite.createItem("derailleur", &gtsmodel.RssFeed{
+		AuthorHandles: map[string]string{"bob": "@the_mighty_zork"},
+	})
+
+	suite.Contains(status.Content, `<p>By Alice, Bob (<span class="h-card"><a href="http://localhost:8080/@the_mighty_zork" class="u-url mention">@<span>the_mighty_zork</span></a></span>)</p>`)
+	suite.Len(status.MentionIDs, 1)
+
+	mention, err := suite.state.DB.GetMention(context.Background(), status.MentionIDs[0])
+	suite.NoError(err)
+	suite.Equal(suite.testAccounts["local_account_1"].ID, mention.TargetAccountID)
+}
+
+func (suite *AuthorTestSuite) TestBylinePageCreator() {
+	config.SetRssFetchAuthorHandles(true)
+
+	status := suite.createItem("century", &gtsmodel.RssFeed{})
+
+	// The only author gets the handle of the page, names are escaped. As the account
+	// doesn't list the website among its attribution domains, it isn't mentioned.
+	suite.Contains(status.Content, `<p>By Carol &amp; co (@admin@localhost:8080)</p>`)
+	suite.Empty(status.MentionIDs)
+}
+
+func (suite *AuthorTestSuite) TestAttributionDomain() {
+	domains := []string{"blog.example.org", "Example.com"}
+	suite.True(attributionDomain(domains, "blog.example.org"))
+	suite.True(attributionDomain(domains, "news.example.com"))
+	suite.False(attributionDomain(domains, "example.org"))
+	suite.False(attributionDomain(domains, "notexample.com"))
+	suite.False(attributionDomain(nil, "blog.example.org"))
+}
+
+func (suite *AuthorTestSuite) TestBylineDisabled() {
+	status := suite.createItem("derailleur", &gtsmodel.RssFeed{
+		Byline: util.Ptr(false),
+	})
+
+	suite.NotContains(status.Content, "By ")
+	suite.Empty(status.MentionIDs)
+}
+
+func (suite *AuthorTestSuite) TestBylineNoAuthor() {
+	status := suite.createItem("unsigned", &gtsmodel.RssFeed{})
+
+	suite.NotContains(status.Content, "By ")
+}
+
+func TestAuthorTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthorTestSuite))
+}
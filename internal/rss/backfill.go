@@ -0,0 +1,176 @@
+package rss
+
+import (
+	"context"
+	"encoding/xml"
+	netUrl "net/url"
+	"sort"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+)
+
+// feedLinks is the minimal shape of an Atom feed or RSS channel
+// needed to read the RFC 5005 navigation links (rel="next",
+// rel="prev-archive", ...). The plain RSS <link> element has
+// no rel attribute and is ignored.
+type feedLinks struct {
+	Links        []feedLink `xml:"link"`
+	ChannelLinks []feedLink `xml:"channel>link"`
+}
+
+type feedLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+// backfill imports the most recent items of a newly created feed account
+// as statuses, keeping their original publication dates.
+//
+// Statuses are only stored: they are not sent to the client API worker,
+// so they never reach home timelines nor notify followers.
+func (n *rssTooter) backfill(ctx context.Context, account *gtsmodel.Account, rssFeed *rssFeed) {
+	count := config.GetRssBackfillCount()
+	if count <= 0 {
+		return
+	}
+
+	items := datedItems(rssFeed.Feed.Items)
+	// Web pages without any feed have no archive.
+	if len(items) < count && config.GetRssBackfillArchivePages() > 0 && rssFeed.Feed.FeedType != scrapedFeedType {
+		items = n.archiveItems(ctx, rssFeed, items, count)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].PublishedParsed.Before(*items[j].PublishedParsed)
+	})
+
+	if len(items) > count {
+		items = items[len(items)-count:]
+	}
+
+	log.Infof(ctx, "Backfilling %d items for %s", len(items), account.Username)
+	for _, item := range items {
+		// Scraped items are published whatever their date,
+		// the poller may have posted them before this runs.
+		if item.Link != "" {
+			ingested, err := n.IsRssItemIngested(ctx, account.ID, item.Link)
+			if err != nil {
+				log.Errorf(ctx, "Failed to check backfilled item %s: %s", item.Link, err)
+				continue
+			}
+			if ingested {
+				continue
+			}
+		}
+
+		if _, err := n.storeItem(ctx, &ToCreate{Account: account, Item: item, Backfill: true}); err != nil {
+			log.Errorf(ctx, "Failed to backfill item %s: %s", item.Link, err)
+		}
+	}
+}
+
+// archiveItems follows the RFC 5005 paged / archived feed links of the
+// feed, whose own items are already known, adding unseen items until
+// count items are known or rss-backfill-archive-pages pages have been fetched.
+func (n *rssTooter) archiveItems(ctx context.Context, rssFeed *rssFeed, items []*gofeed.Item, count int) []*gofeed.Item {
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		seen[itemKey(item)] = true
+	}
+
+	visited := map[string]bool{rssFeed.FeedUrl.String(): true}
+	pageUrl := archiveLink(ctx, rssFeed.FeedUrl, rssFeed.Links)
+	for pages := config.GetRssBackfillArchivePages(); pageUrl != nil && pages > 0 && len(items) < count; pages-- {
+		if visited[pageUrl.String()] {
+			break
+		}
+		visited[pageUrl.String()] = true
+
+		page, err := n.fetcher.FetchFeed(ctx, pageUrl.String(), "", nil)
+		if err != nil {
+			log.Warnf(ctx, "Failed to fetch archive page %s: %s", pageUrl, err)
+			break
+		}
+
+		for _, item := range datedItems(page.Feed.Items) {
+			if key := itemKey(item); !seen[key] {
+				seen[key] = true
+				items = append(items, item)
+			}
+		}
+
+		pageUrl = archiveLink(ctx, rssFeed.FeedUrl, page.Links)
+	}
+
+	return items
+}
+
+// archiveLink returns the url of the page of older items linked
+// from a feed document, relative to feedUrl, or nil if there's none.
+func archiveLink(ctx context.Context, feedUrl *netUrl.URL, links map[string]string) *netUrl.URL {
+	next := links["prev-archive"]
+	if next == "" {
+		next = links["next"]
+	}
+	if next == "" {
+		return nil
+	}
+
+	pageUrl, err := feedUrl.Parse(next)
+	if err != nil {
+		log.Warnf(ctx, "Invalid archive link %s: %s", next, err)
+		return nil
+	}
+	return pageUrl
+}
+
+// parseFeedLinks extracts the rel / href pairs of the link elements
+// of an Atom feed or of the atom:link elements of an RSS channel.
+func parseFeedLinks(data []byte) map[string]string {
+	var doc feedLinks
+	links := make(map[string]string)
+
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return links
+	}
+
+	for _, link := range append(doc.Links, doc.ChannelLinks...) {
+		if link.Rel != "" && link.Href != "" {
+			if _, ok := links[link.Rel]; !ok {
+				links[link.Rel] = link.Href
+			}
+		}
+	}
+
+	return links
+}
+
+// datedItems returns the items having a publication date, using
+// the update date for items which only have this one.
+func datedItems(items []*gofeed.Item) []*gofeed.Item {
+	dated := make([]*gofeed.Item, 0, len(items))
+	for _, item := range items {
+		if item.PublishedParsed == nil {
+			item.PublishedParsed = item.UpdatedParsed
+		}
+		if item.PublishedParsed != nil {
+			dated = append(dated, item)
+		}
+	}
+	return dated
+}
+
+// itemKey returns a value identifying an item across feed documents.
+func itemKey(item *gofeed.Item) string {
+	if item.GUID != "" {
+		return item.GUID
+	}
+	if item.Link != "" {
+		return item.Link
+	}
+	return item.Title + item.PublishedParsed.Format(time.RFC3339)
+}
@@ -0,0 +1,315 @@
+package rss
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	netUrl "net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
+	"golang.org/x/net/html"
+)
+
+// cardFrequency is how often the statuses of local accounts get their preview card.
+const cardFrequency = time.Minute
+
+// cardDelay is the age of the statuses of local accounts looked
+// at, so that statuses still being inserted aren't skipped.
+const cardDelay = 10 * time.Second
+
+// cardBatchSize is the maximum number of statuses of local accounts
+// getting their preview card per run.
+const cardBatchSize = 100
+
+// cardsCursor is the name of the cursor of attachLocalCards.
+const cardsCursor = "cards"
+
+// maxCardDescription is the maximum length, in characters, of a card description.
+const maxCardDescription = 500
+
+// previewCard returns the preview card of page, reading its metadata
+// and caching its image if it isn't known yet. It returns nil if the
+// page has nothing to preview.
+func (n *rssTooter) previewCard(ctx context.Context, page *itemPage) (_ *gtsmodel.PreviewCard, err error) {
+	link := page.link
+	ctx, span := startSpan(ctx, "rss.PreviewCard", attrItemLink.String(link))
+	defer func() { endSpan(span, err) }()
+
+	card, err := n.state.DB.GetPreviewCardByURL(ctx, link)
+	if err == nil {
+		return card, nil
+	} else if !errors.Is(err, db.ErrNoEntries) {
+		return nil, gtserror.Newf("couldn't get preview card: %w", err)
+	}
+
+	pageUrl, err := netUrl.Parse(link)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := page.load(ctx, n.fetcher)
+	if err != nil {
+		return nil, err
+	}
+
+	card, imageUrl := parseCard(pageUrl, doc)
+	if card == nil {
+		return nil, nil
+	}
+
+	if imageUrl != "" {
+		if err := n.loadCardImage(ctx, card, imageUrl); err != nil {
+			log.Warnf(ctx, "Failed to load card image %s: %s", imageUrl, err)
+		}
+	}
+
+	card.ID = id.NewULID()
+	if err := n.state.DB.PutPreviewCard(ctx, card); err != nil {
+		if errors.Is(err, db.ErrAlreadyExists) {
+			// Read concurrently by another status.
+			return n.state.DB.GetPreviewCardByURL(ctx, link)
+		}
+		return nil, gtserror.Newf("couldn't put preview card: %w", err)
+	}
+
+	return card, nil
+}
+
+// parseCard returns the preview card of the page at pageUrl read from
+// its OpenGraph and Twitter card metadata, and the url of its image.
+// It returns nil if the page has no title.
+func parseCard(pageUrl *netUrl.URL, doc *html.Node) (*gtsmodel.PreviewCard, string) {
+	card := &gtsmodel.PreviewCard{
+		URL:          pageUrl.String(),
+		Type:         gtsmodel.PreviewCardTypeLink,
+		Title:        cardMeta(doc, "og:title", "twitter:title"),
+		Description:  cardMeta(doc, "og:description", "twitter:description", "description"),
+		AuthorName:   cardMeta(doc, "author", "twitter:creator"),
+		ProviderName: cardMeta(doc, "og:site_name"),
+		ProviderURL:  pageUrl.Scheme + "://" + pageUrl.Host,
+	}
+
+	if card.Title == "" {
+		if node := htmlquery.FindOne(doc, "//head/title"); node != nil {
+			card.Title = strings.TrimSpace(htmlquery.InnerText(node))
+		}
+	}
+	if card.Title == "" {
+		return nil, ""
+	}
+
+	if description := []rune(card.Description); len(description) > maxCardDescription {
+		card.Description = string(description[:maxCardDescription-1]) + "…"
+	}
+
+	// article:author is either the name or the profile of the author.
+	if author := cardMeta(doc, "article:author"); author != "" {
+		if authorUrl := resolveCardURL(pageUrl, author); authorUrl != "" && strings.HasPrefix(author, "http") {
+			card.AuthorURL = authorUrl
+		} else if card.AuthorName == "" {
+			card.AuthorName = author
+		}
+	}
+
+	imageUrl := resolveCardURL(pageUrl, cardMeta(doc, "og:image:secure_url", "og:image", "og:image:url", "twitter:image", "twitter:image:src"))
+	if imageUrl != "" {
+		card.Width = cardMetaInt(doc, "og:image:width")
+		card.Height = cardMetaInt(doc, "og:image:height")
+	}
+
+	if cardMeta(doc, "twitter:card") == "photo" && imageUrl != "" {
+		card.Type = gtsmodel.PreviewCardTypePhoto
+		card.EmbedURL = imageUrl
+	}
+
+	// Players are embedded only over https,
+	// as clients show them in an iframe.
+	player := resolveCardURL(pageUrl, cardMeta(doc, "twitter:player", "og:video:secure_url", "og:video:url", "og:video"))
+	if strings.HasPrefix(player, "https://") {
+		card.Type = gtsmodel.PreviewCardTypeVideo
+		card.EmbedURL = player
+		card.Width = cardMetaInt(doc, "twitter:player:width", "og:video:width")
+		card.Height = cardMetaInt(doc, "twitter:player:height", "og:video:height")
+		card.HTML = fmt.Sprintf(`<iframe src="%s" width="%d" height="%d" frameborder="0" allowfullscreen></iframe>`,
+			html.EscapeString(player), card.Width, card.Height)
+	}
+
+	return card, imageUrl
+}
+
+// cardMeta returns the content of the first meta tag of doc
+// with one of the given property or name, in order.
+func cardMeta(doc *html.Node, keys ...string) string {
+	for _, key := range keys {
+		node := htmlquery.FindOne(doc, "//meta[@property='"+key+"' or @name='"+key+"']")
+		if node == nil {
+			continue
+		}
+		if content := strings.TrimSpace(htmlquery.SelectAttr(node, "content")); content != "" {
+			return content
+		}
+	}
+	return ""
+}
+
+// cardMetaInt returns the number of the first meta tag
+// of doc with one of the given property or name, or 0.
+func cardMetaInt(doc *html.Node, keys ...string) int {
+	value, err := strconv.Atoi(cardMeta(doc, keys...))
+	if err != nil || value < 0 {
+		return 0
+	}
+	return value
+}
+
+// resolveCardURL returns href resolved against the page
+// url, empty unless it's an http or https url.
+func resolveCardURL(pageUrl *netUrl.URL, href string) string {
+	if href == "" {
+		return ""
+	}
+	resolved, err := pageUrl.Parse(href)
+	if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+		return ""
+	}
+	return resolved.String()
+}
+
+// loadCardImage stores the image of card through the media
+// pipeline, owned by the instance account as cards are shared
+// by every status linking to the same page.
+func (n *rssTooter) loadCardImage(ctx context.Context, card *gtsmodel.PreviewCard, imageUrl string) error {
+	instanceAccount, err := n.state.DB.GetInstanceAccount(ctx, "")
+	if err != nil {
+		return gtserror.Newf("couldn't get instance account: %w", err)
+	}
+
+	imageData, err := n.fetcher.FetchData(ctx, imageUrl, int64(config.GetMediaImageMaxSize()))
+	if err != nil {
+		return err
+	}
+
+	data := func(context.Context) (io.ReadCloser, int64, error) {
+		return io.NopCloser(bytes.NewReader(imageData)), int64(len(imageData)), nil
+	}
+
+	processing := n.mediaManager.PreProcessMedia(data, instanceAccount.ID, &media.AdditionalMediaInfo{
+		RemoteURL:   &imageUrl,
+		Description: &card.Title,
+	})
+
+	attachment, err := processing.LoadAttachment(ctx)
+	if err != nil {
+		return err
+	} else if attachment.Type != gtsmodel.FileTypeImage {
+		return gtserror.Newf("could not process image of type %s", attachment.File.ContentType)
+	}
+
+	card.ImageID = attachment.ID
+	card.Image = attachment
+	return nil
+}
+
+// attachLocalCards gives a preview card of their first link to the public
+// and unlisted statuses of local accounts created since the previous run.
+// Statuses whose card can't be read are not retried.
+func (n *rssTooter) attachLocalCards(ctx context.Context, now time.Time) {
+	until, err := id.NewULIDFromTime(now.Add(-cardDelay))
+	if err != nil {
+		log.Errorf(ctx, "Failed to generate card cursor: %s", err)
+		return
+	}
+
+	// Statuses created before the first run are left without a card.
+	since, err := n.GetRssCursor(ctx, cardsCursor)
+	if errors.Is(err, db.ErrNoEntries) {
+		since, err = until, n.PutRssCursor(ctx, cardsCursor, until)
+	}
+	if err != nil {
+		log.Errorf(ctx, "Failed to retrieve card cursor: %s", err)
+		return
+	}
+
+	statuses, err := n.GetLocalStatusesWithoutCard(ctx, since, until, cardBatchSize)
+	if err != nil {
+		log.Errorf(ctx, "Failed to retrieve local statuses: %s", err)
+		return
+	}
+
+	for _, status := range statuses {
+
+		link := statusLink(status)
+		if link == "" {
+			continue
+		}
+
+		card, err := n.previewCard(ctx, &itemPage{link: link})
+		if err != nil {
+			log.Debugf(ctx, "Failed to read preview card of %s: %s", link, err)
+			continue
+		} else if card == nil {
+			continue
+		}
+
+		status.PreviewCardID = card.ID
+		status.PreviewCard = card
+		if err := n.state.DB.UpdateStatus(ctx, status, "preview_card_id"); err != nil {
+			log.Errorf(ctx, "Failed to update status %s: %s", status.ID, err)
+			continue
+		}
+
+		// Timelines hold the prepared status, without its card.
+		if err := n.state.Timelines.Home.UnprepareItemFromAllTimelines(ctx, status.ID); err != nil {
+			log.Errorf(ctx, "Failed to unprepare status %s from home timelines: %s", status.ID, err)
+		}
+		if err := n.state.Timelines.List.UnprepareItemFromAllTimelines(ctx, status.ID); err != nil {
+			log.Errorf(ctx, "Failed to unprepare status %s from list timelines: %s", status.ID, err)
+		}
+	}
+
+	// Statuses failing to get a card aren't tried again.
+	if len(statuses) > 0 {
+		if err := n.PutRssCursor(ctx, cardsCursor, statuses[len(statuses)-1].ID); err != nil {
+			log.Errorf(ctx, "Failed to store card cursor: %s", err)
+		}
+	}
+}
+
+// statusLink returns the first link of the content of status to another
+// website, leaving out mentions and hashtags, or empty if there is none.
+func statusLink(status *gtsmodel.Status) string {
+	doc, err := htmlquery.Parse(strings.NewReader(status.Content))
+	if err != nil {
+		return ""
+	}
+
+	for _, node := range htmlquery.Find(doc, "//a[@href]") {
+		class := " " + htmlquery.SelectAttr(node, "class") + " "
+		rel := " " + htmlquery.SelectAttr(node, "rel") + " "
+		if strings.Contains(class, " mention ") || strings.Contains(class, " hashtag ") || strings.Contains(rel, " tag ") {
+			continue
+		}
+
+		link, err := netUrl.Parse(htmlquery.SelectAttr(node, "href"))
+		if err != nil || (link.Scheme != "http" && link.Scheme != "https") ||
+			link.Host == config.GetHost() || link.Host == config.GetAccountDomain() {
+			continue
+		}
+
+		return link.String()
+	}
+
+	return ""
+}
@@ -0,0 +1,183 @@
+package rss
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	statusfilter "github.com/superseriousbusiness/gotosocial/internal/filter/status"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+type CardTestSuite struct {
+	RssStandardTestSuite
+}
+
+func (suite *CardTestSuite) TestPreviewCard() {
+	ctx := context.Background()
+
+	card, err := suite.tooter.previewCard(ctx, &itemPage{link: suite.fixtureURL("post.html")})
+	suite.NoError(err)
+	suite.NotNil(card)
+
+	// OpenGraph metadata wins over the page title and description.
+	suite.Equal(suite.fixtureURL("post.html"), card.URL)
+	suite.Equal(gtsmodel.PreviewCardTypeLink, card.Type)
+	suite.Equal("My first century ride", card.Title)
+	suite.Equal("A hundred miles on a sunny day", card.Description)
+	suite.Equal("Carol", card.AuthorName)
+	suite.Equal("A Group Blog", card.ProviderName)
+	suite.Equal(strings.TrimSuffix(suite.fixtureURL(""), "/"), card.ProviderURL)
+	suite.Equal(300, card.Width)
+	suite.Equal(100, card.Height)
+
+	// The image is cached through the media pipeline.
+	suite.NotNil(card.Image)
+	suite.Equal(suite.fixtureURL("banner.png"), card.Image.RemoteURL)
+	suite.Equal(gtsmodel.FileTypeImage, card.Image.Type)
+
+	// Cards are read once per page.
+	again, err := suite.tooter.previewCard(ctx, &itemPage{link: suite.fixtureURL("post.html")})
+	suite.NoError(err)
+	suite.Equal(card.ID, again.ID)
+	suite.Equal(card.ImageID, again.Image.ID)
+
+	used, err := suite.state.DB.IsPreviewCardImage(ctx, card.ImageID)
+	suite.NoError(err)
+	suite.True(used)
+}
+
+func (suite *CardTestSuite) TestParseCardVideo() {
+	doc, err := htmlquery.Parse(strings.NewReader(`<html><head>
+		<meta name="twitter:card" content="player">
+		<meta name="twitter:title" content="A ride &quot;filmed&quot;">
+		<meta name="twitter:player" content="https://video.example.org/embed/1">
+		<meta name="twitter:player:width" content="640">
+		<meta name="twitter:player:height" content="360">
+		<meta name="twitter:image" content="thumb.jpg">
+	</head></html>`))
+	suite.NoError(err)
+
+	pageUrl, _ := url.Parse("https://example.org/videos/1")
+	card, imageUrl := parseCard(pageUrl, doc)
+	suite.NotNil(card)
+	suite.Equal(gtsmodel.PreviewCardTypeVideo, card.Type)
+	suite.Equal(`A ride "filmed"`, card.Title)
+	suite.Equal("https://video.example.org/embed/1", card.EmbedURL)
+	suite.Equal(`<iframe src="https://video.example.org/embed/1" width="640" height="360" frameborder="0" allowfullscreen></iframe>`, card.HTML)
+	suite.Equal("https://example.org/videos/thumb.jpg", imageUrl)
+}
+
+func (suite *CardTestSuite) TestParseCardNoTitle() {
+	doc, err := htmlquery.Parse(strings.NewReader(`<html><body><p>Nothing</p></body></html>`))
+	suite.NoError(err)
+
+	pageUrl, _ := url.Parse("https://example.org/")
+	card, _ := parseCard(pageUrl, doc)
+	suite.Nil(card)
+}
+
+func (suite *CardTestSuite) TestStatusLink() {
+	status := &gtsmodel.Status{Content: `<p>Hi <span class="h-card"><a href="https://example.org/@bob" class="u-url mention">@<span>bob</span></a></span> ` +
+		`<a href="https://example.org/tags/bikes" class="mention hashtag" rel="tag">#<span>bikes</span></a> ` +
+		`<a href="http://localhost:8080/@admin/statuses/1">mine</a> ` +
+		`<a href="https://blog.example.org/century" rel="nofollow noreferrer noopener">read this</a></p>`}
+
+	suite.Equal("https://blog.example.org/century", statusLink(status))
+	suite.Empty(statusLink(&gtsmodel.Status{Content: "<p>No link</p>"}))
+}
+
+func (suite *CardTestSuite) TestCreateStatusCard() {
+	config.SetRssPreviewCards(true)
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_1"]
+
+	httpFeed, err := suite.tooter.fetcher.FetchFeed(ctx, suite.fixtureURL("authors.xml"), "", nil)
+	suite.NoError(err)
+
+	var status *gtsmodel.Status
+	for _, item := range httpFeed.Feed.Items {
+		if item.GUID == "century" {
+			status, err = suite.tooter.createStatus(ctx, &ToCreate{Account: account, Item: item})
+			suite.NoError(err)
+		}
+	}
+	suite.NotNil(status)
+	suite.NotEmpty(status.PreviewCardID)
+
+	// The card is populated with the stored status, and shown by the API.
+	dbStatus, err := suite.state.DB.GetStatusByID(ctx, status.ID)
+	suite.NoError(err)
+	suite.NotNil(dbStatus.PreviewCard)
+
+	apiStatus, err := suite.tooter.converter.StatusToAPIStatus(ctx, dbStatus, account, statusfilter.FilterContextNone, nil, nil)
+	suite.NoError(err)
+	suite.NotNil(apiStatus.Card)
+	suite.Equal("My first century ride", apiStatus.Card.Title)
+	suite.Equal("link", apiStatus.Card.Type)
+	suite.Equal(dbStatus.PreviewCard.Image.URL, apiStatus.Card.Image)
+	suite.NotEmpty(apiStatus.Card.Blurhash)
+}
+
+func (suite *CardTestSuite) TestAttachLocalCards() {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_2"]
+
+	statusID, err := id.NewULIDFromTime(time.Now().Add(-time.Minute))
+	suite.NoError(err)
+	status := &gtsmodel.Status{
+		ID:                  statusID,
+		URI:                 account.URI + "/statuses/" + statusID,
+		Content:             `<p>Look: <a href="` + suite.fixtureURL("post.html") + `">a century</a></p>`,
+		Local:               util.Ptr(true),
+		AccountID:           account.ID,
+		AccountURI:          account.URI,
+		Visibility:          gtsmodel.VisibilityPublic,
+		ActivityStreamsType: ap.ObjectNote,
+		Federated:           util.Ptr(true),
+		Boostable:           util.Ptr(true),
+		Replyable:           util.Ptr(true),
+		Likeable:            util.Ptr(true),
+	}
+	suite.NoError(suite.state.DB.PutStatus(ctx, status))
+
+	// The first run only starts the cursor.
+	suite.tooter.attachLocalCards(ctx, time.Now().Add(-2*time.Minute))
+	dbStatus, err := suite.state.DB.GetStatusByID(ctx, statusID)
+	suite.NoError(err)
+	suite.Nil(dbStatus.PreviewCard)
+
+	// Statuses created since the previous run get the card of their link,
+	// the private ones excepted, and the cursor is stored.
+	status.ID, err = id.NewULIDFromTime(time.Now().Add(-time.Minute))
+	suite.NoError(err)
+	status.URI = account.URI + "/statuses/" + status.ID
+	status.Visibility = gtsmodel.VisibilityFollowersOnly
+	suite.NoError(suite.state.DB.PutStatus(ctx, status))
+
+	suite.tooter.attachLocalCards(ctx, time.Now())
+	cursor, err := suite.tooter.GetRssCursor(ctx, cardsCursor)
+	suite.NoError(err)
+	suite.Equal(statusID, cursor)
+
+	dbStatus, err = suite.state.DB.GetStatusByID(ctx, statusID)
+	suite.NoError(err)
+	suite.NotNil(dbStatus.PreviewCard)
+	suite.Equal(suite.fixtureURL("post.html"), dbStatus.PreviewCard.URL)
+
+	dbStatus, err = suite.state.DB.GetStatusByID(ctx, status.ID)
+	suite.NoError(err)
+	suite.Nil(dbStatus.PreviewCard)
+}
+
+func TestCardTestSuite(t *testing.T) {
+	suite.Run(t, new(CardTestSuite))
+}
@@ -0,0 +1,306 @@
+package rss
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	netUrl "net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/mmcdole/gofeed"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// claimTokenPrefix starts the tokens of feed claims, so
+// that they aren't found in a feed or website by chance.
+const claimTokenPrefix = "rsstooter-claim-"
+
+// claimMetaName is the name of the meta tag of a feed website holding a claim
+// token, and the rel of the feed link or name of the feed meta element holding it.
+const claimMetaName = "rsstooter-claim"
+
+// claimTTL is how long a claim stays pending before it expires.
+const claimTTL = 7 * 24 * time.Hour
+
+// maxPendingClaims is the number of claims a user may have pending at once.
+const maxPendingClaims = 5
+
+// websiteField is the name of the profile field linking the verified website of a feed.
+const websiteField = "Website"
+
+// ClaimFeed requests the ownership of a feed account by requester, returning
+// the token to publish on the feed or its website. Claiming again returns the
+// same token, or a new one once the claim has expired.
+func (n *rssTooter) ClaimFeed(ctx context.Context, requester *gtsmodel.User, accountID string) (*apimodel.RssFeedClaim, gtserror.WithCode) {
+	if _, err := n.GetRssFeedByAccountID(ctx, accountID); err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err := fmt.Errorf("account %s is not a feed account", accountID)
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		err := gtserror.Newf("db error getting feed: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	claim, err := n.GetRssFeedClaim(ctx, accountID, requester.AccountID)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err := gtserror.Newf("db error getting feed claim: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	} else if err == nil && !claimExpired(claim) {
+		return apiFeedClaim(claim), nil
+	} else if err == nil {
+		if err := n.DeleteRssFeedClaim(ctx, claim.ID); err != nil {
+			err := gtserror.Newf("db error deleting expired feed claim: %w", err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+	}
+
+	pending, err := n.CountPendingRssFeedClaims(ctx, requester.AccountID, time.Now().Add(-claimTTL))
+	if err != nil {
+		err := gtserror.Newf("db error counting feed claims: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	if pending >= maxPendingClaims {
+		err := fmt.Errorf("user %s has %d pending claims already", requester.ID, pending)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		err := gtserror.Newf("error generating claim token: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	claim = &gtsmodel.RssFeedClaim{
+		ID:                id.NewULID(),
+		CreatedAt:         time.Now(),
+		AccountID:         accountID,
+		ClaimantAccountID: requester.AccountID,
+		Token:             claimTokenPrefix + feedTokenEncoding.EncodeToString(secret),
+	}
+
+	if err := n.PutRssFeedClaim(ctx, claim); err != nil {
+		err := gtserror.Newf("db error putting feed claim: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return apiFeedClaim(claim), nil
+}
+
+// GetFeedClaim returns the claim of requester on a feed account,
+// not found once it has expired without being verified.
+func (n *rssTooter) GetFeedClaim(ctx context.Context, requester *gtsmodel.User, accountID string) (*apimodel.RssFeedClaim, gtserror.WithCode) {
+	claim, err := n.GetRssFeedClaim(ctx, accountID, requester.AccountID)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err := gtserror.Newf("db error getting feed claim: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	if err != nil || claimExpired(claim) {
+		err := fmt.Errorf("user %s has no pending nor verified claim on account %s", requester.ID, accountID)
+		return nil, gtserror.NewErrorNotFound(err, err.Error())
+	}
+
+	return apiFeedClaim(claim), nil
+}
+
+// claimExpired returns whether claim is pending since longer than claimTTL.
+func claimExpired(claim *gtsmodel.RssFeedClaim) bool {
+	return claim.VerifiedAt.IsZero() && time.Since(claim.CreatedAt) > claimTTL
+}
+
+// verifyClaims looks for the tokens of the pending claims on account in
+// its fetched feed document, if it was modified, and on the feed website,
+// and verifies the claims whose token is found.
+func (n *rssTooter) verifyClaims(ctx context.Context, account *gtsmodel.Account, feedUrl string, feed *HTTPFeed, claims []*gtsmodel.RssFeedClaim) {
+	siteFeed := feed.Feed
+	if siteFeed == nil {
+		// Not modified: the website is guessed from the feed url.
+		siteFeed = &gofeed.Feed{Link: feedUrl}
+	}
+
+	var site *netUrl.URL
+	var doc *html.Node
+	if parsed, err := netUrl.Parse(feedUrl); err == nil {
+		if site, doc, err = loadSite(ctx, n.fetcher, parsed, siteFeed); err != nil {
+			log.Debugf(ctx, "Failed to load website of %s: %s", feedUrl, err)
+		}
+	}
+
+	for _, claim := range claims {
+		if !feedClaimed(feed.Data, claim.Token) && !siteClaimed(doc, claim.Token) {
+			continue
+		}
+
+		if err := n.verifyClaim(ctx, account, claim, site); err != nil {
+			log.Errorf(ctx, "Failed to verify claim %s: %s", claim.ID, err)
+		}
+	}
+}
+
+// feedClaimed returns whether token is published by the feed document data,
+// as the href of a <link rel="rsstooter-claim">, part of the href of a <link
+// rel="me"> or the content of a <meta name="rsstooter-claim"> element of its
+// channel (RSS) or feed (Atom). The items and entries of the feed, written
+// by anyone, are never looked into.
+func feedClaimed(data []byte, token string) bool {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	var path []string
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+
+		switch elem := tok.(type) {
+		case xml.StartElement:
+			if feedElement(path) && claimElement(elem, token) {
+				return true
+			}
+			path = append(path, strings.ToLower(elem.Name.Local))
+		case xml.EndElement:
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+		}
+	}
+}
+
+// feedElement returns whether path, the local names of the
+// elements from the root, is the one of an RSS 2.0 or RSS 1.0
+// channel element or of an Atom feed element.
+func feedElement(path []string) bool {
+	switch len(path) {
+	case 1:
+		return path[0] == "feed"
+	case 2:
+		return (path[0] == "rss" || path[0] == "rdf") && path[1] == "channel"
+	default:
+		return false
+	}
+}
+
+// claimElement returns whether elem is a claim link or meta element holding token.
+func claimElement(elem xml.StartElement, token string) bool {
+	attrs := make(map[string]string, len(elem.Attr))
+	for _, attr := range elem.Attr {
+		attrs[strings.ToLower(attr.Name.Local)] = strings.TrimSpace(attr.Value)
+	}
+
+	switch strings.ToLower(elem.Name.Local) {
+	case "link":
+		return (attrs["rel"] == claimMetaName && attrs["href"] == token) || relMe(attrs["rel"], attrs["href"], token)
+	case "meta":
+		return attrs["name"] == claimMetaName && attrs["content"] == token
+	default:
+		return false
+	}
+}
+
+// siteClaimed returns whether token is the content of the claim meta
+// tag of the website doc, or part of one of its rel="me" head links.
+func siteClaimed(doc *html.Node, token string) bool {
+	if doc == nil {
+		return false
+	}
+
+	for _, node := range htmlquery.Find(doc, "//head/meta[@name='"+claimMetaName+"']") {
+		if strings.TrimSpace(htmlquery.SelectAttr(node, "content")) == token {
+			return true
+		}
+	}
+
+	for _, node := range htmlquery.Find(doc, "//head/link[@rel and @href]") {
+		if relMe(htmlquery.SelectAttr(node, "rel"), htmlquery.SelectAttr(node, "href"), token) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// relMe returns whether rel holds the "me" link type and href contains token.
+func relMe(rel string, href string, token string) bool {
+	return slices.Contains(strings.Fields(strings.ToLower(rel)), "me") && strings.Contains(href, token)
+}
+
+// verifyClaim records claim as verified, makes the claimant a delegate
+// of account, and shows the website of the feed as a verified link on
+// the profile of account, federating the update.
+func (n *rssTooter) verifyClaim(ctx context.Context, account *gtsmodel.Account, claim *gtsmodel.RssFeedClaim, site *netUrl.URL) error {
+	claim.VerifiedAt = time.Now()
+	if err := n.UpdateRssFeedClaim(ctx, claim, "verified_at"); err != nil {
+		return gtserror.Newf("couldn't update claim: %w", err)
+	}
+
+	if err := n.PutRssFeedDelegate(ctx, &gtsmodel.RssFeedDelegate{
+		ID:                id.NewULID(),
+		AccountID:         account.ID,
+		DelegateAccountID: claim.ClaimantAccountID,
+	}); err != nil && !errors.Is(err, db.ErrAlreadyExists) {
+		return gtserror.Newf("couldn't put claimant delegate: %w", err)
+	}
+
+	if site == nil {
+		return nil
+	}
+
+	fields := make([]*gtsmodel.Field, 0, len(account.Fields)+1)
+	for _, field := range account.Fields {
+		if field.Name != websiteField {
+			fields = append(fields, field)
+		}
+	}
+	account.Fields = append(fields, &gtsmodel.Field{
+		Name:       websiteField,
+		Value:      `<a href="` + html.EscapeString(site.String()) + `" rel="nofollow noreferrer noopener me" target="_blank">` + html.EscapeString(site.String()) + `</a>`,
+		VerifiedAt: claim.VerifiedAt,
+	})
+
+	if err := n.state.DB.UpdateAccount(ctx, account, "fields"); err != nil {
+		return gtserror.Newf("couldn't update feed account: %w", err)
+	}
+
+	// send it to the client API worker, which federates the update.
+	n.state.Workers.Client.Queue.Push(&messages.FromClientAPI{
+		APObjectType:   ap.ActorService,
+		APActivityType: ap.ActivityUpdate,
+		GTSModel:       account,
+		Origin:         account,
+	})
+
+	return nil
+}
+
+func apiFeedClaim(claim *gtsmodel.RssFeedClaim) *apimodel.RssFeedClaim {
+	var verifiedAt, expiresAt *string
+	if !claim.VerifiedAt.IsZero() {
+		verifiedAt = util.Ptr(util.FormatISO8601(claim.VerifiedAt))
+	} else {
+		expiresAt = util.Ptr(util.FormatISO8601(claim.CreatedAt.Add(claimTTL)))
+	}
+
+	return &apimodel.RssFeedClaim{
+		AccountID:  claim.AccountID,
+		Token:      claim.Token,
+		VerifiedAt: verifiedAt,
+		ExpiresAt:  expiresAt,
+	}
+}
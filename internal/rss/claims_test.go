@@ -0,0 +1,256 @@
+package rss
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"golang.org/x/net/html"
+)
+
+type ClaimsTestSuite struct {
+	RssStandardTestSuite
+}
+
+// feedAccount makes local_account_2 the account of a feed.
+func (suite *ClaimsTestSuite) feedAccount() *gtsmodel.Account {
+	account := suite.testAccounts["local_account_2"]
+	if err := suite.tooter.PutRssFeed(context.Background(), &gtsmodel.RssFeed{
+		ID:        id.NewULID(),
+		AccountID: account.ID,
+		URL:       suite.fixtureURL("rss2.xml"),
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+	return account
+}
+
+func (suite *ClaimsTestSuite) TestClaimFeed() {
+	ctx := context.Background()
+	feedAccount := suite.feedAccount()
+	user := suite.testUsers["local_account_1"]
+
+	_, errWithCode := suite.tooter.GetFeedClaim(ctx, user, feedAccount.ID)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+
+	claim, errWithCode := suite.tooter.ClaimFeed(ctx, user, feedAccount.ID)
+	suite.Nil(errWithCode)
+	suite.Equal(feedAccount.ID, claim.AccountID)
+	suite.True(strings.HasPrefix(claim.Token, claimTokenPrefix))
+	suite.Nil(claim.VerifiedAt)
+
+	// Claiming again returns the same token.
+	again, errWithCode := suite.tooter.ClaimFeed(ctx, user, feedAccount.ID)
+	suite.Nil(errWithCode)
+	suite.Equal(claim.Token, again.Token)
+
+	got, errWithCode := suite.tooter.GetFeedClaim(ctx, user, feedAccount.ID)
+	suite.Nil(errWithCode)
+	suite.Equal(claim.Token, got.Token)
+
+	// Only feed accounts can be claimed.
+	_, errWithCode = suite.tooter.ClaimFeed(ctx, user, suite.testAccounts["local_account_1"].ID)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+}
+
+func (suite *ClaimsTestSuite) TestVerifyClaims() {
+	ctx := context.Background()
+	feedAccount := suite.feedAccount()
+	user := suite.testUsers["local_account_1"]
+	admin := suite.testUsers["admin_account"]
+
+	if _, errWithCode := suite.tooter.ClaimFeed(ctx, user, feedAccount.ID); errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+	if _, errWithCode := suite.tooter.ClaimFeed(ctx, admin, feedAccount.ID); errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+
+	// Publish the token of the claim of user on the website.
+	claim, err := suite.tooter.GetRssFeedClaim(ctx, feedAccount.ID, user.AccountID)
+	suite.NoError(err)
+	claim.Token = claimTokenPrefix + "testtoken"
+	suite.NoError(suite.tooter.UpdateRssFeedClaim(ctx, claim, "token"))
+
+	pending, err := suite.tooter.GetPendingRssFeedClaims(ctx)
+	suite.NoError(err)
+	suite.Len(pending, 2)
+
+	feed, err := suite.tooter.fetcher.FetchFeed(ctx, suite.fixtureURL("rss2.xml"), "", nil)
+	suite.NoError(err)
+	suite.tooter.verifyClaims(ctx, feedAccount, suite.fixtureURL("rss2.xml"), feed, pending)
+
+	verified, errWithCode := suite.tooter.GetFeedClaim(ctx, user, feedAccount.ID)
+	suite.Nil(errWithCode)
+	suite.NotNil(verified.VerifiedAt)
+
+	delegate, err := suite.state.DB.IsRssFeedDelegate(ctx, feedAccount.ID, user.AccountID)
+	suite.NoError(err)
+	suite.True(delegate)
+
+	// The other token is nowhere to be found.
+	other, errWithCode := suite.tooter.GetFeedClaim(ctx, admin, feedAccount.ID)
+	suite.Nil(errWithCode)
+	suite.Nil(other.VerifiedAt)
+
+	pending, err = suite.tooter.GetPendingRssFeedClaims(ctx)
+	suite.NoError(err)
+	suite.Len(pending, 1)
+
+	dbAccount, err := suite.state.DB.GetAccountByID(ctx, feedAccount.ID)
+	suite.NoError(err)
+	field := dbAccount.Fields[len(dbAccount.Fields)-1]
+	suite.Equal(websiteField, field.Name)
+	suite.Contains(field.Value, suite.fixtureURL(""))
+	suite.False(field.VerifiedAt.IsZero())
+
+	// The profile update is federated as the one of a service actor.
+	var update *messages.FromClientAPI
+	for suite.state.Workers.Client.Queue.Len() > 0 {
+		msg, _ := suite.state.Workers.Client.Queue.Pop()
+		if msg.APActivityType == ap.ActivityUpdate {
+			update = msg
+		}
+	}
+	if suite.NotNil(update) {
+		suite.Equal(ap.ActorService, update.APObjectType)
+	}
+}
+
+func (suite *ClaimsTestSuite) TestVerifyClaimsInFeed() {
+	ctx := context.Background()
+	feedAccount := suite.feedAccount()
+	user := suite.testUsers["local_account_1"]
+
+	claim, errWithCode := suite.tooter.ClaimFeed(ctx, user, feedAccount.ID)
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+
+	pending, err := suite.tooter.GetPendingRssFeedClaims(ctx)
+	suite.NoError(err)
+
+	feed, err := suite.tooter.fetcher.FetchFeed(ctx, suite.fixtureURL("rss2.xml"), "", nil)
+	suite.NoError(err)
+	data := feed.Data
+
+	// Tokens in items, or not in a claim element, don't count.
+	feed.Data = bytes.Replace(data, []byte("<item>"), []byte(`<item><link rel="rsstooter-claim" href="`+claim.Token+`"/>`), 1)
+	feed.Data = bytes.Replace(feed.Data, []byte("</channel>"), []byte("<!-- "+claim.Token+" --></channel>"), 1)
+	suite.tooter.verifyClaims(ctx, feedAccount, suite.fixtureURL("rss2.xml"), feed, pending)
+
+	unverified, errWithCode := suite.tooter.GetFeedClaim(ctx, user, feedAccount.ID)
+	suite.Nil(errWithCode)
+	suite.Nil(unverified.VerifiedAt)
+
+	feed.Data = bytes.Replace(data, []byte("<channel>"), []byte(`<channel><atom:link rel="rsstooter-claim" href="`+claim.Token+`"/>`), 1)
+	suite.tooter.verifyClaims(ctx, feedAccount, suite.fixtureURL("rss2.xml"), feed, pending)
+
+	verified, errWithCode := suite.tooter.GetFeedClaim(ctx, user, feedAccount.ID)
+	suite.Nil(errWithCode)
+	suite.NotNil(verified.VerifiedAt)
+	suite.Nil(verified.ExpiresAt)
+}
+
+func (suite *ClaimsTestSuite) TestFeedClaimed() {
+	token := claimTokenPrefix + "testtoken"
+	for data, claimed := range map[string]bool{
+		`<feed xmlns="http://www.w3.org/2005/Atom"><link rel="rsstooter-claim" href="` + token + `"/></feed>`:                                                  true,
+		`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"><channel><meta name="rsstooter-claim" content="` + token + `"/></channel></rdf:RDF>`: true,
+		`<feed xmlns="http://www.w3.org/2005/Atom"><link rel="me" href="https://example.org/` + token + `"/></feed>`:                                           true,
+		`<feed xmlns="http://www.w3.org/2005/Atom"><entry><link rel="me" href="https://example.org/` + token + `"/></entry></feed>`:                            false,
+		`<feed xmlns="http://www.w3.org/2005/Atom"><entry><link rel="rsstooter-claim" href="` + token + `"/></entry></feed>`:                                   false,
+		`<rss><channel><meta name="rsstooter-claim" content="` + token + `-not"/></channel></rss>`:                                                             false,
+		`{"version": "https://jsonfeed.org/version/1.1", "title": "` + token + `"}`:                                                                            false,
+	} {
+		suite.Equal(claimed, feedClaimed([]byte(data), token), data)
+	}
+}
+
+func (suite *ClaimsTestSuite) TestSiteClaimed() {
+	token := claimTokenPrefix + "testtoken"
+	for page, claimed := range map[string]bool{
+		`<html><head><meta name="rsstooter-claim" content="` + token + `"></head></html>`:                  true,
+		`<html><head><link rel="me author" href="https://example.org/@feed?` + token + `"></head></html>`:  true,
+		`<html><head><link rel="author" href="https://example.org/@feed?` + token + `"></head></html>`:     false,
+		`<html><body><a rel="me" href="https://example.org/@feed?` + token + `">comment</a></body></html>`: false,
+		`<html><body><meta name="rsstooter-claim" content="` + token + `"></body></html>`:                  false,
+	} {
+		doc, err := html.Parse(strings.NewReader(page))
+		suite.NoError(err)
+		suite.Equal(claimed, siteClaimed(doc, token), page)
+	}
+}
+
+func (suite *ClaimsTestSuite) TestClaimExpiry() {
+	ctx := context.Background()
+	feedAccount := suite.feedAccount()
+	user := suite.testUsers["local_account_1"]
+
+	first, errWithCode := suite.tooter.ClaimFeed(ctx, user, feedAccount.ID)
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+	suite.NotNil(first.ExpiresAt)
+
+	claim, err := suite.tooter.GetRssFeedClaim(ctx, feedAccount.ID, user.AccountID)
+	suite.NoError(err)
+	claim.CreatedAt = time.Now().Add(-claimTTL - time.Hour)
+	suite.NoError(suite.tooter.UpdateRssFeedClaim(ctx, claim, "created_at"))
+
+	// Expired claims are gone, claiming again gives a new token.
+	_, errWithCode = suite.tooter.GetFeedClaim(ctx, user, feedAccount.ID)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+
+	second, errWithCode := suite.tooter.ClaimFeed(ctx, user, feedAccount.ID)
+	suite.Nil(errWithCode)
+	suite.NotEqual(first.Token, second.Token)
+
+	// Polling deletes the expired claims.
+	claim, err = suite.tooter.GetRssFeedClaim(ctx, feedAccount.ID, user.AccountID)
+	suite.NoError(err)
+	claim.CreatedAt = time.Now().Add(-claimTTL - time.Hour)
+	suite.NoError(suite.tooter.UpdateRssFeedClaim(ctx, claim, "created_at"))
+
+	suite.tooter.poll(ctx)
+	pending, err := suite.tooter.GetPendingRssFeedClaims(ctx)
+	suite.NoError(err)
+	suite.Empty(pending)
+}
+
+func (suite *ClaimsTestSuite) TestClaimLimit() {
+	ctx := context.Background()
+	feedAccount := suite.feedAccount()
+	user := suite.testUsers["local_account_1"]
+
+	for i := 0; i < maxPendingClaims; i++ {
+		if err := suite.tooter.PutRssFeedClaim(ctx, &gtsmodel.RssFeedClaim{
+			ID:                id.NewULID(),
+			CreatedAt:         time.Now(),
+			AccountID:         id.NewULID(),
+			ClaimantAccountID: user.AccountID,
+			Token:             claimTokenPrefix + id.NewULID(),
+		}); err != nil {
+			suite.FailNow(err.Error())
+		}
+	}
+
+	_, errWithCode := suite.tooter.ClaimFeed(ctx, user, feedAccount.ID)
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+
+	// Other users still can.
+	_, errWithCode = suite.tooter.ClaimFeed(ctx, suite.testUsers["admin_account"], feedAccount.ID)
+	suite.Nil(errWithCode)
+}
+
+func TestClaimsTestSuite(t *testing.T) {
+	suite.Run(t, new(ClaimsTestSuite))
+}
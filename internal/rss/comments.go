@@ -0,0 +1,180 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+// commentsWindow is how long after its publication the comment feed of an item is
+// followed: blog comments come in the first days, older items are left alone.
+const commentsWindow = 7 * 24 * time.Hour
+
+// ingestComments fetches the comment feed of a recent, already ingested item,
+// and stores each new comment as a reply status threaded under the item status.
+//
+// Comment feeds are fetched with a conditional request, from the cache headers of
+// their previous fetch. Comments are only stored, as backfilled items: they show up
+// in the conversation of the item, not in home timelines, and aren't federated.
+func (n *rssTooter) ingestComments(ctx context.Context, account *gtsmodel.Account, item *gofeed.Item) {
+	commentsUrl := commentFeed(item)
+	if !config.GetRssFetchComments() || commentsUrl == "" || item.Link == "" {
+		return
+	}
+	if item.PublishedParsed == nil || time.Since(*item.PublishedParsed) > commentsWindow {
+		return
+	}
+
+	parent, err := n.GetStatusByAccountURL(ctx, account.ID, item.Link)
+	if err != nil {
+		if !errors.Is(err, db.ErrNoEntries) {
+			log.Errorf(ctx, "Failed to retrieve status of item %s: %s", item.Link, err)
+		}
+		return
+	}
+
+	cache, err := n.GetRssCommentFeed(ctx, account.ID, commentsUrl)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		log.Errorf(ctx, "Failed to retrieve comment feed %s: %s", commentsUrl, err)
+		return
+	}
+
+	var lastModified *time.Time
+	if cache != nil && !cache.LastModified.IsZero() {
+		lastModified = &cache.LastModified
+	}
+	etag := ""
+	if cache != nil {
+		etag = cache.ETag
+	}
+
+	comments, err := n.fetcher.FetchFeed(ctx, commentsUrl, etag, lastModified)
+	if err != nil {
+		log.Warnf(ctx, "Failed to fetch comment feed %s: %s", commentsUrl, err)
+		return
+	}
+	if comments.Feed == nil {
+		return // not modified
+	}
+
+	items := datedItems(comments.Feed.Items)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].PublishedParsed.Before(*items[j].PublishedParsed)
+	})
+
+	for _, comment := range items {
+		if comment.Link == "" {
+			continue
+		}
+
+		if _, err := n.GetStatusByAccountURL(ctx, account.ID, comment.Link); err == nil {
+			continue // already ingested
+		} else if !errors.Is(err, db.ErrNoEntries) {
+			log.Errorf(ctx, "Failed to check comment %s: %s", comment.Link, err)
+			continue
+		}
+
+		// Nested comments reply to their parent
+		// comment, the others to the item itself.
+		inReplyTo := n.itemParent(ctx, account, comment)
+		if inReplyTo == nil {
+			inReplyTo = parent
+		}
+
+		// Comments are written by anyone: their html is
+		// sanitized, as the one of newsletters, before
+		// making its way into the status.
+		comment.Title = strings.TrimSpace(text.SanitizeToHTML(comment.Title))
+		comment.Description = strings.TrimSpace(text.SanitizeToHTML(comment.Description))
+		comment.Content = strings.TrimSpace(text.SanitizeToHTML(comment.Content))
+
+		if _, err := n.createStatus(ctx, &ToCreate{
+			Account:   account,
+			Item:      comment,
+			InReplyTo: inReplyTo,
+			Comment:   true,
+		}); err != nil {
+			log.Errorf(ctx, "Failed to create comment %s: %s", comment.Link, err)
+		}
+	}
+
+	// The cache headers are only kept once the comments are stored.
+	if cache == nil {
+		cache = &gtsmodel.RssCommentFeed{
+			ID:           id.NewULID(),
+			CreatedAt:    time.Now(),
+			AccountID:    account.ID,
+			URL:          commentsUrl,
+			ETag:         comments.Etag,
+			LastModified: util.PtrValueOr(comments.LastModified, time.Time{}),
+		}
+		err = n.PutRssCommentFeed(ctx, cache)
+	} else {
+		cache.ETag = comments.Etag
+		cache.LastModified = util.PtrValueOr(comments.LastModified, time.Time{})
+		err = n.UpdateRssCommentFeed(ctx, cache, "etag", "last_modified")
+	}
+	if err != nil {
+		log.Errorf(ctx, "Failed to save comment feed %s: %s", commentsUrl, err)
+	}
+}
+
+// pruneCommentFeeds forgets the cache headers of the comment feeds of the items past commentsWindow.
+func (n *rssTooter) pruneCommentFeeds(ctx context.Context) {
+	if err := n.DeleteRssCommentFeedsBefore(ctx, time.Now().Add(-commentsWindow)); err != nil {
+		log.Errorf(ctx, "Failed to delete old comment feeds: %s", err)
+	}
+}
+
+// itemParent returns the status of the item this item replies to, as advertised
+// by an Atom thr:in-reply-to element, or nil if there is none or it is unknown.
+func (n *rssTooter) itemParent(ctx context.Context, account *gtsmodel.Account, item *gofeed.Item) *gtsmodel.Status {
+	for _, inReplyTo := range extensions(item, "thr", "in-reply-to") {
+		for _, ref := range []string{inReplyTo.Attrs["href"], inReplyTo.Attrs["ref"]} {
+			if ref == "" || ref == item.Link {
+				continue
+			}
+
+			if status, err := n.GetStatusByAccountURL(ctx, account.ID, ref); err == nil {
+				return status
+			}
+		}
+	}
+
+	return nil
+}
+
+// commentFeed returns the url of the comment feed of an item (wfw:commentRss),
+// or an empty string if it has none or announces no comment (slash:comments).
+func commentFeed(item *gofeed.Item) string {
+	if count := extensions(item, "slash", "comments"); len(count) > 0 && count[0].Value == "0" {
+		return ""
+	}
+
+	if commentRss := extensions(item, "wfw", "commentRss"); len(commentRss) > 0 {
+		return commentRss[0].Value
+	}
+
+	return ""
+}
+
+// extensions returns the extension elements of an item with the given namespace prefix and name.
+func extensions(item *gofeed.Item, prefix string, name string) []ext.Extension {
+	if item.Extensions == nil {
+		return nil
+	}
+
+	return item.Extensions[prefix][name]
+}
@@ -0,0 +1,118 @@
+package rss
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+type CommentsTestSuite struct {
+	RssStandardTestSuite
+}
+
+// commentedItem returns the first item of rss2.xml, published at the
+// given time and announcing the comment feed of the comments fixture.
+func (suite *CommentsTestSuite) commentedItem(published time.Time) *gofeed.Item {
+	return &gofeed.Item{
+		Link:            "https://blog.example.org/2024/06/03/tomatoes-again/",
+		PublishedParsed: &published,
+		Extensions: ext.Extensions{
+			"wfw": {"commentRss": {{Value: suite.fixtureURL("comments.xml")}}},
+		},
+	}
+}
+
+// pollFeed ingests the items of rss2.xml, and drops the messages they queued.
+func (suite *CommentsTestSuite) pollFeed(account *gtsmodel.Account) {
+	feed := &gtsmodel.RssFeed{
+		ID:        id.NewULID(),
+		AccountID: account.ID,
+		URL:       suite.fixtureURL("rss2.xml"),
+	}
+	if err := suite.tooter.PutRssFeed(context.Background(), feed); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.tooter.poll(context.Background())
+	for suite.state.Workers.Client.Queue.Len() > 0 {
+		suite.state.Workers.Client.Queue.Pop()
+	}
+}
+
+func (suite *CommentsTestSuite) TestIngestComments() {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_1"]
+	config.SetRssFetchComments(true)
+	suite.pollFeed(account)
+
+	item := suite.commentedItem(time.Now().Add(-time.Hour))
+	parent, err := suite.tooter.GetStatusByAccountURL(ctx, account.ID, item.Link)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	fetcher := &recordingFetcher{Fetcher: suite.tooter.fetcher}
+	suite.tooter.fetcher = fetcher
+	suite.tooter.ingestComments(ctx, account, item)
+
+	for _, link := range []string{
+		"https://blog.example.org/2024/06/03/tomatoes-again/#comment-12",
+		"https://blog.example.org/2024/06/03/tomatoes-again/#comment-13",
+	} {
+		comment, err := suite.tooter.GetStatusByAccountURL(ctx, account.ID, link)
+		if suite.NoError(err, link) {
+			suite.Equal(parent.ID, comment.InReplyToID)
+			suite.NotContains(comment.Content, "steal()")
+			suite.NotContains(comment.Text, "steal()")
+		}
+	}
+
+	// Comments are stored without being published.
+	suite.Zero(suite.state.Workers.Client.Queue.Len())
+
+	cache, err := suite.tooter.GetRssCommentFeed(ctx, account.ID, suite.fixtureURL("comments.xml"))
+	if suite.NoError(err) {
+		suite.Equal(`"comments.xml-v1"`, cache.ETag)
+		suite.True(fixtureModTime.Equal(cache.LastModified))
+	}
+
+	// The next fetch is conditional, and gets a 304.
+	comments, err := suite.tooter.fetcher.FetchFeed(ctx, cache.URL, cache.ETag, &cache.LastModified)
+	suite.NoError(err)
+	suite.Nil(comments.Feed)
+	suite.Len(fetcher.urls, 2)
+}
+
+func (suite *CommentsTestSuite) TestIngestCommentsOldItem() {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_1"]
+	config.SetRssFetchComments(true)
+	suite.pollFeed(account)
+
+	fetcher := &recordingFetcher{Fetcher: suite.tooter.fetcher}
+	suite.tooter.fetcher = fetcher
+	suite.tooter.ingestComments(ctx, account, suite.commentedItem(time.Now().Add(-2*commentsWindow)))
+	suite.Empty(fetcher.urls)
+}
+
+func (suite *CommentsTestSuite) TestIngestCommentsDisabled() {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_1"]
+	suite.pollFeed(account)
+
+	fetcher := &recordingFetcher{Fetcher: suite.tooter.fetcher}
+	suite.tooter.fetcher = fetcher
+	suite.tooter.ingestComments(ctx, account, suite.commentedItem(time.Now()))
+	suite.Empty(fetcher.urls)
+}
+
+func TestCommentsTestSuite(t *testing.T) {
+	suite.Run(t, new(CommentsTestSuite))
+}
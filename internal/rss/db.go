@@ -6,6 +6,10 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/uptrace/bun"
 )
 
 type ToPoll struct {
@@ -13,6 +17,8 @@ type ToPoll struct {
 	DBUsername  	string
 	Url  					string
 	LastTweet  		time.Time
+	Digest  			gtsmodel.RssDigest
+	Scraper  			*gtsmodel.RssScraper
 }
 
 func (n *rssTooter) GetAccountsToPoll(ctx context.Context) ([]*ToPoll, error) {
@@ -21,31 +27,927 @@ func (n *rssTooter) GetAccountsToPoll(ctx context.Context) ([]*ToPoll, error) {
 	err := n.state.DB.DB().NewSelect().
 		ColumnExpr("accounts.id AS db_account_id").
 		ColumnExpr("accounts.username AS db_username").
-		ColumnExpr("accounts.url AS url").
-		ColumnExpr("max(statuses.created_at) AS last_tweet").
+		ColumnExpr("rss_feeds.url AS url").
+		ColumnExpr("rss_feeds.last_item_at AS last_tweet").
+		ColumnExpr("rss_feeds.digest AS digest").
+		ColumnExpr("rss_feeds.scraper AS scraper").
 		TableExpr("accounts").
 		Join("INNER JOIN follows on target_account_id = accounts.id").
-		Join("LEFT JOIN statuses on accounts.id = statuses.account_id").
-		Where("actor_type = 'Person'").
-		Where("bot = true").
+		Join("INNER JOIN rss_feeds on accounts.id = rss_feeds.account_id").
 		Where("domain IS NULL").
 		GroupExpr("accounts.id").
 		GroupExpr("accounts.username").
-		GroupExpr("accounts.url").
+		GroupExpr("rss_feeds.url").
+		GroupExpr("rss_feeds.last_item_at").
+		GroupExpr("rss_feeds.digest").
+		GroupExpr("rss_feeds.scraper").
 		Scan(ctx, &toPoll)
 
 	return toPoll, err
 }
 
+// GetRssFeedByID returns the feed with the given id.
+func (n *rssTooter) GetRssFeedByID(ctx context.Context, id string) (*gtsmodel.RssFeed, error) {
+	feed := new(gtsmodel.RssFeed)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(feed).
+		Where("id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+func (n *rssTooter) GetRssFeedByAccountID(ctx context.Context, accountID string) (*gtsmodel.RssFeed, error) {
+	feed := new(gtsmodel.RssFeed)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(feed).
+		Where("account_id = ?", accountID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+func (n *rssTooter) PutRssFeed(ctx context.Context, feed *gtsmodel.RssFeed) error {
+	_, err := n.state.DB.DB().NewInsert().
+		Model(feed).
+		Exec(ctx)
+
+	return err
+}
+
+func (n *rssTooter) UpdateRssFeed(ctx context.Context, feed *gtsmodel.RssFeed, columns ...string) error {
+	feed.UpdatedAt = time.Now()
+	if len(columns) > 0 {
+		columns = append(columns, "updated_at")
+	}
+
+	_, err := n.state.DB.DB().NewUpdate().
+		Model(feed).
+		Column(columns...).
+		Where("id = ?", feed.ID).
+		Exec(ctx)
+
+	return err
+}
+
+// GetRssFeeds returns all the feeds.
+func (n *rssTooter) GetRssFeeds(ctx context.Context) ([]*gtsmodel.RssFeed, error) {
+	feeds := make([]*gtsmodel.RssFeed, 0)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(&feeds).
+		Scan(ctx)
+
+	return feeds, err
+}
+
+// GetDigestFeeds returns the feeds publishing their items as a digest.
+func (n *rssTooter) GetDigestFeeds(ctx context.Context) ([]*gtsmodel.RssFeed, error) {
+	feeds := make([]*gtsmodel.RssFeed, 0)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(&feeds).
+		Where("digest IS NOT NULL").
+		Scan(ctx)
+
+	return feeds, err
+}
+
+// GetReplyForwardFeeds returns the feeds forwarding the replies to their statuses.
+func (n *rssTooter) GetReplyForwardFeeds(ctx context.Context) ([]*gtsmodel.RssFeed, error) {
+	feeds := make([]*gtsmodel.RssFeed, 0)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(&feeds).
+		WhereOr("reply_webhook_url IS NOT NULL").
+		WhereOr("reply_email IS NOT NULL").
+		Scan(ctx)
+
+	return feeds, err
+}
+
+// GetMentionsSince returns the mention notifications of an account with an id greater than sinceID, oldest first.
+func (n *rssTooter) GetMentionsSince(ctx context.Context, accountID string, sinceID string) ([]*gtsmodel.Notification, error) {
+	var notificationIDs []string
+
+	q := n.state.DB.DB().NewSelect().
+		Column("id").
+		TableExpr("notifications").
+		Where("target_account_id = ?", accountID).
+		Where("notification_type = ?", gtsmodel.NotificationMention).
+		Order("id ASC")
+	if sinceID != "" {
+		q = q.Where("id > ?", sinceID)
+	}
+
+	if err := q.Scan(ctx, &notificationIDs); err != nil {
+		return nil, err
+	}
+
+	return n.state.DB.GetNotificationsByIDs(ctx, notificationIDs)
+}
+
+// GetStatusesSince returns the statuses of an account with an id greater than sinceID, oldest first.
+func (n *rssTooter) GetStatusesSince(ctx context.Context, accountID string, sinceID string) ([]*gtsmodel.Status, error) {
+	var statusIDs []string
+
+	q := n.state.DB.DB().NewSelect().
+		Column("id").
+		TableExpr("statuses").
+		Where("account_id = ?", accountID).
+		Order("id ASC")
+	if sinceID != "" {
+		q = q.Where("id > ?", sinceID)
+	}
+
+	if err := q.Scan(ctx, &statusIDs); err != nil {
+		return nil, err
+	}
+
+	return n.state.DB.GetStatusesByIDs(ctx, statusIDs)
+}
+
+// GetStatusByAccountURL returns the status created by an account for the item with the given link.
+func (n *rssTooter) GetStatusByAccountURL(ctx context.Context, accountID string, url string) (*gtsmodel.Status, error) {
+	var statusID string
+
+	err := n.state.DB.DB().NewSelect().
+		Column("id").
+		TableExpr("statuses").
+		Where("account_id = ?", accountID).
+		Where("url = ?", url).
+		Order("id ASC").
+		Limit(1).
+		Scan(ctx, &statusID)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.state.DB.GetStatusByID(ctx, statusID)
+}
+
+// GetLocalStatusesWithoutCard returns the public and unlisted statuses of local accounts other than feed
+// accounts, with an id between sinceID and untilID and no preview card, leaving out boosts, oldest first.
+func (n *rssTooter) GetLocalStatusesWithoutCard(ctx context.Context, sinceID string, untilID string, limit int) ([]*gtsmodel.Status, error) {
+	var statusIDs []string
+
+	err := n.state.DB.DB().NewSelect().
+		Column("id").
+		TableExpr("statuses").
+		Where("local = ?", true).
+		Where("visibility IN (?)", bun.In([]gtsmodel.Visibility{gtsmodel.VisibilityPublic, gtsmodel.VisibilityUnlocked})).
+		Where("id > ?", sinceID).
+		Where("id < ?", untilID).
+		Where("preview_card_id IS NULL").
+		Where("boost_of_id IS NULL").
+		Where("account_id NOT IN (SELECT account_id FROM rss_feeds)").
+		Order("id ASC").
+		Limit(limit).
+		Scan(ctx, &statusIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.state.DB.GetStatusesByIDs(ctx, statusIDs)
+}
+
+// GetRssCursor returns the id of the last status the job name went through.
+func (n *rssTooter) GetRssCursor(ctx context.Context, name string) (string, error) {
+	var statusID string
+
+	err := n.state.DB.DB().NewSelect().
+		Column("status_id").
+		TableExpr("rss_cursors").
+		Where("name = ?", name).
+		Scan(ctx, &statusID)
+
+	return statusID, err
+}
+
+// PutRssCursor stores the id of the last status the job name went through.
+func (n *rssTooter) PutRssCursor(ctx context.Context, name string, statusID string) error {
+	_, err := n.state.DB.DB().NewInsert().
+		Model(&gtsmodel.RssCursor{Name: name, UpdatedAt: time.Now(), StatusID: statusID}).
+		On("CONFLICT (?) DO UPDATE", bun.Ident("name")).
+		Set("? = EXCLUDED.?", bun.Ident("updated_at"), bun.Ident("updated_at")).
+		Set("? = EXCLUDED.?", bun.Ident("status_id"), bun.Ident("status_id")).
+		Exec(ctx)
+
+	return err
+}
+
+// GetFeedTokensByAccountID returns the feed tokens of an account, oldest first.
+func (n *rssTooter) GetFeedTokensByAccountID(ctx context.Context, accountID string) ([]*gtsmodel.FeedToken, error) {
+	tokens := make([]*gtsmodel.FeedToken, 0)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(&tokens).
+		Where("account_id = ?", accountID).
+		Order("id ASC").
+		Scan(ctx)
+
+	return tokens, err
+}
+
+// GetFeedTokenByToken returns the feed token with the given secret.
+func (n *rssTooter) GetFeedTokenByToken(ctx context.Context, token string) (*gtsmodel.FeedToken, error) {
+	feedToken := new(gtsmodel.FeedToken)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(feedToken).
+		Where("token_hash = ?", appPasswordHash(token)).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return feedToken, nil
+}
+
+func (n *rssTooter) PutFeedToken(ctx context.Context, token *gtsmodel.FeedToken) error {
+	_, err := n.state.DB.DB().NewInsert().
+		Model(token).
+		Exec(ctx)
+
+	return err
+}
+
+// DeleteFeedTokenByID deletes a feed token of an account, returning db.ErrNoEntries if it has no such token.
+func (n *rssTooter) DeleteFeedTokenByID(ctx context.Context, accountID string, id string) error {
+	res, err := n.state.DB.DB().NewDelete().
+		TableExpr("feed_tokens").
+		Where("id = ?", id).
+		Where("account_id = ?", accountID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return db.ErrNoEntries
+	}
+
+	return nil
+}
+
+// GetAppPasswordsByAccountID returns the app passwords of an account, oldest first.
+func (n *rssTooter) GetAppPasswordsByAccountID(ctx context.Context, accountID string) ([]*gtsmodel.AppPassword, error) {
+	passwords := make([]*gtsmodel.AppPassword, 0)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(&passwords).
+		Where("account_id = ?", accountID).
+		Order("id ASC").
+		Scan(ctx)
+
+	return passwords, err
+}
+
+// GetAppPasswordByPassword returns the app password with the given secret.
+func (n *rssTooter) GetAppPasswordByPassword(ctx context.Context, password string) (*gtsmodel.AppPassword, error) {
+	appPassword := new(gtsmodel.AppPassword)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(appPassword).
+		Where("password_hash = ?", appPasswordHash(password)).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return appPassword, nil
+}
+
+// GetAppPasswordByFeverKey returns the app password with the given Fever API key.
+func (n *rssTooter) GetAppPasswordByFeverKey(ctx context.Context, feverKey string) (*gtsmodel.AppPassword, error) {
+	appPassword := new(gtsmodel.AppPassword)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(appPassword).
+		Where("fever_key_hash = ?", appPasswordHash(feverKey)).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return appPassword, nil
+}
+
+func (n *rssTooter) PutAppPassword(ctx context.Context, appPassword *gtsmodel.AppPassword) error {
+	_, err := n.state.DB.DB().NewInsert().
+		Model(appPassword).
+		Exec(ctx)
+
+	return err
+}
+
+// DeleteAppPasswordByID deletes an app password of an account, returning db.ErrNoEntries if it has no such password.
+func (n *rssTooter) DeleteAppPasswordByID(ctx context.Context, accountID string, id string) error {
+	res, err := n.state.DB.DB().NewDelete().
+		TableExpr("app_passwords").
+		Where("id = ?", id).
+		Where("account_id = ?", accountID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return db.ErrNoEntries
+	}
+
+	return nil
+}
+
+// GetFollowedRssFeeds returns the feeds of the feed accounts followed by an account.
+func (n *rssTooter) GetFollowedRssFeeds(ctx context.Context, accountID string) ([]*gtsmodel.RssFeed, error) {
+	feeds := make([]*gtsmodel.RssFeed, 0)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(&feeds).
+		Join("INNER JOIN follows ON follows.target_account_id = rss_feed.account_id").
+		Where("follows.account_id = ?", accountID).
+		Order("rss_feed.account_id ASC").
+		Scan(ctx)
+
+	return feeds, err
+}
+
+// GetStatusIDsOfAccounts returns the ids of the statuses of the given accounts between
+// maxID and minID, both excluded when set, newest first unless oldest is set.
+func (n *rssTooter) GetStatusIDsOfAccounts(ctx context.Context, accountIDs []string, maxID string, minID string, limit int, oldest bool) ([]string, error) {
+	statusIDs := make([]string, 0)
+	if len(accountIDs) == 0 {
+		return statusIDs, nil
+	}
+
+	q := n.state.DB.DB().NewSelect().
+		Column("id").
+		TableExpr("statuses").
+		Where("account_id IN (?)", bun.In(accountIDs)).
+		Limit(limit)
+	if maxID != "" {
+		q = q.Where("id < ?", maxID)
+	}
+	if minID != "" {
+		q = q.Where("id > ?", minID)
+	}
+	if oldest {
+		q = q.Order("id ASC")
+	} else {
+		q = q.Order("id DESC")
+	}
+
+	err := q.Scan(ctx, &statusIDs)
+
+	return statusIDs, err
+}
+
+// GetBookmarkedStatusIDs returns the ids of the statuses bookmarked by an account between
+// maxID and minID, both excluded when set, newest first unless oldest is set.
+func (n *rssTooter) GetBookmarkedStatusIDs(ctx context.Context, accountID string, maxID string, minID string, limit int, oldest bool) ([]string, error) {
+	statusIDs := make([]string, 0)
+
+	q := n.state.DB.DB().NewSelect().
+		Column("status_id").
+		TableExpr("status_bookmarks").
+		Where("account_id = ?", accountID).
+		Limit(limit)
+	if maxID != "" {
+		q = q.Where("status_id < ?", maxID)
+	}
+	if minID != "" {
+		q = q.Where("status_id > ?", minID)
+	}
+	if oldest {
+		q = q.Order("status_id ASC")
+	} else {
+		q = q.Order("status_id DESC")
+	}
+
+	err := q.Scan(ctx, &statusIDs)
+
+	return statusIDs, err
+}
+
+// GetStatusIDsBetween returns the ids of the statuses from lowID to highID, both included.
+func (n *rssTooter) GetStatusIDsBetween(ctx context.Context, lowID string, highID string) ([]string, error) {
+	statusIDs := make([]string, 0)
+
+	err := n.state.DB.DB().NewSelect().
+		Column("id").
+		TableExpr("statuses").
+		Where("id >= ?", lowID).
+		Where("id <= ?", highID).
+		Order("id ASC").
+		Scan(ctx, &statusIDs)
+
+	return statusIDs, err
+}
+
+// CountStatusesOfAccounts returns the number of statuses of each of the
+// given accounts newer than minID, if set, with the newest of them.
+func (n *rssTooter) CountStatusesOfAccounts(ctx context.Context, accountIDs []string, minID string) ([]*ReaderItemCount, error) {
+	counts := make([]*ReaderItemCount, 0)
+	if len(accountIDs) == 0 {
+		return counts, nil
+	}
+
+	err := n.state.DB.DB().NewSelect().
+		ColumnExpr("account_id").
+		ColumnExpr("COUNT(*) AS count").
+		ColumnExpr("MAX(id) AS newest_id").
+		TableExpr("statuses").
+		Where("account_id IN (?)", bun.In(accountIDs)).
+		Where("id > ?", minID).
+		GroupExpr("account_id").
+		Scan(ctx, &counts)
+
+	return counts, err
+}
+
+// GetExpiredStatusIDs returns the ids of the item statuses of an account published before
+// before, if set, or older than its keep most recent item statuses, if set, oldest first.
+// Pinned statuses, statuses bookmarked or faved by anyone, and the statuses not created
+// from feed items (digests, boosts, statuses of delegates) are left out.
+func (n *rssTooter) GetExpiredStatusIDs(ctx context.Context, accountID string, before time.Time, keep int, limit int) ([]string, error) {
+	statusIDs := make([]string, 0)
+	if before.IsZero() && keep <= 0 {
+		return statusIDs, nil
+	}
+
+	err := n.state.DB.DB().NewSelect().
+		Column("id").
+		TableExpr("statuses").
+		Where("account_id = ?", accountID).
+		Where("id IN (SELECT status_id FROM rss_items WHERE account_id = ?)", accountID).
+		Where("pinned_at IS NULL").
+		Where("NOT EXISTS (SELECT 1 FROM status_bookmarks WHERE status_bookmarks.status_id = statuses.id)").
+		Where("NOT EXISTS (SELECT 1 FROM status_faves WHERE status_faves.status_id = statuses.id)").
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			if !before.IsZero() {
+				q = q.WhereOr("created_at < ?", before)
+			}
+			if keep > 0 {
+				q = q.WhereOr("id NOT IN (SELECT id FROM statuses WHERE account_id = ? AND id IN (SELECT status_id FROM rss_items WHERE account_id = ?) ORDER BY created_at DESC, id DESC LIMIT ?)", accountID, accountID, keep)
+			}
+			return q
+		}).
+		Order("created_at ASC").
+		Limit(limit).
+		Scan(ctx, &statusIDs)
+
+	return statusIDs, err
+}
+
+// IsRssItemIngested returns whether account already posted an item with the given
+// link, even if its status was deleted since.
+func (n *rssTooter) IsRssItemIngested(ctx context.Context, accountID string, link string) (bool, error) {
+	return n.state.DB.DB().NewSelect().
+		TableExpr("rss_items").
+		Where("account_id = ?", accountID).
+		Where("link = ?", link).
+		Exists(ctx)
+}
+
+// PutRssItem records an item ingested from a feed.
+func (n *rssTooter) PutRssItem(ctx context.Context, item *gtsmodel.RssItem) error {
+	_, err := n.state.DB.DB().NewInsert().
+		Model(item).
+		Exec(ctx)
+
+	return err
+}
+
+// GetRssArticleByURL returns the indexed article with the given canonical url.
+func (n *rssTooter) GetRssArticleByURL(ctx context.Context, url string) (*gtsmodel.RssArticle, error) {
+	article := new(gtsmodel.RssArticle)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(article).
+		Where("url = ?", url).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return article, nil
+}
+
+// PutRssArticle indexes an article, unless its url is already indexed.
+func (n *rssTooter) PutRssArticle(ctx context.Context, article *gtsmodel.RssArticle) error {
+	_, err := n.state.DB.DB().NewInsert().
+		Model(article).
+		On("CONFLICT (?) DO NOTHING", bun.Ident("url")).
+		Exec(ctx)
+
+	return err
+}
+
+// DeleteRssArticleByURL removes an article from the index.
+func (n *rssTooter) DeleteRssArticleByURL(ctx context.Context, url string) error {
+	_, err := n.state.DB.DB().NewDelete().
+		TableExpr("rss_articles").
+		Where("url = ?", url).
+		Exec(ctx)
+
+	return err
+}
+
+func (n *rssTooter) GetRssCommentFeed(ctx context.Context, accountID string, url string) (*gtsmodel.RssCommentFeed, error) {
+	commentFeed := new(gtsmodel.RssCommentFeed)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(commentFeed).
+		Where("account_id = ?", accountID).
+		Where("url = ?", url).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return commentFeed, nil
+}
+
+func (n *rssTooter) PutRssCommentFeed(ctx context.Context, commentFeed *gtsmodel.RssCommentFeed) error {
+	_, err := n.state.DB.DB().NewInsert().
+		Model(commentFeed).
+		Exec(ctx)
+
+	return err
+}
+
+func (n *rssTooter) UpdateRssCommentFeed(ctx context.Context, commentFeed *gtsmodel.RssCommentFeed, columns ...string) error {
+	_, err := n.state.DB.DB().NewUpdate().
+		Model(commentFeed).
+		Column(columns...).
+		Where("id = ?", commentFeed.ID).
+		Exec(ctx)
+
+	return err
+}
+
+// DeleteRssCommentFeedsBefore deletes the comment feeds first fetched before before.
+func (n *rssTooter) DeleteRssCommentFeedsBefore(ctx context.Context, before time.Time) error {
+	_, err := n.state.DB.DB().NewDelete().
+		TableExpr("rss_comment_feeds").
+		Where("created_at < ?", before).
+		Exec(ctx)
+
+	return err
+}
+
+// GetRssWebhooks returns the webhooks of a feed account, or the server-wide ones if accountID is empty, oldest first.
+func (n *rssTooter) GetRssWebhooks(ctx context.Context, accountID string) ([]*gtsmodel.RssWebhook, error) {
+	webhooks := make([]*gtsmodel.RssWebhook, 0)
+
+	q := n.state.DB.DB().NewSelect().
+		Model(&webhooks).
+		Order("id ASC")
+	if accountID == "" {
+		q = q.Where("account_id IS NULL")
+	} else {
+		q = q.Where("account_id = ?", accountID)
+	}
+
+	err := q.Scan(ctx)
+
+	return webhooks, err
+}
+
+// GetRssWebhooksForAccount returns the webhooks receiving the statuses of a feed account: its own and the server-wide ones.
+func (n *rssTooter) GetRssWebhooksForAccount(ctx context.Context, accountID string) ([]*gtsmodel.RssWebhook, error) {
+	webhooks := make([]*gtsmodel.RssWebhook, 0)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(&webhooks).
+		WhereOr("account_id = ?", accountID).
+		WhereOr("account_id IS NULL").
+		Scan(ctx)
+
+	return webhooks, err
+}
+
+func (n *rssTooter) GetRssWebhookByID(ctx context.Context, id string) (*gtsmodel.RssWebhook, error) {
+	webhook := new(gtsmodel.RssWebhook)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(webhook).
+		Where("id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (n *rssTooter) PutRssWebhook(ctx context.Context, webhook *gtsmodel.RssWebhook) error {
+	_, err := n.state.DB.DB().NewInsert().
+		Model(webhook).
+		Exec(ctx)
+
+	return err
+}
+
+// DeleteRssWebhookByID deletes a webhook and its deliveries, returning db.ErrNoEntries if there is no such webhook.
+func (n *rssTooter) DeleteRssWebhookByID(ctx context.Context, id string) error {
+	return n.state.DB.DB().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().
+			TableExpr("rss_webhook_deliveries").
+			Where("webhook_id = ?", id).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		res, err := tx.NewDelete().
+			TableExpr("rss_webhooks").
+			Where("id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+			return db.ErrNoEntries
+		}
+
+		return nil
+	})
+}
+
+func (n *rssTooter) PutRssWebhookDelivery(ctx context.Context, delivery *gtsmodel.RssWebhookDelivery) error {
+	_, err := n.state.DB.DB().NewInsert().
+		Model(delivery).
+		Exec(ctx)
+
+	return err
+}
+
+func (n *rssTooter) GetRssWebhookDeliveryByID(ctx context.Context, id string) (*gtsmodel.RssWebhookDelivery, error) {
+	delivery := new(gtsmodel.RssWebhookDelivery)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(delivery).
+		Where("id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// GetDueRssWebhookDeliveries returns the pending deliveries whose next attempt is due at now, by due date.
+func (n *rssTooter) GetDueRssWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]*gtsmodel.RssWebhookDelivery, error) {
+	deliveries := make([]*gtsmodel.RssWebhookDelivery, 0)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(&deliveries).
+		Where("next_attempt_at <= ?", now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Scan(ctx)
+
+	return deliveries, err
+}
+
+// GetFailedRssWebhookDeliveries returns the deliveries given up on, most recent first.
+func (n *rssTooter) GetFailedRssWebhookDeliveries(ctx context.Context, limit int) ([]*gtsmodel.RssWebhookDelivery, error) {
+	deliveries := make([]*gtsmodel.RssWebhookDelivery, 0)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(&deliveries).
+		Where("failed_at IS NOT NULL").
+		Order("failed_at DESC").
+		Limit(limit).
+		Scan(ctx)
+
+	return deliveries, err
+}
+
+func (n *rssTooter) UpdateRssWebhookDelivery(ctx context.Context, delivery *gtsmodel.RssWebhookDelivery, columns ...string) error {
+	_, err := n.state.DB.DB().NewUpdate().
+		Model(delivery).
+		Column(columns...).
+		Where("id = ?", delivery.ID).
+		Exec(ctx)
+
+	return err
+}
+
+func (n *rssTooter) DeleteRssWebhookDeliveryByID(ctx context.Context, id string) error {
+	_, err := n.state.DB.DB().NewDelete().
+		TableExpr("rss_webhook_deliveries").
+		Where("id = ?", id).
+		Exec(ctx)
+
+	return err
+}
+
+// DeleteRssWebhookDeliveriesFailedBefore deletes the deliveries given up on before before.
+func (n *rssTooter) DeleteRssWebhookDeliveriesFailedBefore(ctx context.Context, before time.Time) error {
+	_, err := n.state.DB.DB().NewDelete().
+		TableExpr("rss_webhook_deliveries").
+		Where("failed_at < ?", before).
+		Exec(ctx)
+
+	return err
+}
+
+// GetRssFeedDelegates returns the delegates of a feed account, oldest first.
+func (n *rssTooter) GetRssFeedDelegates(ctx context.Context, accountID string) ([]*gtsmodel.RssFeedDelegate, error) {
+	delegates := make([]*gtsmodel.RssFeedDelegate, 0)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(&delegates).
+		Where("account_id = ?", accountID).
+		Order("id ASC").
+		Scan(ctx)
+
+	return delegates, err
+}
+
+func (n *rssTooter) PutRssFeedDelegate(ctx context.Context, delegate *gtsmodel.RssFeedDelegate) error {
+	_, err := n.state.DB.DB().NewInsert().
+		Model(delegate).
+		Exec(ctx)
+
+	return err
+}
+
+// DeleteRssFeedDelegate removes a delegate of a feed account, returning db.ErrNoEntries if it isn't one.
+func (n *rssTooter) DeleteRssFeedDelegate(ctx context.Context, accountID string, delegateAccountID string) error {
+	res, err := n.state.DB.DB().NewDelete().
+		TableExpr("rss_feed_delegates").
+		Where("account_id = ?", accountID).
+		Where("delegate_account_id = ?", delegateAccountID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return db.ErrNoEntries
+	}
+
+	return nil
+}
+
+// GetRssFeedClaim returns the claim of a local account on a feed account.
+func (n *rssTooter) GetRssFeedClaim(ctx context.Context, accountID string, claimantAccountID string) (*gtsmodel.RssFeedClaim, error) {
+	claim := new(gtsmodel.RssFeedClaim)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(claim).
+		Where("account_id = ?", accountID).
+		Where("claimant_account_id = ?", claimantAccountID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return claim, nil
+}
+
+// GetPendingRssFeedClaims returns the claims not verified yet, oldest first.
+func (n *rssTooter) GetPendingRssFeedClaims(ctx context.Context) ([]*gtsmodel.RssFeedClaim, error) {
+	claims := make([]*gtsmodel.RssFeedClaim, 0)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(&claims).
+		Where("verified_at IS NULL").
+		Order("id ASC").
+		Scan(ctx)
+
+	return claims, err
+}
+
+// CountPendingRssFeedClaims returns the number of claims of a local account
+// created after since and not verified yet.
+func (n *rssTooter) CountPendingRssFeedClaims(ctx context.Context, claimantAccountID string, since time.Time) (int, error) {
+	return n.state.DB.DB().NewSelect().
+		TableExpr("rss_feed_claims").
+		Where("claimant_account_id = ?", claimantAccountID).
+		Where("verified_at IS NULL").
+		Where("created_at > ?", since).
+		Count(ctx)
+}
+
+func (n *rssTooter) PutRssFeedClaim(ctx context.Context, claim *gtsmodel.RssFeedClaim) error {
+	_, err := n.state.DB.DB().NewInsert().
+		Model(claim).
+		Exec(ctx)
+
+	return err
+}
+
+func (n *rssTooter) UpdateRssFeedClaim(ctx context.Context, claim *gtsmodel.RssFeedClaim, columns ...string) error {
+	_, err := n.state.DB.DB().NewUpdate().
+		Model(claim).
+		Column(columns...).
+		Where("id = ?", claim.ID).
+		Exec(ctx)
+
+	return err
+}
+
+func (n *rssTooter) DeleteRssFeedClaim(ctx context.Context, id string) error {
+	_, err := n.state.DB.DB().NewDelete().
+		TableExpr("rss_feed_claims").
+		Where("id = ?", id).
+		Exec(ctx)
+
+	return err
+}
+
+// DeletePendingRssFeedClaimsBefore deletes the claims created
+// before a given time and still not verified: the expired ones.
+func (n *rssTooter) DeletePendingRssFeedClaimsBefore(ctx context.Context, before time.Time) error {
+	_, err := n.state.DB.DB().NewDelete().
+		TableExpr("rss_feed_claims").
+		Where("verified_at IS NULL").
+		Where("created_at < ?", before).
+		Exec(ctx)
+
+	return err
+}
+
+// GetRssNewsletters returns all the newsletters, oldest first.
+func (n *rssTooter) GetRssNewsletters(ctx context.Context) ([]*gtsmodel.RssNewsletter, error) {
+	newsletters := make([]*gtsmodel.RssNewsletter, 0)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(&newsletters).
+		Order("id ASC").
+		Scan(ctx)
+
+	return newsletters, err
+}
+
+// GetRssNewsletterByAddress returns the newsletter received at address.
+func (n *rssTooter) GetRssNewsletterByAddress(ctx context.Context, address string) (*gtsmodel.RssNewsletter, error) {
+	newsletter := new(gtsmodel.RssNewsletter)
+
+	err := n.state.DB.DB().NewSelect().
+		Model(newsletter).
+		Where("address = ?", strings.ToLower(address)).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newsletter, nil
+}
+
+func (n *rssTooter) PutRssNewsletter(ctx context.Context, newsletter *gtsmodel.RssNewsletter) error {
+	_, err := n.state.DB.DB().NewInsert().
+		Model(newsletter).
+		Exec(ctx)
+
+	return err
+}
+
+func (n *rssTooter) UpdateRssNewsletter(ctx context.Context, newsletter *gtsmodel.RssNewsletter, columns ...string) error {
+	_, err := n.state.DB.DB().NewUpdate().
+		Model(newsletter).
+		Column(columns...).
+		Where("id = ?", newsletter.ID).
+		Exec(ctx)
+
+	return err
+}
+
+// updateLastItemAt records the publication date of an ingested item, if more recent than the known ones.
+func (n *rssTooter) updateLastItemAt(ctx context.Context, accountID string, publishedAt time.Time) error {
+	_, err := n.state.DB.DB().NewUpdate().
+		TableExpr("rss_feeds").
+		Set("last_item_at = ?", publishedAt).
+		Where("account_id = ?", accountID).
+		WhereGroup(" AND ", func(q *bun.UpdateQuery) *bun.UpdateQuery {
+			return q.Where("last_item_at IS NULL").WhereOr("last_item_at < ?", publishedAt)
+		}).
+		Exec(ctx)
+
+	return err
+}
+
 type IdDB interface {
   int64 | string
 }
 
 func ToIdDB[T IdDB](id T) string {
-	idC := any(id)
-	switch idC.(type) {
-		case int64: return fmt.Sprintf("%026s", strconv.FormatInt(idC.(int64), 10))
-		default: return fmt.Sprintf("%026s", id)
+	switch idC := any(id).(type) {
+		case int64: return fmt.Sprintf("%026s", strconv.FormatInt(idC, 10))
+		default: return fmt.Sprintf("%026s", idC)
 	}
 }
 
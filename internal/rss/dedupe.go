@@ -0,0 +1,207 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	netUrl "net/url"
+	"strings"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+)
+
+// trackingParams are the query parameters added to links to follow their
+// audience, left out of article urls, like those starting with utm_.
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"dclid":   true,
+	"msclkid": true,
+	"yclid":   true,
+	"igshid":  true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"_hsenc":  true,
+	"_hsmi":   true,
+	"mkt_tok": true,
+	"ref_src": true,
+}
+
+// articleURL returns the canonical form of the url of an article, to compare the links
+// of different feeds: https, lowercase host without www., no tracking parameters, sorted
+// query, and no fragment nor trailing slash. It returns an empty string if link isn't
+// an absolute http(s) url.
+func articleURL(link string) string {
+	u, err := netUrl.Parse(strings.TrimSpace(link))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+
+	u.Scheme = "https"
+	u.User = nil
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+	u.Host = host
+
+	query := u.Query()
+	for key := range query {
+		if trackingParams[strings.ToLower(key)] || strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+	u.ForceQuery = false
+
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = strings.TrimSuffix(u.RawPath, "/")
+
+	return u.String()
+}
+
+// resolveArticleURL returns the canonical url of the article of page, from its
+// rel="canonical" link with rss-deduplicate-canonical, or the page link itself.
+func (n *rssTooter) resolveArticleURL(ctx context.Context, page *itemPage) string {
+	link := page.link
+	articleUrl := articleURL(link)
+	if articleUrl == "" || !config.GetRssDeduplicateCanonical() {
+		return articleUrl
+	}
+
+	doc, err := page.load(ctx, n.fetcher)
+	if err != nil {
+		log.Debugf(ctx, "Failed to fetch article %s: %s", link, err)
+		return articleUrl
+	}
+
+	node := htmlquery.FindOne(doc, "//link[@rel='canonical']")
+	if node == nil {
+		return articleUrl
+	}
+
+	pageUrl, _ := netUrl.Parse(link)
+	if canonical := articleURL(resolveCardURL(pageUrl, htmlquery.SelectAttr(node, "href"))); canonical != "" {
+		return canonical
+	}
+	return articleUrl
+}
+
+// boostDuplicate boosts the status of the article of toCreate if another feed account
+// already posted it, instead of posting it again, returning whether the item is done.
+// Articles already posted by the same feed account, under another url, are skipped.
+// Digest and backfilled items, only stored, and articles whose status can't be
+// boosted by the feed account get their own status.
+func (n *rssTooter) boostDuplicate(ctx context.Context, toCreate *ToCreate) (bool, error) {
+	if toCreate.ArticleURL == "" {
+		return false, nil
+	}
+
+	article, err := n.GetRssArticleByURL(ctx, toCreate.ArticleURL)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			return false, nil
+		}
+		return false, gtserror.Newf("couldn't get article: %w", err)
+	}
+
+	original, err := n.state.DB.GetStatusByID(ctx, article.StatusID)
+	if err != nil {
+		if !errors.Is(err, db.ErrNoEntries) {
+			return false, gtserror.Newf("couldn't get article status: %w", err)
+		}
+
+		// Deleted since, like statuses past their
+		// retention: the article is posted again.
+		if err := n.DeleteRssArticleByURL(ctx, article.URL); err != nil {
+			return false, gtserror.Newf("couldn't delete article: %w", err)
+		}
+		return false, nil
+	}
+
+	if original.AccountID != toCreate.Account.ID {
+		if toCreate.Digest || toCreate.Backfill {
+			return false, nil
+		}
+
+		boostable, err := n.visFilter.StatusBoostable(ctx, toCreate.Account, original)
+		if err != nil {
+			return false, gtserror.Newf("couldn't check article status boostable: %w", err)
+		}
+		if !boostable {
+			return false, nil
+		}
+
+		if err := n.putBoost(ctx, toCreate.Account, original); err != nil {
+			return false, err
+		}
+	}
+
+	// The item is done, like a posted one.
+	if err := n.updateLastItemAt(ctx, toCreate.Account.ID, *toCreate.Item.PublishedParsed); err != nil {
+		log.Errorf(ctx, "Failed to update feed last item date: %s", err)
+	}
+
+	return true, nil
+}
+
+// putBoost boosts original from account, unless already boosted, and sends
+// the boost to the client API worker for side effects.
+func (n *rssTooter) putBoost(ctx context.Context, account *gtsmodel.Account, original *gtsmodel.Status) error {
+	boosted, err := n.state.DB.IsStatusBoostedBy(ctx, original.ID, account.ID)
+	if err != nil {
+		return gtserror.Newf("couldn't check article status boosted: %w", err)
+	}
+	if boosted {
+		return nil
+	}
+
+	boost, err := n.converter.StatusToBoost(ctx, original, account, "")
+	if err != nil {
+		return gtserror.Newf("couldn't create boost: %w", err)
+	}
+
+	if err := n.state.DB.PutStatus(ctx, boost); err != nil {
+		return gtserror.Newf("couldn't put boost: %w", err)
+	}
+
+	n.state.Workers.Client.Queue.Push(&messages.FromClientAPI{
+		APObjectType:   ap.ActivityAnnounce,
+		APActivityType: ap.ActivityCreate,
+		GTSModel:       boost,
+		Origin:         account,
+		Target:         original.Account,
+	})
+
+	log.Infof(ctx, "Boosted %s from %s instead of posting it again", original.URL, account.Username)
+	return nil
+}
+
+// indexArticle indexes the article of the status posted for toCreate,
+// unless it is a comment or isn't at an http(s) url.
+func (n *rssTooter) indexArticle(ctx context.Context, toCreate *ToCreate, status *gtsmodel.Status) error {
+	articleUrl := toCreate.ArticleURL
+	if articleUrl == "" {
+		articleUrl = articleURL(toCreate.Item.Link)
+	}
+	if articleUrl == "" || toCreate.Comment {
+		return nil
+	}
+
+	return n.PutRssArticle(ctx, &gtsmodel.RssArticle{
+		ID:        id.NewULID(),
+		URL:       articleUrl,
+		StatusID:  status.ID,
+		AccountID: status.AccountID,
+	})
+}
@@ -0,0 +1,169 @@
+package rss
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+type DedupeTestSuite struct {
+	RssStandardTestSuite
+}
+
+func (suite *DedupeTestSuite) TestArticleURL() {
+	for link, expected := range map[string]string{
+		"https://blog.example.org/2024/06/03/tomatoes-again/":                     "https://blog.example.org/2024/06/03/tomatoes-again",
+		"http://WWW.Blog.example.org:80/post?utm_source=rss&utm_medium=feed#more": "https://blog.example.org/post",
+		"https://blog.example.org/post?page=2&fbclid=abc&id=1":                    "https://blog.example.org/post?id=1&page=2",
+		"https://blog.example.org:8443/":                                          "https://blog.example.org:8443",
+		"/2024/05/28/relative/":                                                   "",
+		"mailto:blog@example.org":                                                 "",
+	} {
+		suite.Equal(expected, articleURL(link), link)
+	}
+}
+
+func (suite *DedupeTestSuite) TestResolveArticleURL() {
+	ctx := context.Background()
+	link := suite.fixtureURL("post.html?utm_campaign=feed")
+
+	// The page is only fetched when asked to.
+	suite.Equal(articleURL(suite.fixtureURL("post.html")), suite.tooter.resolveArticleURL(ctx, &itemPage{link: link}))
+
+	config.SetRssDeduplicateCanonical(true)
+	suite.Equal("https://blog.example.org/2024/06/01/first-century", suite.tooter.resolveArticleURL(ctx, &itemPage{link: link}))
+
+	// Pages without canonical link keep their url.
+	suite.Equal(articleURL(suite.fixtureURL("blog.html")), suite.tooter.resolveArticleURL(ctx, &itemPage{link: suite.fixtureURL("blog.html")}))
+}
+
+func (suite *DedupeTestSuite) TestItemPageFetchedOnce() {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_1"]
+	config.SetRssDeduplicateCanonical(true)
+	config.SetRssPreviewCards(true)
+	config.SetRssFetchAuthorHandles(true)
+
+	fetcher := &recordingFetcher{Fetcher: suite.tooter.fetcher}
+	suite.tooter.fetcher = fetcher
+
+	// The canonical url, the preview card and the
+	// byline of the item are read from one request.
+	published := time.Now()
+	item := &gofeed.Item{Title: "First century", Link: suite.fixtureURL("post.html"), PublishedParsed: &published}
+	if err := suite.tooter.PutStatus(ctx, &ToCreate{Account: account, Item: item}); err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal([]string{suite.fixtureURL("post.html")}, fetcher.pages)
+}
+
+func (suite *DedupeTestSuite) TestStoreDigestDuplicate() {
+	ctx := context.Background()
+	backfilled := suite.testAccounts["local_account_1"]
+	digested := suite.testAccounts["local_account_2"]
+
+	_, feed, err := NewRssFeed(&suite.state, suite.tooter.fetcher, ctx, suite.fixtureURL("rss2.xml"))
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.tooter.backfill(ctx, backfilled, feed)
+
+	original, err := suite.tooter.GetStatusByAccountURL(ctx, backfilled.ID, "https://blog.example.org/2024/06/03/tomatoes-again/")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// Items of a digest feed already posted by another feed account
+	// are stored with their own status, not boosted outside the digest.
+	queued := suite.state.Workers.Client.Queue.Len()
+	if err := suite.tooter.PutRssFeed(ctx, &gtsmodel.RssFeed{
+		ID:                 id.NewULID(),
+		AccountID:          digested.ID,
+		URL:                suite.fixtureURL("rss2.xml"),
+		Digest:             gtsmodel.RssDigestDaily,
+		DigestedAt:         time.Now(),
+		DigestLastStatusID: id.NewULID(),
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.tooter.poll(ctx)
+
+	digest, err := suite.tooter.GetStatusByAccountURL(ctx, digested.ID, original.URL)
+	if suite.NoError(err) {
+		suite.NotEqual(original.ID, digest.ID)
+	}
+
+	boosted, err := suite.state.DB.IsStatusBoostedBy(ctx, original.ID, digested.ID)
+	suite.NoError(err)
+	suite.False(boosted)
+	suite.Equal(queued, suite.state.Workers.Client.Queue.Len())
+}
+
+func (suite *DedupeTestSuite) TestBoostDuplicate() {
+	ctx := context.Background()
+
+	accounts := []*gtsmodel.Account{}
+	for _, name := range []string{"local_account_1", "local_account_2"} {
+		account, err := suite.state.DB.GetAccountByID(ctx, suite.testAccounts[name].ID)
+		if err != nil {
+			suite.FailNow(err.Error())
+		}
+		if err := suite.tooter.PutRssFeed(ctx, &gtsmodel.RssFeed{
+			ID:        id.NewULID(),
+			AccountID: account.ID,
+			URL:       suite.fixtureURL("rss2.xml"),
+		}); err != nil {
+			suite.FailNow(err.Error())
+		}
+		accounts = append(accounts, account)
+	}
+
+	suite.tooter.poll(ctx)
+
+	// One feed account posts the article, the other boosts it.
+	var original *gtsmodel.Status
+	var booster *gtsmodel.Account
+	for i, account := range accounts {
+		if status, err := suite.tooter.GetStatusByAccountURL(ctx, account.ID, "https://blog.example.org/2024/06/03/tomatoes-again/"); err == nil {
+			suite.Nil(original)
+			original, booster = status, accounts[1-i]
+		}
+	}
+	if original == nil {
+		suite.FailNow("article not posted")
+	}
+
+	boosted, err := suite.state.DB.IsStatusBoostedBy(ctx, original.ID, booster.ID)
+	suite.NoError(err)
+	suite.True(boosted)
+
+	// Boosting again is a no-op.
+	item := &gofeed.Item{Link: original.URL, PublishedParsed: &original.CreatedAt}
+	done, err := suite.tooter.boostDuplicate(ctx, &ToCreate{Account: booster, Item: item, ArticleURL: articleURL(original.URL)})
+	suite.NoError(err)
+	suite.True(done)
+	boosts, err := suite.state.DB.GetStatusBoosts(ctx, original.ID)
+	suite.NoError(err)
+	suite.Len(boosts, 1)
+
+	// Once the original is deleted, the article is posted again.
+	if err := suite.state.DB.DeleteStatusByID(ctx, original.ID); err != nil {
+		suite.FailNow(err.Error())
+	}
+	done, err = suite.tooter.boostDuplicate(ctx, &ToCreate{Account: booster, Item: item, ArticleURL: articleURL(original.URL)})
+	suite.NoError(err)
+	suite.False(done)
+	_, err = suite.tooter.GetRssArticleByURL(ctx, articleURL(original.URL))
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
+func TestDedupeTestSuite(t *testing.T) {
+	suite.Run(t, new(DedupeTestSuite))
+}
@@ -0,0 +1,123 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+// GetFeedDelegates returns the local accounts acting on behalf of a feed account, if requester is an admin.
+func (n *rssTooter) GetFeedDelegates(ctx context.Context, requester *gtsmodel.User, accountID string) ([]*apimodel.Account, gtserror.WithCode) {
+	feed, errWithCode := n.getDelegatedFeed(ctx, requester, accountID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return n.apiDelegates(ctx, feed)
+}
+
+// AddFeedDelegate lets a local account act on behalf of a feed account, if requester is an admin.
+func (n *rssTooter) AddFeedDelegate(ctx context.Context, requester *gtsmodel.User, accountID string, delegateAccountID string) ([]*apimodel.Account, gtserror.WithCode) {
+	feed, errWithCode := n.getDelegatedFeed(ctx, requester, accountID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	delegateAccount, err := n.state.DB.GetAccountByID(ctx, delegateAccountID)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err := fmt.Errorf("account %s not found", delegateAccountID)
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		err := gtserror.Newf("db error getting delegate account: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if !delegateAccount.IsLocal() {
+		err := fmt.Errorf("account %s is not a local account", delegateAccountID)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	// Feed accounts can't log in, they can't act on behalf of another.
+	if _, err := n.GetRssFeedByAccountID(ctx, delegateAccountID); err == nil {
+		err := fmt.Errorf("account %s is a feed account", delegateAccountID)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	} else if !errors.Is(err, db.ErrNoEntries) {
+		err := gtserror.Newf("db error getting feed: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if err := n.PutRssFeedDelegate(ctx, &gtsmodel.RssFeedDelegate{
+		ID:                id.NewULID(),
+		AccountID:         feed.AccountID,
+		DelegateAccountID: delegateAccount.ID,
+	}); err != nil && !errors.Is(err, db.ErrAlreadyExists) {
+		err := gtserror.Newf("db error putting feed delegate: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return n.apiDelegates(ctx, feed)
+}
+
+// RemoveFeedDelegate stops a local account acting on behalf of a feed account, if requester is an admin.
+func (n *rssTooter) RemoveFeedDelegate(ctx context.Context, requester *gtsmodel.User, accountID string, delegateAccountID string) ([]*apimodel.Account, gtserror.WithCode) {
+	feed, errWithCode := n.getDelegatedFeed(ctx, requester, accountID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if err := n.DeleteRssFeedDelegate(ctx, feed.AccountID, delegateAccountID); err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err := fmt.Errorf("account %s is not a delegate of feed account %s", delegateAccountID, accountID)
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		err := gtserror.Newf("db error deleting feed delegate: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return n.apiDelegates(ctx, feed)
+}
+
+// getDelegatedFeed fetches the feed of a feed account, checking that requester
+// is an admin: delegates can manage the feed, but not who its delegates are.
+func (n *rssTooter) getDelegatedFeed(ctx context.Context, requester *gtsmodel.User, accountID string) (*gtsmodel.RssFeed, gtserror.WithCode) {
+	if !*requester.Admin {
+		err := fmt.Errorf("user %s not allowed to manage the delegates of feed account %s", requester.ID, accountID)
+		return nil, gtserror.NewErrorForbidden(err, err.Error())
+	}
+
+	return n.getManagedFeed(ctx, requester, accountID)
+}
+
+// apiDelegates returns the accounts of the delegates of feed.
+func (n *rssTooter) apiDelegates(ctx context.Context, feed *gtsmodel.RssFeed) ([]*apimodel.Account, gtserror.WithCode) {
+	delegates, err := n.GetRssFeedDelegates(ctx, feed.AccountID)
+	if err != nil {
+		err := gtserror.Newf("db error getting feed delegates: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	accounts := make([]*apimodel.Account, 0, len(delegates))
+	for _, delegate := range delegates {
+		account, err := n.state.DB.GetAccountByID(ctx, delegate.DelegateAccountID)
+		if err != nil {
+			err := gtserror.Newf("db error getting delegate account: %w", err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		apiAccount, err := n.converter.AccountToAPIAccountPublic(ctx, account)
+		if err != nil {
+			err := gtserror.Newf("error converting delegate account: %w", err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		accounts = append(accounts, apiAccount)
+	}
+
+	return accounts, nil
+}
@@ -0,0 +1,93 @@
+package rss
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+type DelegatesTestSuite struct {
+	RssStandardTestSuite
+}
+
+// feedAccount makes local_account_2 the account of a feed.
+func (suite *DelegatesTestSuite) feedAccount() *gtsmodel.Account {
+	account := suite.testAccounts["local_account_2"]
+	if err := suite.tooter.PutRssFeed(context.Background(), &gtsmodel.RssFeed{
+		ID:        id.NewULID(),
+		AccountID: account.ID,
+		URL:       suite.fixtureURL("rss2.xml"),
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+	return account
+}
+
+func (suite *DelegatesTestSuite) TestFeedDelegates() {
+	ctx := context.Background()
+	feedAccount := suite.feedAccount()
+	admin := suite.testUsers["admin_account"]
+	user := suite.testUsers["local_account_1"]
+
+	// Not a delegate yet.
+	_, errWithCode := suite.tooter.GetFeed(ctx, user, feedAccount.ID)
+	suite.Equal(http.StatusForbidden, errWithCode.Code())
+
+	delegates, errWithCode := suite.tooter.AddFeedDelegate(ctx, admin, feedAccount.ID, user.AccountID)
+	suite.Nil(errWithCode)
+	suite.Len(delegates, 1)
+	suite.Equal(user.AccountID, delegates[0].ID)
+
+	// Adding it again is a no-op.
+	delegates, errWithCode = suite.tooter.AddFeedDelegate(ctx, admin, feedAccount.ID, user.AccountID)
+	suite.Nil(errWithCode)
+	suite.Len(delegates, 1)
+
+	delegate, err := suite.state.DB.IsRssFeedDelegate(ctx, feedAccount.ID, user.AccountID)
+	suite.NoError(err)
+	suite.True(delegate)
+
+	// The delegate manages the feed, but not its delegates.
+	feed, errWithCode := suite.tooter.GetFeed(ctx, user, feedAccount.ID)
+	suite.Nil(errWithCode)
+	suite.Equal(feedAccount.ID, feed.AccountID)
+
+	_, errWithCode = suite.tooter.GetFeedDelegates(ctx, user, feedAccount.ID)
+	suite.Equal(http.StatusForbidden, errWithCode.Code())
+
+	delegates, errWithCode = suite.tooter.RemoveFeedDelegate(ctx, admin, feedAccount.ID, user.AccountID)
+	suite.Nil(errWithCode)
+	suite.Empty(delegates)
+
+	_, errWithCode = suite.tooter.RemoveFeedDelegate(ctx, admin, feedAccount.ID, user.AccountID)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+
+	_, errWithCode = suite.tooter.GetFeed(ctx, user, feedAccount.ID)
+	suite.Equal(http.StatusForbidden, errWithCode.Code())
+}
+
+func (suite *DelegatesTestSuite) TestAddFeedDelegateInvalid() {
+	ctx := context.Background()
+	feedAccount := suite.feedAccount()
+	admin := suite.testUsers["admin_account"]
+
+	// Remote accounts can't be delegates.
+	_, errWithCode := suite.tooter.AddFeedDelegate(ctx, admin, feedAccount.ID, suite.testAccounts["remote_account_1"].ID)
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+
+	// Nor feed accounts, which can't log in.
+	_, errWithCode = suite.tooter.AddFeedDelegate(ctx, admin, feedAccount.ID, feedAccount.ID)
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+
+	// Only feed accounts have delegates.
+	_, errWithCode = suite.tooter.AddFeedDelegate(ctx, admin, suite.testAccounts["local_account_1"].ID, feedAccount.ID)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+}
+
+func TestDelegatesTestSuite(t *testing.T) {
+	suite.Run(t, new(DelegatesTestSuite))
+}
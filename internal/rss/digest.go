@@ -0,0 +1,183 @@
+package rss
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/uris"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+// digestFrequency is how often feeds are checked for a due digest.
+const digestFrequency = 5 * time.Minute
+
+// digestThreadSize is the maximum number of items listed by a digest
+// status, longer digests are published as a thread.
+const digestThreadSize = 10
+
+// publishDigests publishes the digest of every feed whose period has elapsed.
+func (n *rssTooter) publishDigests(ctx context.Context, now time.Time) {
+	feeds, err := n.GetDigestFeeds(ctx)
+	if err != nil {
+		log.Errorf(ctx, "Failed to retrieve digest feeds: %s", err)
+		return
+	}
+
+	for _, feed := range feeds {
+		if now.Before(feed.DigestedAt.Add(feed.Digest.Period())) {
+			continue
+		}
+
+		if err := n.publishDigest(ctx, feed, now); err != nil {
+			log.Errorf(ctx, "Failed to publish digest of %s: %s", feed.URL, err)
+		}
+	}
+}
+
+// publishDigest publishes a single status (or a short thread) listing
+// the items stored since the previous digest of the feed.
+func (n *rssTooter) publishDigest(ctx context.Context, feed *gtsmodel.RssFeed, now time.Time) error {
+	account, err := n.state.DB.GetAccountByID(ctx, feed.AccountID)
+	if err != nil {
+		return gtserror.Newf("couldn't get feed account: %w", err)
+	}
+
+	statuses, err := n.GetStatusesSince(ctx, feed.AccountID, feed.DigestLastStatusID)
+	if err != nil {
+		return gtserror.Newf("couldn't get digest items: %w", err)
+	}
+	items := feedItems(account, statuses)
+
+	var parent *gtsmodel.Status
+	for start := 0; start < len(items); start += digestThreadSize {
+		end := min(start+digestThreadSize, len(items))
+
+		var header string
+		if start == 0 {
+			header = fmt.Sprintf("<p>%d new items from %s:</p>", len(items), html.EscapeString(account.DisplayName))
+		}
+
+		status, err := n.putDigestStatus(ctx, account, feed, parent, header+digestList(items[start:end]))
+		if err != nil {
+			return err
+		}
+
+		parent = status
+	}
+
+	// The next digest lists the statuses stored after the last one
+	// listed here, including those stored while it was published.
+	feed.DigestedAt = now
+	if len(statuses) > 0 {
+		feed.DigestLastStatusID = statuses[len(statuses)-1].ID
+	}
+
+	return n.UpdateRssFeed(ctx, feed, "digested_at", "digest_last_status_id")
+}
+
+// putDigestStatus stores a digest status, replying to parent if
+// set, and sends it to the client API worker for side effects.
+func (n *rssTooter) putDigestStatus(ctx context.Context, account *gtsmodel.Account, feed *gtsmodel.RssFeed, parent *gtsmodel.Status, content string) (*gtsmodel.Status, error) {
+	accountURIs := uris.GenerateURIsForAccount(account.Username)
+	statusId := id.NewULID()
+	now := time.Now()
+
+	status := &gtsmodel.Status{
+		ID:                  statusId,
+		URI:                 accountURIs.StatusesURI + "/" + statusId,
+		URL:                 accountURIs.StatusesURL + "/" + statusId,
+		Local:               util.Ptr(true),
+		CreatedAt:           now,
+		UpdatedAt:           now,
+		Account:             account,
+		AccountID:           account.ID,
+		AccountURI:          account.URI,
+		ActivityStreamsType: ap.ObjectNote,
+		Content:             content,
+	}
+	resolveSettings(feed).apply(status, nil)
+
+	if parent != nil {
+		status.InReplyToID = parent.ID
+		status.InReplyToURI = parent.URI
+		status.InReplyToAccountID = account.ID
+		status.InReplyTo = parent
+		status.InReplyToAccount = account
+		status.ThreadID = parent.ThreadID
+	} else if errWithCode := n.processThreadID(ctx, status); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if err := n.state.DB.PutStatus(ctx, status); err != nil {
+		return nil, gtserror.Newf("couldn't put digest status: %w", err)
+	}
+
+	n.state.Workers.Client.Queue.Push(&messages.FromClientAPI{
+		APObjectType:   ap.ObjectNote,
+		APActivityType: ap.ActivityCreate,
+		GTSModel:       status,
+		Origin:         account,
+	})
+
+	return status, nil
+}
+
+// feedItems returns the statuses of a feed account created from its feed items.
+// Boosts of duplicate articles, replies (comments are threaded under their item)
+// and the statuses posted on this server, by delegates or digests, are left out:
+// their url is the local one of the status instead of the item link.
+func feedItems(account *gtsmodel.Account, statuses []*gtsmodel.Status) []*gtsmodel.Status {
+	localURL := uris.GenerateURIsForAccount(account.Username).StatusesURL + "/"
+
+	items := make([]*gtsmodel.Status, 0, len(statuses))
+	for _, status := range statuses {
+		if status.BoostOfID != "" || status.InReplyToID != "" || strings.HasPrefix(status.URL, localURL) {
+			continue
+		}
+		items = append(items, status)
+	}
+
+	return items
+}
+
+// digestList renders the titles of the given item statuses as an html list.
+func digestList(items []*gtsmodel.Status) string {
+	var b strings.Builder
+
+	b.WriteString("<ul>")
+	for _, item := range items {
+		title, link := itemTitle(item)
+		fmt.Fprintf(&b, `<li><a href="%s">%s</a></li>`, html.EscapeString(link), html.EscapeString(title))
+	}
+	b.WriteString("</ul>")
+
+	return b.String()
+}
+
+// itemTitle extracts the title and link of an item from
+// the leading link of the status created by createStatus.
+func itemTitle(status *gtsmodel.Status) (string, string) {
+	title, link := "", status.URL
+
+	if doc, err := htmlquery.Parse(strings.NewReader(status.Content)); err == nil {
+		if node := htmlquery.FindOne(doc, "//a"); node != nil {
+			title = htmlquery.InnerText(node)
+		}
+	}
+
+	if title == "" {
+		title = link
+	}
+
+	return title, link
+}
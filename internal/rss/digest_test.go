@@ -0,0 +1,90 @@
+package rss
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/uris"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type DigestTestSuite struct {
+	RssStandardTestSuite
+}
+
+func (suite *DigestTestSuite) TestPublishDigest() {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_1"]
+
+	feed := &gtsmodel.RssFeed{
+		ID:                 id.NewULID(),
+		AccountID:          account.ID,
+		URL:                suite.fixtureURL("rss2.xml"),
+		Digest:             gtsmodel.RssDigestDaily,
+		DigestedAt:         time.Now().Add(-48 * time.Hour),
+		DigestLastStatusID: id.NewULID(),
+	}
+	if err := suite.tooter.PutRssFeed(ctx, feed); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.tooter.poll(ctx)
+	items, err := suite.tooter.GetStatusesSince(ctx, account.ID, feed.DigestLastStatusID)
+	suite.NoError(err)
+	suite.NotEmpty(items)
+	suite.Zero(suite.state.Workers.Client.Queue.Len())
+
+	// Statuses posted by hand on behalf of the feed account are not items.
+	post := testrig.NewTestStatuses()["local_account_1_status_1"]
+	post.ID = id.NewULID()
+	post.URI = uris.GenerateURIsForAccount(account.Username).StatusesURI + "/" + post.ID
+	post.URL = uris.GenerateURIsForAccount(account.Username).StatusesURL + "/" + post.ID
+	if err := suite.state.DB.PutStatus(ctx, post); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// An item stored while the digest is published, after its items
+	// were listed, has an older id than the digest status. Ids of the
+	// same millisecond are random: wait for the one after the post.
+	time.Sleep(time.Millisecond)
+	lateID := id.NewULID()
+	suite.tooter.publishDigests(ctx, time.Now())
+
+	msg, ok := suite.state.Workers.Client.Queue.Pop()
+	if !ok {
+		suite.FailNow("no digest published")
+	}
+	digest := msg.GTSModel.(*gtsmodel.Status)
+	suite.Contains(digest.Content, fmt.Sprintf("<p>%d new items from ", len(items)))
+	suite.Contains(digest.Content, "https://blog.example.org/2024/06/03/tomatoes-again/")
+	suite.NotContains(digest.Content, post.URL)
+
+	// It is listed by the next digest.
+	late := testrig.NewTestStatuses()["local_account_1_status_2"]
+	late.ID = lateID
+	late.URI = uris.GenerateURIsForAccount(account.Username).StatusesURI + "/" + late.ID
+	late.URL = "https://blog.example.org/2024/06/05/late/"
+	late.InReplyToID = ""
+	if err := suite.state.DB.PutStatus(ctx, late); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.tooter.publishDigests(ctx, time.Now().Add(48*time.Hour))
+
+	msg, ok = suite.state.Workers.Client.Queue.Pop()
+	if !ok {
+		suite.FailNow("no digest published")
+	}
+	digest = msg.GTSModel.(*gtsmodel.Status)
+	suite.Contains(digest.Content, "<p>1 new items from ")
+	suite.Contains(digest.Content, late.URL)
+}
+
+func TestDigestTestSuite(t *testing.T) {
+	suite.Run(t, new(DigestTestSuite))
+}
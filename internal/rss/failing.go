@@ -0,0 +1,39 @@
+package rss
+
+import "sync"
+
+// failingFeeds tracks the feeds whose last fetch failed, for
+// the gotosocial.rss.feeds_in_backoff gauge. They are still
+// fetched on each poll cycle.
+type failingFeeds struct {
+	mu    sync.Mutex
+	feeds map[string]struct{}
+}
+
+func newFailingFeeds() *failingFeeds {
+	return &failingFeeds{feeds: make(map[string]struct{})}
+}
+
+// failed records a failed fetch of the feed of accountID.
+func (f *failingFeeds) failed(accountID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.feeds[accountID] = struct{}{}
+}
+
+// succeeded records a successful fetch of the feed of accountID.
+func (f *failingFeeds) succeeded(accountID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.feeds, accountID)
+}
+
+// len returns the number of feeds whose last fetch failed.
+func (f *failingFeeds) len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.feeds)
+}
@@ -27,10 +27,11 @@ type rssFeed struct {
    Doc                  *html.Node
    FeedUrl              *netUrl.URL
    Feed                 *gofeed.Feed
+   Links                map[string]string // RFC 5005 navigation links of the feed document
    DbUsername           string
 }
 
-func NewRssFeed(state *state.State, ctx context.Context, resource string) (string, *rssFeed, error) {
+func NewRssFeed(state *state.State, fetcher Fetcher, ctx context.Context, resource string) (string, *rssFeed, error) {
    hostRg := regexp.MustCompile(fmt.Sprintf("^@|^acct:|@%s?$", config.GetHost())) // GetHost will return "" outside
    cleaned := hostRg.ReplaceAllString(resource, ``)
 
@@ -51,13 +52,13 @@ func NewRssFeed(state *state.State, ctx context.Context, resource string) (strin
    }
 
    var doc *html.Node
-   fp := gofeed.NewParser()
+   var feed *gofeed.Feed
 
    feedUrl := url
    baseUrl := url
-   feed, err := fp.ParseURL(feedUrl.String())
+   httpFeed, err := fetcher.FetchFeed(ctx, feedUrl.String(), "", nil)
    if err != nil {
-      doc, err = htmlquery.LoadURL(url.String())
+      doc, err = fetcher.FetchHTML(ctx, url.String())
       if err != nil {
          return "", nil, fmt.Errorf("Failed to load HTML from %s: %s", url, err)
       }
@@ -71,40 +72,25 @@ func NewRssFeed(state *state.State, ctx context.Context, resource string) (strin
       }
       feedPath := htmlquery.SelectAttr(node, "href")
 
-      feedUrlStr := fmt.Sprintf("https://%s%s", url.Hostname(), feedPath)
-      feedUrl, err = netUrl.Parse(feedUrlStr)
+      feedUrl, err = url.Parse(feedPath)
       if err != nil {
-         return "", nil, fmt.Errorf("Not a valid feed url %s: %s", feedUrlStr, err)
+         return "", nil, fmt.Errorf("Not a valid feed url %s: %s", feedPath, err)
       }
 
-      feed, err = fp.ParseURL(feedUrlStr)
+      httpFeed, err = fetcher.FetchFeed(ctx, feedUrl.String(), "", nil)
       if err != nil {
          return "", nil, fmt.Errorf("Invalid feed at %s: %s", feedUrl, err)
       }
+      feed = httpFeed.Feed
    } else {
-      baseUrlStr := baseRg.ReplaceAllString(feed.Link, ``)
-
-      baseUrl, err = netUrl.Parse(baseUrlStr)
+      feed = httpFeed.Feed
+      baseUrl, doc, err = loadSite(ctx, fetcher, feedUrl, feed)
       if err != nil {
-         return "", nil, fmt.Errorf("Invalid resolved baseUrl %s: %s", baseUrl, err)
-      }
-
-      doc, err = htmlquery.LoadURL(baseUrl.String())
-      if err != nil {
-         return "", nil, fmt.Errorf("Failed to load HTML from %s: %s", url, err)
+         return "", nil, err
       }
    }
 
-   var dbUsername = ""
-   hostName := cleanHostRg.ReplaceAllString(feedUrl.Hostname(), ``)
-   feedPath := cleanPathRg.ReplaceAllString(feedUrl.Path, ``)
-   if len(feedPath) > 20 || len(feedUrl.RawQuery) > 0 {
-      pathQuery := fmt.Sprintf("%s?%s", feedUrl.Path, feedUrl.RawQuery)
-      dbUsername = TolUsernameDB(fmt.Sprintf("%s.%d", hostName, xxhash.Sum64String(pathQuery)))
-   } else {
-      dbUsername = TolUsernameDB( hostName + mastoCharsRg.ReplaceAllString(feedPath, `.`))
-   }
-
+   dbUsername := feedUsername(feedUrl)
    available, err := state.DB.IsUsernameAvailable(ctx, dbUsername)
    if !available {
       return dbUsername, nil, err
@@ -115,12 +101,63 @@ func NewRssFeed(state *state.State, ctx context.Context, resource string) (strin
       Doc:              doc,
       FeedUrl:          feedUrl,
       Feed:             feed,
+      Links:            httpFeed.Links,
       DbUsername:       dbUsername,
    }
 
    return "", &rssFeed, nil
 }
 
+// feedUsername returns the username of the account of the feed at feedUrl.
+func feedUsername(feedUrl *netUrl.URL) string {
+   hostName := cleanHostRg.ReplaceAllString(feedUrl.Hostname(), ``)
+   feedPath := cleanPathRg.ReplaceAllString(feedUrl.Path, ``)
+   if len(feedPath) > 20 || len(feedUrl.RawQuery) > 0 {
+      pathQuery := fmt.Sprintf("%s?%s", feedUrl.Path, feedUrl.RawQuery)
+      return TolUsernameDB(fmt.Sprintf("%s.%d", hostName, xxhash.Sum64String(pathQuery)))
+   }
+   return TolUsernameDB( hostName + mastoCharsRg.ReplaceAllString(feedPath, `.`))
+}
+
+// LoadRssFeed fetches the feed at feedUrl and the website it links
+// to, to read again the metadata of an existing feed account.
+func LoadRssFeed(ctx context.Context, fetcher Fetcher, feedUrl *netUrl.URL) (*rssFeed, error) {
+   httpFeed, err := fetcher.FetchFeed(ctx, feedUrl.String(), "", nil)
+   if err != nil {
+      return nil, fmt.Errorf("Invalid feed at %s: %s", feedUrl, err)
+   }
+
+   baseUrl, doc, err := loadSite(ctx, fetcher, feedUrl, httpFeed.Feed)
+   if err != nil {
+      return nil, err
+   }
+
+   return &rssFeed{
+      BaseUrl:          baseUrl,
+      Doc:              doc,
+      FeedUrl:          feedUrl,
+      Feed:             httpFeed.Feed,
+      Links:            httpFeed.Links,
+   }, nil
+}
+
+// loadSite fetches the html page of the website of feed.
+func loadSite(ctx context.Context, fetcher Fetcher, feedUrl *netUrl.URL, feed *gofeed.Feed) (*netUrl.URL, *html.Node, error) {
+   baseUrlStr := baseRg.ReplaceAllString(feed.Link, ``)
+
+   baseUrl, err := feedUrl.Parse(baseUrlStr)
+   if err != nil {
+      return nil, nil, fmt.Errorf("Invalid resolved baseUrl %s: %s", baseUrlStr, err)
+   }
+
+   doc, err := fetcher.FetchHTML(ctx, baseUrl.String())
+   if err != nil {
+      return nil, nil, fmt.Errorf("Failed to load HTML from %s: %s", baseUrl, err)
+   }
+
+   return baseUrl, doc, nil
+}
+
 func (r *rssFeed) ExtractDescription() string {
    description := r.Feed.Description
 
@@ -134,31 +171,49 @@ func (r *rssFeed) ExtractDescription() string {
    return fmt.Sprintf("%s <br> Proxy account for: <a href='%s'>%s<a>", description, r.FeedUrl, r.FeedUrl)
 }
 
-func (r *rssFeed) ExtractIcon() string {
-   iconUrl := ""
+// MediaType returns the media type of the feed document.
+func (r *rssFeed) MediaType() string {
+   switch r.Feed.FeedType {
+   case "atom":
+      return "application/atom+xml"
+   case "json":
+      return "application/feed+json"
+   case scrapedFeedType:
+      return "text/html"
+   default:
+      return "application/rss+xml"
+   }
+}
 
-   if r.Feed.Image != nil {
-      iconUrl = r.Feed.Image.URL
-   } else {
-      for _, iconNode := range htmlquery.Find(r.Doc, "//head/link[@type='image/png']") {
-         rel := htmlquery.SelectAttr(iconNode, "rel")
-
-         if iconRg.MatchString(rel) {
-            iconPath := htmlquery.SelectAttr(iconNode, "href")
-            if( len(iconPath) > 1 ){
-               if strings.Contains(iconPath, "http") {
-                  iconUrl = iconPath
-               } else {
-                  iconUrl = fmt.Sprintf("https://%s%s", r.BaseUrl.Hostname(), iconPath)
-               }
-               break
+// ExtractHeader returns the banner image of the feed (og:image of
+// its website, or banner_image feed element), empty if there is none.
+func (r *rssFeed) ExtractHeader() string {
+   headerPath := ""
+
+   if node := htmlquery.FindOne(r.Doc, "//head/meta[@property='og:image']"); node != nil {
+      headerPath = htmlquery.SelectAttr(node, "content")
+   }
+
+   if len(headerPath) == 0 {
+      for _, exts := range r.Feed.Extensions {
+         for _, ext := range exts["banner_image"] {
+            headerPath = ext.Value
+            if len(headerPath) == 0 {
+               headerPath = ext.Attrs["url"]
             }
+            break
          }
       }
-      if len(iconUrl) == 0 {
-         iconUrl = fmt.Sprintf("http://%s/assets/logo.png", config.GetHost())
-      }
    }
 
-   return iconUrl
+   if len(headerPath) == 0 {
+      return ""
+   }
+
+   resolved, err := r.BaseUrl.Parse(headerPath)
+   if err != nil {
+      return ""
+   }
+
+   return resolved.String()
 }
@@ -0,0 +1,52 @@
+package rss
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type FeedTestSuite struct {
+	RssStandardTestSuite
+}
+
+func (suite *FeedTestSuite) TestNewRssFeedFromPage() {
+	existing, feed, err := NewRssFeed(&suite.state, suite.tooter.fetcher, context.Background(), suite.fixtureURL("page.html"))
+	suite.NoError(err)
+	suite.Empty(existing)
+
+	// The feed is discovered from the relative
+	// alternate link of the page.
+	suite.Equal(suite.fixtureURL("rss2.xml"), feed.FeedUrl.String())
+	suite.Equal("127.0.0.1.rss2", feed.DbUsername)
+	suite.Equal("A Small Blog", feed.Feed.Title)
+	suite.Equal("Notes about gardening and bikes <br> Proxy account for: <a href='"+suite.fixtureURL("rss2.xml")+"'>"+suite.fixtureURL("rss2.xml")+"<a>", feed.ExtractDescription())
+}
+
+func (suite *FeedTestSuite) TestNewRssFeedFromFeed() {
+	existing, feed, err := NewRssFeed(&suite.state, suite.tooter.fetcher, context.Background(), suite.fixtureURL("rss2.xml"))
+	suite.NoError(err)
+	suite.Empty(existing)
+
+	// The page is found from the link of the feed.
+	suite.Equal(suite.fixtureURL("rss2.xml"), feed.FeedUrl.String())
+	suite.Equal(suite.fixtureURL(""), feed.BaseUrl.String())
+	suite.Equal(suite.fixtureURL("banner.png"), feed.ExtractHeader())
+}
+
+func (suite *FeedTestSuite) TestNewRssFeedExisting() {
+	existing, feed, err := NewRssFeed(&suite.state, suite.tooter.fetcher, context.Background(), "@the_mighty_zork")
+	suite.NoError(err)
+	suite.Nil(feed)
+	suite.Equal("the_mighty_zork", existing)
+}
+
+func (suite *FeedTestSuite) TestNewRssFeedNoFeed() {
+	_, _, err := NewRssFeed(&suite.state, suite.tooter.fetcher, context.Background(), suite.fixtureURL("missing.html"))
+	suite.ErrorContains(err, "Failed to load HTML from")
+}
+
+func TestFeedTestSuite(t *testing.T) {
+	suite.Run(t, new(FeedTestSuite))
+}
@@ -0,0 +1,328 @@
+package rss
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	netUrl "net/url"
+	"time"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/mmcdole/gofeed"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// HTTPClient performs the HTTP requests of the rss package:
+// the *httpclient.Client of the instance outside of tests.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Fetcher fetches the remote documents read by the rss
+// package: feeds, and the html pages they're found from.
+type Fetcher interface {
+	// FetchFeed fetches and parses the feed document at url. The
+	// request is conditional when etag or lastModified are set, the
+	// returned HTTPFeed has no Feed if the document wasn't modified.
+	FetchFeed(ctx context.Context, url string, etag string, lastModified *time.Time) (*HTTPFeed, error)
+
+	// FetchHTML fetches and parses the html page at url.
+	FetchHTML(ctx context.Context, url string) (*html.Node, error)
+
+	// FetchData fetches the document at url, failing
+	// if it's larger than maxSize bytes.
+	FetchData(ctx context.Context, url string, maxSize int64) ([]byte, error)
+}
+
+// HTTPFeed is a fetched feed document with its cache headers.
+type HTTPFeed struct {
+	StatusCode   int
+	Feed         *gofeed.Feed
+	Etag         string
+	LastModified *time.Time
+	Links        map[string]string // RFC 5005 navigation links, indexed by rel
+	Data         []byte            // the feed document, nil if not modified
+}
+
+// StatusError is returned by a Fetcher when the
+// server responds with an unexpected status code.
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("Invalid returned HTTPCode: %d - %s", e.StatusCode, e.Status)
+}
+
+// ParseError is returned by a Fetcher when the
+// fetched document is not a valid feed.
+type ParseError struct {
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return "Failed to parse feed: " + e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// NewFetcher returns a Fetcher performing its requests with client.
+func NewFetcher(client HTTPClient) Fetcher {
+	return &httpFetcher{
+		client: client,
+		parser: gofeed.NewParser(),
+	}
+}
+
+type httpFetcher struct {
+	client HTTPClient
+	parser *gofeed.Parser
+}
+
+func (f *httpFetcher) FetchFeed(ctx context.Context, feedUrl string, etag string, lastModified *time.Time) (_ *HTTPFeed, err error) {
+	ctx, span := startSpan(ctx, "rss.FetchFeed", attrFeedURL.String(feedUrl))
+	defer func() { endSpan(span, err) }()
+
+	location := time.FixedZone("GMT", 0)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", f.parser.UserAgent)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	if lastModified != nil && !lastModified.IsZero() {
+		req.Header.Set("If-Modified-Since", lastModified.In(location).Format(time.RFC1123))
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	span.SetAttributes(attrStatusCode.Int(resp.StatusCode))
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent && resp.StatusCode != http.StatusNotModified {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	httpFeed := HTTPFeed{
+		StatusCode:   resp.StatusCode,
+		Etag:         resp.Header.Get("Etag"),
+		LastModified: lastModified,
+	}
+
+	if lastModified := resp.Header.Get("Last-Modified"); lastModified != "" {
+		parsed, err := time.ParseInLocation(time.RFC1123, lastModified, location)
+		if err == nil {
+			httpFeed.LastModified = &parsed
+		}
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		span.SetAttributes(attrCacheOutcome.String("hit"))
+		return &httpFeed, nil
+	}
+	span.SetAttributes(attrCacheOutcome.String("miss"))
+
+	reader, err := decompress(resp)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attrBytes.Int(len(data)))
+
+	feed, err := f.parse(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve against the final url, after redirects.
+	base := req.URL
+	if resp.Request != nil {
+		base = resp.Request.URL
+	}
+
+	resolveLinks(base, feed)
+	httpFeed.Feed = feed
+	httpFeed.Links = parseFeedLinks(data)
+	httpFeed.Data = data
+
+	return &httpFeed, nil
+}
+
+// parse parses the feed document data.
+func (f *httpFetcher) parse(ctx context.Context, data []byte) (_ *gofeed.Feed, err error) {
+	_, span := startSpan(ctx, "rss.ParseFeed", attrBytes.Int(len(data)))
+	defer func() { endSpan(span, err) }()
+
+	feed, err := f.parser.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, &ParseError{Err: err}
+	}
+
+	span.SetAttributes(attrItemCount.Int(len(feed.Items)))
+	return feed, nil
+}
+
+func (f *httpFetcher) FetchHTML(ctx context.Context, pageUrl string) (_ *html.Node, err error) {
+	ctx, span := startSpan(ctx, "rss.FetchHTML", attrFeedURL.String(pageUrl))
+	defer func() { endSpan(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", f.parser.UserAgent)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	span.SetAttributes(attrStatusCode.Int(resp.StatusCode))
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	reader, err := decompress(resp)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	utf8Reader, err := charset.NewReader(reader, resp.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+
+	return htmlquery.Parse(utf8Reader)
+}
+
+func (f *httpFetcher) FetchData(ctx context.Context, dataUrl string, maxSize int64) (_ []byte, err error) {
+	ctx, span := startSpan(ctx, "rss.FetchData", attrFeedURL.String(dataUrl))
+	defer func() { endSpan(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, dataUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", f.parser.UserAgent)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	span.SetAttributes(attrStatusCode.Int(resp.StatusCode))
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	reader, err := decompress(resp)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("Document larger than %d bytes", maxSize)
+	}
+	span.SetAttributes(attrBytes.Int(len(data)))
+
+	return data, nil
+}
+
+// decompress returns a reader of the decoded body of resp, as
+// requests set Accept-Encoding themselves the transport won't.
+func decompress(resp *http.Response) (io.ReadCloser, error) {
+	if resp.Uncompressed {
+		return resp.Body, nil
+	}
+
+	switch ce := resp.Header.Get("Content-Encoding"); ce {
+	case "gzip":
+		reader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to initialize gzip reader: %w", err)
+		}
+		return reader, nil
+	case "deflate":
+		return flate.NewReader(resp.Body), nil
+	case "":
+		return resp.Body, nil
+	default:
+		return nil, fmt.Errorf("Unknow Content-Encoding: %s", ce)
+	}
+}
+
+// resolveLinks resolves the relative links of a feed and
+// its items against the url it was fetched from.
+func resolveLinks(feedUrl *netUrl.URL, feed *gofeed.Feed) {
+	resolve := func(link string) string {
+		if link == "" {
+			return link
+		}
+		if resolved, err := feedUrl.Parse(link); err == nil {
+			return resolved.String()
+		}
+		return link
+	}
+
+	feed.Link = resolve(feed.Link)
+	if feed.Image != nil {
+		feed.Image.URL = resolve(feed.Image.URL)
+	}
+
+	for _, item := range feed.Items {
+		item.Link = resolve(item.Link)
+		for i, link := range item.Links {
+			item.Links[i] = resolve(link)
+		}
+	}
+}
+
+// itemPage is the html page of a feed item, fetched on first use: the
+// byline, the preview card and the deduplication of an item all read it.
+type itemPage struct {
+	link    string
+	fetched bool
+	doc     *html.Node
+	err     error
+}
+
+// load returns the parsed page, fetching it the first time only.
+func (p *itemPage) load(ctx context.Context, fetcher Fetcher) (*html.Node, error) {
+	if !p.fetched {
+		p.doc, p.err = fetcher.FetchHTML(ctx, p.link)
+		p.fetched = true
+	}
+	return p.doc, p.err
+}
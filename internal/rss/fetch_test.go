@@ -0,0 +1,111 @@
+package rss
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/stretchr/testify/suite"
+)
+
+type FetchTestSuite struct {
+	RssStandardTestSuite
+}
+
+func (suite *FetchTestSuite) TestFetchRSS() {
+	feed, err := suite.tooter.fetcher.FetchFeed(context.Background(), suite.fixtureURL("rss2.xml"), "", nil)
+	suite.NoError(err)
+
+	suite.Equal("rss", feed.Feed.FeedType)
+	suite.Equal(`"rss2.xml-v1"`, feed.Etag)
+	suite.True(fixtureModTime.Equal(*feed.LastModified))
+	suite.Equal("/rss2-page2.xml", feed.Links["next"])
+	suite.Len(feed.Feed.Items, 3)
+
+	// Relative links are resolved against the feed url.
+	suite.Equal(suite.fixtureURL(""), feed.Feed.Link)
+	suite.Equal(suite.fixtureURL("2024/05/28/relative/"), feed.Feed.Items[1].Link)
+
+	// Items without guid are identified by their link.
+	suite.Equal(suite.fixtureURL("2024/05/28/relative/"), itemKey(feed.Feed.Items[1]))
+
+	// Items with an unparseable date are left out.
+	items := datedItems(feed.Feed.Items)
+	suite.Len(items, 2)
+	suite.Equal("Tomatoes, again", items[0].Title)
+	suite.Equal("A relative link and no guid", items[1].Title)
+}
+
+func (suite *FetchTestSuite) TestFetchAtom() {
+	feed, err := suite.tooter.fetcher.FetchFeed(context.Background(), suite.fixtureURL("atom.xml"), "", nil)
+	suite.NoError(err)
+
+	suite.Equal("atom", feed.Feed.FeedType)
+	suite.Equal("https://code.example.org/releases.atom?page=2", feed.Links["prev-archive"])
+
+	// Entries with only an updated date use it as publication date.
+	items := datedItems(feed.Feed.Items)
+	suite.Len(items, 2)
+	suite.Equal("2024-05-02T06:00:00Z", items[1].PublishedParsed.UTC().Format("2006-01-02T15:04:05Z"))
+	suite.Equal(items[1].Link, itemKey(items[1]))
+}
+
+func (suite *FetchTestSuite) TestFetchRDF() {
+	feed, err := suite.tooter.fetcher.FetchFeed(context.Background(), suite.fixtureURL("rdf.xml"), "", nil)
+	suite.NoError(err)
+
+	suite.Equal("rss", feed.Feed.FeedType)
+	suite.Equal("1.0", feed.Feed.FeedVersion)
+	suite.Len(datedItems(feed.Feed.Items), 2)
+}
+
+func (suite *FetchTestSuite) TestFetchJSON() {
+	feed, err := suite.tooter.fetcher.FetchFeed(context.Background(), suite.fixtureURL("feed.json"), "", nil)
+	suite.NoError(err)
+
+	suite.Equal("json", feed.Feed.FeedType)
+	items := datedItems(feed.Feed.Items)
+	suite.Len(items, 1)
+	suite.Equal("Episode 42", items[0].Title)
+}
+
+func (suite *FetchTestSuite) TestFetchGzip() {
+	feed, err := suite.tooter.fetcher.FetchFeed(context.Background(), suite.fixtureURL("gzip/rss2.xml"), "", nil)
+	suite.NoError(err)
+	suite.Equal("A Small Blog", feed.Feed.Title)
+	suite.Len(feed.Feed.Items, 3)
+}
+
+func (suite *FetchTestSuite) TestFetchNotModified() {
+	ctx := context.Background()
+
+	feed, err := suite.tooter.fetcher.FetchFeed(ctx, suite.fixtureURL("rss2.xml"), `"rss2.xml-v1"`, nil)
+	suite.NoError(err)
+	suite.Nil(feed.Feed)
+	suite.Equal(`"rss2.xml-v1"`, feed.Etag)
+
+	feed, err = suite.tooter.fetcher.FetchFeed(ctx, suite.fixtureURL("rss2.xml"), "", &fixtureModTime)
+	suite.NoError(err)
+	suite.Nil(feed.Feed)
+}
+
+func (suite *FetchTestSuite) TestFetchErrors() {
+	ctx := context.Background()
+
+	_, err := suite.tooter.fetcher.FetchFeed(ctx, suite.fixtureURL("missing.xml"), "", nil)
+	suite.EqualError(err, "Invalid returned HTTPCode: 404 - 404 Not Found")
+
+	// Html pages are not feeds.
+	_, err = suite.tooter.fetcher.FetchFeed(ctx, suite.fixtureURL("page.html"), "", nil)
+	suite.Error(err)
+}
+
+func (suite *FetchTestSuite) TestFetchHTML() {
+	doc, err := suite.tooter.fetcher.FetchHTML(context.Background(), suite.fixtureURL(""))
+	suite.NoError(err)
+	suite.Equal("A Small Blog", htmlquery.InnerText(htmlquery.FindOne(doc, "//title")))
+}
+
+func TestFetchTestSuite(t *testing.T) {
+	suite.Run(t, new(FetchTestSuite))
+}
@@ -0,0 +1,278 @@
+package rss
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	_ "golang.org/x/image/webp"
+)
+
+// maxIconSize is the maximum size of a fetched icon or manifest.
+const maxIconSize = 2 << 20
+
+// maxIconCandidates is the maximum number of icons
+// fetched to find the best avatar of a feed account.
+const maxIconCandidates = 8
+
+// Icon is the avatar of a feed account: png, jpeg, gif, webp
+// or ico data, the media pipeline converting icos to png.
+type Icon struct {
+	URL    string
+	Data   []byte
+	Width  int
+	Height int
+}
+
+// iconCandidate is an image advertised by a feed or its website,
+// with its declared size if any (0 for unknown or scalable).
+type iconCandidate struct {
+	URL  string
+	Size int
+}
+
+// webManifest is the part of a web app manifest listing its icons.
+type webManifest struct {
+	Icons []struct {
+		Src   string `json:"src"`
+		Sizes string `json:"sizes"`
+		Type  string `json:"type"`
+	} `json:"icons"`
+}
+
+// ResolveIcon fetches the icons advertised by the feed and its
+// website (link elements, web app manifest, og:image, favicon.ico)
+// and returns the largest square one, the instance logo if none
+// could be decoded, or nil.
+func (r *rssFeed) ResolveIcon(ctx context.Context, fetcher Fetcher) *Icon {
+	candidates := r.iconCandidates(ctx, fetcher)
+	if len(candidates) > maxIconCandidates {
+		candidates = candidates[:maxIconCandidates]
+	}
+
+	var best *Icon
+	for _, candidate := range candidates {
+		icon, err := fetchIcon(ctx, fetcher, candidate.URL)
+		if err != nil {
+			log.Debugf(ctx, "Skipping icon %s: %s", candidate.URL, err)
+			continue
+		}
+		if best == nil || betterIcon(icon, best) {
+			best = icon
+		}
+	}
+
+	if best == nil {
+		logo := fmt.Sprintf("%s://%s/assets/logo.png", config.GetProtocol(), config.GetHost())
+		if icon, err := fetchIcon(ctx, fetcher, logo); err == nil {
+			best = icon
+		}
+	}
+
+	return best
+}
+
+// iconCandidates returns the icons advertised by the feed and its
+// website, the ones declaring the largest sizes first.
+func (r *rssFeed) iconCandidates(ctx context.Context, fetcher Fetcher) []iconCandidate {
+	var candidates []iconCandidate
+	seen := make(map[string]bool)
+
+	add := func(href string, size int) {
+		if href == "" {
+			return
+		}
+		resolved, err := r.BaseUrl.Parse(href)
+		if err != nil || seen[resolved.String()] {
+			return
+		}
+		seen[resolved.String()] = true
+		candidates = append(candidates, iconCandidate{URL: resolved.String(), Size: size})
+	}
+
+	if r.Feed.Image != nil {
+		add(r.Feed.Image.URL, 0)
+	}
+
+	if r.Doc != nil {
+		for _, node := range htmlquery.Find(r.Doc, "//head/link[@rel]") {
+			rel := strings.ToLower(htmlquery.SelectAttr(node, "rel"))
+			href := htmlquery.SelectAttr(node, "href")
+
+			switch {
+			case rel == "manifest":
+				for _, icon := range r.manifestIcons(ctx, fetcher, href) {
+					add(icon.URL, icon.Size)
+				}
+			case strings.Contains(rel, "apple-touch-icon"):
+				// Apple touch icons are 180x180 unless told otherwise.
+				size := largestSize(htmlquery.SelectAttr(node, "sizes"))
+				if size == 0 {
+					size = 180
+				}
+				add(href, size)
+			case strings.Contains(rel, "mask-icon"):
+				// Monochrome svg, not meant as an avatar.
+			case iconRg.MatchString(rel):
+				add(href, largestSize(htmlquery.SelectAttr(node, "sizes")))
+			}
+		}
+
+		if node := htmlquery.FindOne(r.Doc, "//head/meta[@property='og:image']"); node != nil {
+			add(htmlquery.SelectAttr(node, "content"), 0)
+		}
+	}
+
+	add("/favicon.ico", 0)
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Size > candidates[j].Size
+	})
+
+	return candidates
+}
+
+// manifestIcons returns the icons listed by the web app manifest at href.
+func (r *rssFeed) manifestIcons(ctx context.Context, fetcher Fetcher, href string) []iconCandidate {
+	manifestUrl, err := r.BaseUrl.Parse(href)
+	if err != nil {
+		return nil
+	}
+
+	data, err := fetcher.FetchData(ctx, manifestUrl.String(), maxIconSize)
+	if err != nil {
+		log.Debugf(ctx, "Failed to fetch manifest %s: %s", manifestUrl, err)
+		return nil
+	}
+
+	var manifest webManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		log.Debugf(ctx, "Invalid manifest %s: %s", manifestUrl, err)
+		return nil
+	}
+
+	icons := make([]iconCandidate, 0, len(manifest.Icons))
+	for _, icon := range manifest.Icons {
+		// Icons are relative to the manifest, not the page.
+		if resolved, err := manifestUrl.Parse(icon.Src); err == nil {
+			icons = append(icons, iconCandidate{URL: resolved.String(), Size: largestSize(icon.Sizes)})
+		}
+	}
+
+	return icons
+}
+
+// largestSize returns the width of the largest size of
+// a sizes attribute ("16x16 32x32"), 0 if there is none.
+func largestSize(sizes string) int {
+	largest := 0
+	for _, size := range strings.Fields(strings.ToLower(sizes)) {
+		width, _, ok := strings.Cut(size, "x")
+		if !ok {
+			continue
+		}
+		if w, err := strconv.Atoi(width); err == nil && w > largest {
+			largest = w
+		}
+	}
+	return largest
+}
+
+// betterIcon returns whether icon makes a better avatar than
+// other: square icons first, then the largest ones.
+func betterIcon(icon *Icon, other *Icon) bool {
+	square, otherSquare := icon.Width == icon.Height, other.Width == other.Height
+	if square != otherSquare {
+		return square
+	}
+	return min(icon.Width, icon.Height) > min(other.Width, other.Height)
+}
+
+// fetchIcon fetches and decodes the icon at url.
+func fetchIcon(ctx context.Context, fetcher Fetcher, url string) (*Icon, error) {
+	data, err := fetcher.FetchData(ctx, url, maxIconSize)
+	if err != nil {
+		return nil, err
+	}
+
+	data, width, height, err := decodeIcon(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Icon{URL: url, Data: data, Width: width, Height: height}, nil
+}
+
+// decodeIcon returns the dimensions of the image data. The media
+// pipeline converts ico images to png when loading them as avatar.
+func decodeIcon(data []byte) ([]byte, int, int, error) {
+	switch contentType := http.DetectContentType(data); contentType {
+	case "image/png", "image/jpeg", "image/gif", "image/webp":
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return data, cfg.Width, cfg.Height, nil
+
+	case "image/x-icon":
+		width, height, err := icoSize(data)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return data, width, height, nil
+
+	default:
+		// Including svg, which can't be rasterized.
+		return nil, 0, 0, fmt.Errorf("Unsupported icon type %s", contentType)
+	}
+}
+
+// icoSize returns the dimensions of the largest image of an ico file.
+func icoSize(data []byte) (int, int, error) {
+	if len(data) < 6 || binary.LittleEndian.Uint16(data[2:4]) != 1 {
+		return 0, 0, errors.New("Invalid ico header")
+	}
+
+	count := int(binary.LittleEndian.Uint16(data[4:6]))
+	if len(data) < 6+16*count {
+		return 0, 0, errors.New("Truncated ico directory")
+	}
+
+	width, height := 0, 0
+	for i := 0; i < count; i++ {
+		dir := data[6+16*i : 6+16*(i+1)]
+
+		// A dimension of 0 means 256 pixels.
+		w, h := int(dir[0]), int(dir[1])
+		if w == 0 {
+			w = 256
+		}
+		if h == 0 {
+			h = 256
+		}
+
+		if w > width {
+			width, height = w, h
+		}
+	}
+
+	if width == 0 {
+		return 0, 0, errors.New("No image in ico")
+	}
+
+	return width, height, nil
+}
@@ -0,0 +1,84 @@
+package rss
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/html"
+)
+
+type IconTestSuite struct {
+	RssStandardTestSuite
+}
+
+func (suite *IconTestSuite) TestResolveIcon() {
+	ctx := context.Background()
+
+	_, feed, err := NewRssFeed(&suite.state, suite.tooter.fetcher, ctx, suite.fixtureURL("rss2.xml"))
+	suite.NoError(err)
+
+	// The 192x192 icon of the manifest beats the apple touch
+	// icon, the favicons and the larger but wide og:image.
+	icon := feed.ResolveIcon(ctx, suite.tooter.fetcher)
+	suite.NotNil(icon)
+	suite.Equal(suite.fixtureURL("icon-192.png"), icon.URL)
+	suite.Equal(192, icon.Width)
+	suite.Equal(192, icon.Height)
+}
+
+func (suite *IconTestSuite) TestResolveIconFavicon() {
+	ctx := context.Background()
+
+	_, feed, err := NewRssFeed(&suite.state, suite.tooter.fetcher, ctx, suite.fixtureURL("rss2.xml"))
+	suite.NoError(err)
+
+	// Without any advertised icon, /favicon.ico is
+	// tried, and converted to png by the media pipeline.
+	feed.Doc = &html.Node{Type: html.DocumentNode}
+	feed.Feed = &gofeed.Feed{}
+
+	icon := feed.ResolveIcon(ctx, suite.tooter.fetcher)
+	suite.NotNil(icon)
+	suite.Equal(suite.fixtureURL("favicon.ico"), icon.URL)
+	suite.Equal(48, icon.Width)
+	suite.Equal("image/x-icon", http.DetectContentType(icon.Data))
+
+	account := suite.testAccounts["local_account_1"]
+	suite.NoError(suite.tooter.loadAvatar(ctx, account, icon))
+	suite.Equal("image/png", account.AvatarMediaAttachment.File.ContentType)
+	suite.Equal(48, account.AvatarMediaAttachment.FileMeta.Original.Width)
+}
+
+func (suite *IconTestSuite) TestDecodeIconICO() {
+	data, err := os.ReadFile("testdata/favicon.ico")
+	suite.NoError(err)
+
+	// Icos are kept as is, with the size of their largest entry.
+	decoded, width, height, err := decodeIcon(data)
+	suite.NoError(err)
+	suite.Equal(data, decoded)
+	suite.Equal(48, width)
+	suite.Equal(48, height)
+}
+
+func (suite *IconTestSuite) TestDecodeIconSVG() {
+	data, err := os.ReadFile("testdata/icon.svg")
+	suite.NoError(err)
+
+	_, _, _, err = decodeIcon(data)
+	suite.ErrorContains(err, "Unsupported icon type")
+}
+
+func (suite *IconTestSuite) TestLargestSize() {
+	suite.Equal(64, largestSize("16x16 64x64 32x32"))
+	suite.Equal(0, largestSize("any"))
+	suite.Equal(0, largestSize(""))
+}
+
+func TestIconTestSuite(t *testing.T) {
+	suite.Run(t, new(IconTestSuite))
+}
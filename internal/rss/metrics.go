@@ -0,0 +1,199 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+)
+
+// Results of the poll of a feed, set as the
+// result attribute of gotosocial.rss.feeds_polled.
+const (
+	pollResultFetched     = "fetched"
+	pollResultNotModified = "not_modified"
+	pollResultError       = "error"
+)
+
+// pollerMetrics are the instruments of the feed poller, exported
+// through the meter provider set up by metrics.Initialize.
+type pollerMetrics struct {
+	feedsPolled    metric.Int64Counter
+	fetchDuration  metric.Float64Histogram
+	fetchResponses metric.Int64Counter
+	itemsIngested  metric.Int64Counter
+	parseErrors    metric.Int64Counter
+	pollDuration   metric.Float64Histogram
+
+	// cacheHitRatio is the ratio of not modified
+	// responses of the last poll cycle, in float64 bits.
+	cacheHitRatio atomic.Uint64
+}
+
+// newPollerMetrics creates the instruments of the poller from the
+// global meter provider, which is a no-op when metrics are disabled.
+func newPollerMetrics(failing *failingFeeds) *pollerMetrics {
+	m, err := initPollerMetrics(otel.GetMeterProvider().Meter("GoToSocial"), failing)
+	if err != nil {
+		log.Errorf(nil, "Failed to create feed poller metrics: %s", err)
+		m, _ = initPollerMetrics(noop.NewMeterProvider().Meter("GoToSocial"), failing)
+	}
+	return m
+}
+
+func initPollerMetrics(meter metric.Meter, failing *failingFeeds) (*pollerMetrics, error) {
+	var err error
+	m := &pollerMetrics{}
+
+	m.feedsPolled, err = meter.Int64Counter(
+		"gotosocial.rss.feeds_polled",
+		metric.WithDescription("Number of feeds polled, by result"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.fetchDuration, err = meter.Float64Histogram(
+		"gotosocial.rss.fetch_duration",
+		metric.WithDescription("Duration of the fetches of feeds, by host"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.fetchResponses, err = meter.Int64Counter(
+		"gotosocial.rss.fetch_responses",
+		metric.WithDescription("Number of HTTP responses to fetches of feeds, by host and status code"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.itemsIngested, err = meter.Int64Counter(
+		"gotosocial.rss.items_ingested",
+		metric.WithDescription("Number of feed items turned into statuses by the poller"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.parseErrors, err = meter.Int64Counter(
+		"gotosocial.rss.parse_errors",
+		metric.WithDescription("Number of fetched feed documents which failed to parse, by host"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.pollDuration, err = meter.Float64Histogram(
+		"gotosocial.rss.poll_duration",
+		metric.WithDescription("Duration of a whole poll cycle over all feeds"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(1, 5, 15, 30, 60, 120, 300, 600, 1800),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = meter.Int64ObservableGauge(
+		"gotosocial.rss.feeds_in_backoff",
+		metric.WithDescription("Number of feeds whose last fetch failed"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(int64(failing.len()))
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = meter.Float64ObservableGauge(
+		"gotosocial.rss.cache_hit_ratio",
+		metric.WithDescription("Ratio of feeds not modified in the last poll cycle, among those fetched successfully"),
+		metric.WithFloat64Callback(func(_ context.Context, o metric.Float64Observer) error {
+			o.Observe(m.loadCacheHitRatio())
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// recordFetch records the outcome of the fetch of feedUrl, which
+// took duration and returned feed or err, and returns its result.
+func (m *pollerMetrics) recordFetch(ctx context.Context, feedUrl string, duration time.Duration, feed *HTTPFeed, err error) string {
+	host := attribute.String("host", feedHost(feedUrl))
+	m.fetchDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(host))
+
+	result := pollResultFetched
+	statusCode := 0
+
+	var statusErr *StatusError
+	var parseErr *ParseError
+	switch {
+	case err == nil:
+		statusCode = feed.StatusCode
+		if feed.StatusCode == http.StatusNotModified {
+			result = pollResultNotModified
+		}
+	case errors.As(err, &statusErr):
+		statusCode = statusErr.StatusCode
+		result = pollResultError
+	case errors.As(err, &parseErr):
+		statusCode = http.StatusOK
+		result = pollResultError
+		m.parseErrors.Add(ctx, 1, metric.WithAttributes(host))
+	default:
+		result = pollResultError
+	}
+
+	if statusCode != 0 {
+		m.fetchResponses.Add(ctx, 1, metric.WithAttributes(host, attribute.String("status_code", strconv.Itoa(statusCode))))
+	}
+	m.feedsPolled.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+
+	return result
+}
+
+// recordCycle records the duration of a poll cycle and
+// the ratio of not modified feeds among fetched ones.
+func (m *pollerMetrics) recordCycle(ctx context.Context, duration time.Duration, fetched int, notModified int) {
+	m.pollDuration.Record(ctx, duration.Seconds())
+	if total := fetched + notModified; total > 0 {
+		m.storeCacheHitRatio(float64(notModified) / float64(total))
+	}
+}
+
+func (m *pollerMetrics) storeCacheHitRatio(ratio float64) {
+	m.cacheHitRatio.Store(math.Float64bits(ratio))
+}
+
+func (m *pollerMetrics) loadCacheHitRatio() float64 {
+	return math.Float64frombits(m.cacheHitRatio.Load())
+}
+
+// feedHost returns the host of feedUrl, to label metrics
+// without creating a time series per feed.
+func feedHost(feedUrl string) string {
+	parsed, err := url.Parse(feedUrl)
+	if err != nil || parsed.Host == "" {
+		return "unknown"
+	}
+	return parsed.Hostname()
+}
@@ -0,0 +1,561 @@
+package rss
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/mmcdole/gofeed"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+	"github.com/superseriousbusiness/gotosocial/internal/uris"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+	"github.com/superseriousbusiness/gotosocial/internal/validate"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// newsletterFrequency is how often the newsletter maildir is checked.
+const newsletterFrequency = time.Minute
+
+// maxNewsletterSize is the maximum size of a newsletter message, in bytes.
+const maxNewsletterSize = 32 << 20
+
+// maxNewsletterDepth is the maximum nesting of the multipart bodies of a newsletter message.
+const maxNewsletterDepth = 8
+
+// recipientHeaders are the headers of a message naming its recipients,
+// the ones set by the delivering mail server first.
+var recipientHeaders = []string{"Delivered-To", "X-Original-To", "Envelope-To", "To", "Cc"}
+
+// confirmRg matches the subject and links of subscription confirmation messages.
+var confirmRg = regexp.MustCompile(`(?i)\b(confirm|verify|activate)`)
+
+// headerDecoder decodes the RFC 2047 encoded words of message headers.
+var headerDecoder = &mime.WordDecoder{CharsetReader: charset.NewReaderLabel}
+
+// newsletterMessage is a newsletter message parsed from its MIME representation.
+type newsletterMessage struct {
+	Recipients     []string
+	Subject        string
+	Date           time.Time
+	HTML           string
+	Text           string
+	Images         []*newsletterImage
+	UnsubscribeURL string
+}
+
+// newsletterImage is an image part of a newsletter message.
+type newsletterImage struct {
+	ContentID string
+	Data      []byte
+}
+
+// CreateNewsletter creates the account of a newsletter with its own email address, if requester is an admin.
+func (n *rssTooter) CreateNewsletter(ctx context.Context, requester *gtsmodel.User, form *apimodel.RssNewsletterCreateRequest) (*apimodel.RssNewsletter, gtserror.WithCode) {
+	if !*requester.Admin {
+		err := fmt.Errorf("user %s not allowed to create newsletter accounts", requester.ID)
+		return nil, gtserror.NewErrorForbidden(err, err.Error())
+	}
+
+	if err := validate.Username(form.Username); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if _, err := n.state.DB.GetAccountByUsernameDomain(ctx, form.Username, ""); err == nil {
+		err := fmt.Errorf("username %s is already in use", form.Username)
+		return nil, gtserror.NewErrorConflict(err, err.Error())
+	} else if !errors.Is(err, db.ErrNoEntries) {
+		err := gtserror.Newf("db error getting account: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	secret := make([]byte, 5)
+	if _, err := rand.Read(secret); err != nil {
+		err := gtserror.Newf("error generating newsletter address: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	domain := config.GetRssNewsletterDomain()
+	if domain == "" {
+		domain = config.GetHost()
+	}
+	if host, _, err := net.SplitHostPort(domain); err == nil {
+		domain = host
+	}
+
+	displayName := form.DisplayName
+	if displayName == "" {
+		displayName = form.Username
+	}
+
+	account, err := newProxyAccount(form.Username, displayName, html.EscapeString(form.Note), uris.GenerateURIsForAccount(form.Username).UserURL)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if err := n.putProxyAccount(ctx, account); err != nil {
+		err := gtserror.Newf("db error putting newsletter account: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// The random part keeps the address from being guessed from the username.
+	newsletter := &gtsmodel.RssNewsletter{
+		ID:        id.NewULID(),
+		AccountID: account.ID,
+		Address:   strings.ToLower(form.Username) + "." + feedTokenEncoding.EncodeToString(secret) + "@" + strings.ToLower(domain),
+	}
+
+	if err := n.PutRssNewsletter(ctx, newsletter); err != nil {
+		err := gtserror.Newf("db error putting newsletter: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return n.apiNewsletter(ctx, newsletter)
+}
+
+// GetNewsletters returns the newsletters, with their unsubscribe and confirmation links, if requester is an admin.
+func (n *rssTooter) GetNewsletters(ctx context.Context, requester *gtsmodel.User) ([]*apimodel.RssNewsletter, gtserror.WithCode) {
+	if !*requester.Admin {
+		err := fmt.Errorf("user %s not allowed to list newsletter accounts", requester.ID)
+		return nil, gtserror.NewErrorForbidden(err, err.Error())
+	}
+
+	newsletters, err := n.GetRssNewsletters(ctx)
+	if err != nil {
+		err := gtserror.Newf("db error getting newsletters: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiNewsletters := make([]*apimodel.RssNewsletter, 0, len(newsletters))
+	for _, newsletter := range newsletters {
+		apiNewsletter, errWithCode := n.apiNewsletter(ctx, newsletter)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+		apiNewsletters = append(apiNewsletters, apiNewsletter)
+	}
+
+	return apiNewsletters, nil
+}
+
+// receiveNewsletters publishes the messages delivered to the new/ directory
+// of the newsletter maildir, moving them to cur/ once handled. Messages
+// that couldn't be stored are left in new/ to be retried on the next run.
+func (n *rssTooter) receiveNewsletters(ctx context.Context, _ time.Time) {
+	maildir := config.GetRssNewsletterMaildir()
+
+	entries, err := os.ReadDir(filepath.Join(maildir, "new"))
+	if err != nil {
+		log.Errorf(ctx, "Failed to read newsletter maildir: %s", err)
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(maildir, "new", entry.Name())
+		if err := n.receiveNewsletter(ctx, path); err != nil {
+			log.Errorf(ctx, "Failed to receive newsletter message %s: %s", entry.Name(), err)
+			continue
+		}
+
+		// Flagged as seen, following the maildir conventions.
+		if err := os.Rename(path, filepath.Join(maildir, "cur", entry.Name()+":2,S")); err != nil {
+			log.Errorf(ctx, "Failed to move newsletter message %s: %s", entry.Name(), err)
+		}
+	}
+}
+
+// receiveNewsletter publishes the message at path by the accounts of the
+// newsletters it is addressed to. Messages which can't be parsed or aren't
+// addressed to any newsletter are dropped, only storage errors are returned.
+func (n *rssTooter) receiveNewsletter(ctx context.Context, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	message, err := parseNewsletter(io.LimitReader(file, maxNewsletterSize))
+	if err != nil {
+		log.Warnf(ctx, "Dropping newsletter message %s: %s", filepath.Base(path), err)
+		return nil
+	}
+
+	received := false
+	seen := make(map[string]bool)
+	for _, recipient := range message.Recipients {
+		newsletter, err := n.GetRssNewsletterByAddress(ctx, recipient)
+		if errors.Is(err, db.ErrNoEntries) {
+			continue
+		} else if err != nil {
+			return gtserror.Newf("couldn't get newsletter: %w", err)
+		}
+
+		if seen[newsletter.ID] {
+			continue
+		}
+		seen[newsletter.ID] = true
+		received = true
+
+		if err := n.publishNewsletter(ctx, newsletter, message); err != nil {
+			return err
+		}
+	}
+
+	if !received {
+		log.Warnf(ctx, "Dropping newsletter message %s: no newsletter at %v", filepath.Base(path), message.Recipients)
+	}
+
+	return nil
+}
+
+// publishNewsletter publishes message by the account of newsletter, except
+// subscription confirmations whose link is kept for admins to visit.
+func (n *rssTooter) publishNewsletter(ctx context.Context, newsletter *gtsmodel.RssNewsletter, message *newsletterMessage) error {
+	account, err := n.state.DB.GetAccountByID(ctx, newsletter.AccountID)
+	if err != nil {
+		return gtserror.Newf("couldn't get newsletter account: %w", err)
+	}
+
+	content, descriptions, confirmURL := newsletterContent(message)
+	confirmation := confirmURL != "" && confirmRg.MatchString(message.Subject)
+
+	columns := []string{"last_message_at"}
+	newsletter.LastMessageAt = time.Now()
+	if message.UnsubscribeURL != "" {
+		newsletter.UnsubscribeURL = message.UnsubscribeURL
+		columns = append(columns, "unsubscribe_url")
+	}
+	if confirmation {
+		newsletter.ConfirmURL = confirmURL
+		columns = append(columns, "confirm_url")
+	}
+
+	if err := n.UpdateRssNewsletter(ctx, newsletter, columns...); err != nil {
+		return gtserror.Newf("couldn't update newsletter: %w", err)
+	}
+
+	if confirmation {
+		log.Infof(ctx, "Newsletter %s is waiting for the confirmation of its subscription", newsletter.Address)
+		return nil
+	}
+
+	var attachments []*gtsmodel.MediaAttachment
+	for _, image := range message.Images {
+		if len(attachments) == config.GetStatusesMediaMaxFiles() {
+			break
+		}
+		attachment, err := n.storeNewsletterImage(ctx, account, image, descriptions[image.ContentID])
+		if err != nil {
+			log.Warnf(ctx, "Failed to store newsletter image: %s", err)
+			continue
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return n.PutStatus(ctx, &ToCreate{
+		Account: account,
+		Item: &gofeed.Item{
+			Title:           html.EscapeString(message.Subject),
+			Content:         content,
+			PublishedParsed: &message.Date,
+		},
+		Attachments: attachments,
+	})
+}
+
+// storeNewsletterImage stores an image of a newsletter message
+// through the media pipeline, owned by the newsletter account.
+func (n *rssTooter) storeNewsletterImage(ctx context.Context, account *gtsmodel.Account, image *newsletterImage, description string) (*gtsmodel.MediaAttachment, error) {
+	data := func(context.Context) (io.ReadCloser, int64, error) {
+		return io.NopCloser(bytes.NewReader(image.Data)), int64(len(image.Data)), nil
+	}
+
+	processing := n.mediaManager.PreProcessMedia(data, account.ID, &media.AdditionalMediaInfo{
+		Description: &description,
+	})
+
+	attachment, err := processing.LoadAttachment(ctx)
+	if err != nil {
+		return nil, err
+	} else if attachment.Type != gtsmodel.FileTypeImage {
+		return nil, gtserror.Newf("could not process image of type %s", attachment.File.ContentType)
+	}
+
+	return attachment, nil
+}
+
+// parseNewsletter parses a message in its MIME representation.
+func parseNewsletter(r io.Reader) (*newsletterMessage, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &newsletterMessage{
+		Recipients:     messageRecipients(msg.Header),
+		Subject:        decodeHeader(msg.Header.Get("Subject")),
+		UnsubscribeURL: unsubscribeURL(msg.Header.Get("List-Unsubscribe")),
+	}
+
+	message.Date, err = msg.Header.Date()
+	if err != nil || message.Date.After(time.Now()) {
+		message.Date = time.Now()
+	}
+
+	if err := message.readPart(textproto.MIMEHeader(msg.Header), msg.Body, 0); err != nil {
+		return nil, err
+	}
+
+	if message.HTML == "" && message.Text == "" {
+		return nil, errors.New("message has no text")
+	}
+
+	return message, nil
+}
+
+// readPart reads the body of a part of the message, descending into multipart bodies.
+func (m *newsletterMessage) readPart(header textproto.MIMEHeader, body io.Reader, depth int) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", nil
+	}
+
+	disposition, _, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	body = decodeTransfer(header.Get("Content-Transfer-Encoding"), body)
+
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		if depth >= maxNewsletterDepth {
+			return errors.New("message parts are nested too deep")
+		}
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if errors.Is(err, io.EOF) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if err := m.readPart(part.Header, part, depth+1); err != nil {
+				return err
+			}
+		}
+
+	case disposition == "attachment" && !strings.HasPrefix(mediaType, "image/"):
+		// Other attached files are not published.
+		return nil
+
+	case mediaType == "text/html" && m.HTML == "":
+		text, err := readText(body, params["charset"])
+		m.HTML = text
+		return err
+
+	case mediaType == "text/plain" && m.Text == "":
+		text, err := readText(body, params["charset"])
+		m.Text = text
+		return err
+
+	case strings.HasPrefix(mediaType, "image/"):
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		m.Images = append(m.Images, &newsletterImage{
+			ContentID: strings.Trim(header.Get("Content-Id"), "<> "),
+			Data:      data,
+		})
+	}
+
+	return nil
+}
+
+// newsletterContent returns the html content of the status of message without
+// its style, scripts and images (remote ones being mostly tracking pixels),
+// sanitized like any status content, the descriptions of its inline images by
+// content id, and its confirmation link.
+func newsletterContent(message *newsletterMessage) (string, map[string]string, string) {
+	descriptions := make(map[string]string)
+
+	if message.HTML == "" {
+		paragraphs := strings.Split(strings.ReplaceAll(message.Text, "\r\n", "\n"), "\n\n")
+		var content strings.Builder
+		for _, paragraph := range paragraphs {
+			if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+				content.WriteString("<p>" + strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>") + "</p>")
+			}
+		}
+		return content.String(), descriptions, confirmLink(nil, message.Text)
+	}
+
+	doc, err := htmlquery.Parse(strings.NewReader(message.HTML))
+	if err != nil {
+		return "", descriptions, ""
+	}
+
+	for _, node := range htmlquery.Find(doc, "//img") {
+		if src := htmlquery.SelectAttr(node, "src"); strings.HasPrefix(src, "cid:") {
+			descriptions[strings.TrimPrefix(src, "cid:")] = htmlquery.SelectAttr(node, "alt")
+		}
+	}
+
+	for _, node := range htmlquery.Find(doc, "//img|//script|//style|//head|//comment()") {
+		if node.Parent != nil {
+			node.Parent.RemoveChild(node)
+		}
+	}
+
+	body := htmlquery.FindOne(doc, "//body")
+	if body == nil {
+		body = doc
+	}
+
+	var content bytes.Buffer
+	for child := body.FirstChild; child != nil; child = child.NextSibling {
+		if err := html.Render(&content, child); err != nil {
+			return "", descriptions, ""
+		}
+	}
+
+	return strings.TrimSpace(text.SanitizeToHTML(content.String())), descriptions, confirmLink(body, "")
+}
+
+// confirmLink returns the first link of the html body, or of the plain
+// text, looking like the confirmation of a subscription.
+func confirmLink(body *html.Node, text string) string {
+	if body != nil {
+		for _, node := range htmlquery.Find(body, "//a[@href]") {
+			href := htmlquery.SelectAttr(node, "href")
+			if strings.HasPrefix(href, "http") && (confirmRg.MatchString(href) || confirmRg.MatchString(htmlquery.InnerText(node))) {
+				return href
+			}
+		}
+		return ""
+	}
+
+	for _, field := range strings.Fields(text) {
+		field = strings.Trim(field, "<>()[]")
+		if strings.HasPrefix(field, "http") && confirmRg.MatchString(field) {
+			return field
+		}
+	}
+	return ""
+}
+
+// messageRecipients returns the lowercase addresses of the recipients of a message.
+func messageRecipients(header mail.Header) []string {
+	var recipients []string
+	for _, key := range recipientHeaders {
+		for _, value := range header[textproto.CanonicalMIMEHeaderKey(key)] {
+			addresses, err := mail.ParseAddressList(value)
+			if err != nil {
+				continue
+			}
+			for _, address := range addresses {
+				recipients = append(recipients, strings.ToLower(address.Address))
+			}
+		}
+	}
+	return recipients
+}
+
+// unsubscribeURL returns the first web link of a List-Unsubscribe header, or its mailto link.
+func unsubscribeURL(header string) string {
+	var mailto string
+	for _, link := range strings.Split(header, ",") {
+		link = strings.Trim(strings.TrimSpace(link), "<>")
+		if strings.HasPrefix(link, "https://") || strings.HasPrefix(link, "http://") {
+			return link
+		} else if strings.HasPrefix(link, "mailto:") && mailto == "" {
+			mailto = link
+		}
+	}
+	return mailto
+}
+
+// decodeHeader decodes the RFC 2047 encoded words of a header value.
+func decodeHeader(value string) string {
+	decoded, err := headerDecoder.DecodeHeader(value)
+	if err != nil {
+		return strings.TrimSpace(value)
+	}
+	return strings.TrimSpace(decoded)
+}
+
+// decodeTransfer decodes a body with its content transfer encoding.
+func decodeTransfer(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// readText reads a text body in the given charset, converted to utf-8.
+func readText(body io.Reader, label string) (string, error) {
+	if label != "" {
+		if reader, err := charset.NewReaderLabel(label, body); err == nil {
+			body = reader
+		}
+	}
+	data, err := io.ReadAll(body)
+	return string(data), err
+}
+
+// apiNewsletter returns the api model of newsletter.
+func (n *rssTooter) apiNewsletter(ctx context.Context, newsletter *gtsmodel.RssNewsletter) (*apimodel.RssNewsletter, gtserror.WithCode) {
+	account, err := n.state.DB.GetAccountByID(ctx, newsletter.AccountID)
+	if err != nil {
+		err := gtserror.Newf("db error getting newsletter account: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiAccount, err := n.converter.AccountToAPIAccountPublic(ctx, account)
+	if err != nil {
+		err := gtserror.Newf("error converting newsletter account: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	var lastMessageAt *string
+	if !newsletter.LastMessageAt.IsZero() {
+		lastMessageAt = util.Ptr(util.FormatISO8601(newsletter.LastMessageAt))
+	}
+
+	return &apimodel.RssNewsletter{
+		Account:        apiAccount,
+		Address:        newsletter.Address,
+		UnsubscribeURL: newsletter.UnsubscribeURL,
+		ConfirmURL:     newsletter.ConfirmURL,
+		LastMessageAt:  lastMessageAt,
+	}, nil
+}
@@ -0,0 +1,148 @@
+package rss
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type NewsletterTestSuite struct {
+	RssStandardTestSuite
+}
+
+// deliver writes the message fixture addressed to address in the new/ directory of maildir.
+func (suite *NewsletterTestSuite) deliver(maildir string, fixture string, address string) {
+	data, err := os.ReadFile(filepath.Join("testdata", fixture))
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	for _, dir := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(maildir, dir), 0o700); err != nil {
+			suite.FailNow(err.Error())
+		}
+	}
+
+	message := strings.ReplaceAll(string(data), "{{address}}", address)
+	if err := os.WriteFile(filepath.Join(maildir, "new", fixture), []byte(message), 0o600); err != nil {
+		suite.FailNow(err.Error())
+	}
+}
+
+func (suite *NewsletterTestSuite) TestParseNewsletter() {
+	file, err := os.Open("testdata/newsletter.eml")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	defer file.Close()
+
+	message, err := parseNewsletter(file)
+	suite.NoError(err)
+	suite.Equal([]string{"subscribers@smallblog.example.org"}, message.Recipients)
+	suite.Equal("Gardening notes — June", message.Subject)
+	suite.Equal(2024, message.Date.Year())
+	suite.Equal("https://smallblog.example.org/unsubscribe?id=42", message.UnsubscribeURL)
+	suite.Equal("Tomatoes are finally growing.\r\n", message.Text)
+	suite.Len(message.Images, 1)
+	suite.Equal("banner@smallblog", message.Images[0].ContentID)
+
+	content, descriptions, confirmURL := newsletterContent(message)
+	suite.True(strings.HasPrefix(content, "<h1>Gardening notes</h1>"))
+	suite.Contains(content, `<p>Tomatoes are finally growing — see the <a href="https://smallblog.example.org/tomatoes" rel="nofollow noreferrer noopener" target="_blank">photos</a>.</p>`)
+	suite.NotContains(content, "<img")
+	suite.NotContains(content, "<style>")
+	suite.NotContains(content, "<script>")
+	suite.Equal("Tomato plants", descriptions["banner@smallblog"])
+	suite.Empty(confirmURL)
+}
+
+func (suite *NewsletterTestSuite) TestNewsletterContentSanitized() {
+	content, _, _ := newsletterContent(&newsletterMessage{HTML: `<html><body>
+		<p style="color: red" onclick="steal()">Hello <a href="https://smallblog.example.org/" onmouseover="steal()">readers</a></p>
+		<form action="https://evil.example.org/"><input name="password"></form>
+		<iframe src="https://evil.example.org/"></iframe>
+	</body></html>`})
+
+	suite.Contains(content, "Hello")
+	suite.Contains(content, `href="https://smallblog.example.org/"`)
+	for _, unsafe := range []string{"onclick", "onmouseover", "style=", "<form", "<input", "<iframe", "evil.example.org"} {
+		suite.NotContains(content, unsafe)
+	}
+}
+
+func (suite *NewsletterTestSuite) TestReceiveNewsletters() {
+	ctx := context.Background()
+	maildir := suite.T().TempDir()
+	config.SetRssNewsletterMaildir(maildir)
+
+	_, errWithCode := suite.tooter.CreateNewsletter(ctx, suite.testUsers["local_account_1"], &apimodel.RssNewsletterCreateRequest{Username: "smallblog"})
+	suite.Equal(http.StatusForbidden, errWithCode.Code())
+
+	newsletter, errWithCode := suite.tooter.CreateNewsletter(ctx, suite.testUsers["admin_account"], &apimodel.RssNewsletterCreateRequest{
+		Username:    "smallblog",
+		DisplayName: "A Small Blog",
+	})
+	suite.Nil(errWithCode)
+	suite.Equal("smallblog", newsletter.Account.Username)
+	suite.True(strings.HasPrefix(newsletter.Address, "smallblog."))
+	suite.True(strings.HasSuffix(newsletter.Address, "@localhost"))
+
+	_, errWithCode = suite.tooter.CreateNewsletter(ctx, suite.testUsers["admin_account"], &apimodel.RssNewsletterCreateRequest{Username: "smallblog"})
+	suite.Equal(http.StatusConflict, errWithCode.Code())
+
+	// The confirmation link is kept for admins, and not published.
+	suite.deliver(maildir, "confirm.eml", strings.ToUpper(newsletter.Address))
+	suite.tooter.receiveNewsletters(ctx, time.Now())
+
+	newsletters, errWithCode := suite.tooter.GetNewsletters(ctx, suite.testUsers["admin_account"])
+	suite.Nil(errWithCode)
+	suite.Len(newsletters, 1)
+	suite.Equal("https://smallblog.example.org/confirm?token=abc", newsletters[0].ConfirmURL)
+	suite.NotNil(newsletters[0].LastMessageAt)
+
+	statuses, err := suite.tooter.GetStatusesSince(ctx, newsletter.Account.ID, "")
+	suite.NoError(err)
+	suite.Empty(statuses)
+
+	suite.deliver(maildir, "newsletter.eml", newsletter.Address)
+	suite.tooter.receiveNewsletters(ctx, time.Now())
+
+	statuses, err = suite.tooter.GetStatusesSince(ctx, newsletter.Account.ID, "")
+	suite.NoError(err)
+	suite.Len(statuses, 1)
+
+	status, err := suite.state.DB.GetStatusByID(ctx, statuses[0].ID)
+	suite.NoError(err)
+	suite.Contains(status.Content, "<p>Gardening notes — June</p>")
+	suite.Contains(status.Content, `<a href="https://smallblog.example.org/tomatoes" rel="nofollow noreferrer noopener" target="_blank">photos</a>`)
+	suite.NotContains(status.Content, "tracker.example.org")
+	suite.NotContains(status.Content, "<script>")
+	suite.Len(status.Attachments, 1)
+	suite.Equal(gtsmodel.FileTypeImage, status.Attachments[0].Type)
+	suite.Equal("Tomato plants", status.Attachments[0].Description)
+
+	newsletters, errWithCode = suite.tooter.GetNewsletters(ctx, suite.testUsers["admin_account"])
+	suite.Nil(errWithCode)
+	suite.Equal("https://smallblog.example.org/unsubscribe?id=42", newsletters[0].UnsubscribeURL)
+
+	// Handled messages are moved out of new/.
+	entries, err := os.ReadDir(filepath.Join(maildir, "new"))
+	suite.NoError(err)
+	suite.Empty(entries)
+	entries, err = os.ReadDir(filepath.Join(maildir, "cur"))
+	suite.NoError(err)
+	suite.Len(entries, 2)
+}
+
+func TestNewsletterTestSuite(t *testing.T) {
+	suite.Run(t, new(NewsletterTestSuite))
+}
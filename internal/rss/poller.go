@@ -1,11 +1,7 @@
 package rss
 
 import (
-   "compress/flate"
-   "compress/gzip"
    "context"
-   "fmt"
-   "net/http"
    "sort"
    "time"
 
@@ -15,8 +11,23 @@ import (
 )
 
 type ToCreate struct {
-   Account   *gtsmodel.Account
-   Item      *gofeed.Item
+   Account     *gtsmodel.Account
+   Item        *gofeed.Item
+   Digest      bool // only store the status, it will be published by the next feed digest
+   Backfill    bool // only store the status, an older item of a new feed account
+   InReplyTo   *gtsmodel.Status // status of the item this item replies to
+   Comment     bool // item comes from the comment feed of another item
+   Attachments []*gtsmodel.MediaAttachment // attachments already stored, like the images of a newsletter
+   ArticleURL  string // canonical url of the item article, resolved when looking for duplicates
+   itemPage    *itemPage
+}
+
+// page returns the page of the item, fetched once for all its readers.
+func (t *ToCreate) page() *itemPage {
+   if t.itemPage == nil {
+      t.itemPage = &itemPage{link: t.Item.Link}
+   }
+   return t.itemPage
 }
 
 
@@ -27,157 +38,146 @@ func (n *rssTooter) refresh() {
    for {
       select {
          case <-n.ctx.Done(): return
-         case <-ticker.C:
-            var toCreate []ToCreate
-
-            toPoll, err := n.GetAccountsToPoll(n.ctx)
-            log.Infof(nil, "Started polling %d accounts", len(toPoll))
-            if( err != nil ) {
-               log.Errorf(nil, "Failed to retrieve accounts to poll: %s", err)
-            }
-
-            fp := gofeed.NewParser()
-            client := http.Client{Timeout: time.Duration(30) * time.Second}
-
-            for _, infos := range toPoll {
-               account, err := n.state.DB.GetAccountByID(n.ctx, infos.DBAccountID)
-               if( err != nil ) {
-                  log.Errorf(nil, "Failed to retrieve account: %s", err)
-                  continue
-               }
-
-               etag := ""
-               if len(account.Fields) > 0 && account.Fields[0].Name == "etag" {
-                  etag = account.Fields[0].Value
-               }
-
-               feed, err := parseURLWithCache(fp, &client, infos.Url, etag, &account.FetchedAt, n.ctx)
-               if err != nil {
-                  log.Errorf(nil, "Invalid feed url: %s", err)
-                  continue
-               }
-
-               if feed.Feed != nil {
-                  size := len(toCreate)
-                  for _, item := range feed.Feed.Items {
-                     if( item.PublishedParsed.After(infos.LastTweet) ){
-                        toCreate = append(toCreate, ToCreate { Account: account, Item: item })
-                     }
-                  }
-                  if len(feed.Feed.Items) > 0 && len(toCreate) == size {
-                     log.Warnf(nil, "Feed was not cached but returned no new items :( (%s)", infos.Url)
-                  }
-               }
-
-               account.FetchedAt = *feed.LastModified
-               account.Fields = []*gtsmodel.Field{
-                  &gtsmodel.Field { Name: "etag", Value: feed.Etag, },
-               }
-
-               err = n.state.DB.UpdateAccount(n.ctx, account, "fields", "fetched_at")
-               if err != nil {
-                  log.Errorf(nil, "Failed to save modified account: %s", err)
-               }
-            }
-
-            sort.SliceStable(toCreate, func(i, j int) bool {
-               return toCreate[i].Item.PublishedParsed.Before(*toCreate[j].Item.PublishedParsed)
-            })
-
-            for _, create := range toCreate {
-               err = n.PutStatus(n.ctx, &create)
-               if( err != nil ) {
-                  log.Errorf(nil, "Failed to create tweet %s: %s", create.Item, err)
-               }
-            }
+         case <-ticker.C: n.poll(n.ctx)
       }
    }
 }
 
-type HTTPFeed struct {
-   Feed              *gofeed.Feed
-   Etag              string
-   LastModified      *time.Time
-}
+// poll fetches every followed feed once, and creates the statuses of their new items.
+func (n *rssTooter) poll(ctx context.Context) {
+   var toCreate []ToCreate
+   var toComment []ToCreate
+   var fetched, notModified int
 
-func parseURLWithCache(f *gofeed.Parser, client *http.Client, feedURL string, etag string, lastModified *time.Time, ctx context.Context) (feed *HTTPFeed, err error) {
-   location := time.FixedZone("GMT", 0)
+   start := time.Now()
+   ctx, span := startSpan(ctx, "rss.Poll")
+   defer func() {
+      n.metrics.recordCycle(ctx, time.Since(start), fetched, notModified)
+      span.SetAttributes(attrItemCount.Int(len(toCreate)))
+      span.End()
+   }()
 
-   req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
-   if err != nil {
-      return nil, err
+   toPoll, err := n.GetAccountsToPoll(ctx)
+   log.Infof(nil, "Started polling %d accounts", len(toPoll))
+   if( err != nil ) {
+      log.Errorf(nil, "Failed to retrieve accounts to poll: %s", err)
    }
-   req.Header.Set("User-Agent", f.UserAgent)
-   req.Header.Set("Accept-Encoding", "gzip, deflate")
+   span.SetAttributes(attrFeedCount.Int(len(toPoll)))
 
-   if etag != "" {
-      req.Header.Set("If-None-Match", etag)
+   if err := n.DeletePendingRssFeedClaimsBefore(ctx, time.Now().Add(-claimTTL)); err != nil {
+      log.Errorf(nil, "Failed to delete expired feed claims: %s", err)
    }
-
-   if lastModified != nil {
-      req.Header.Set("If-Modified-Since", lastModified.In(location).Format(time.RFC1123))
+   claims, err := n.GetPendingRssFeedClaims(ctx)
+   if( err != nil ) {
+      log.Errorf(nil, "Failed to retrieve feed claims: %s", err)
    }
-
-   if f.AuthConfig != nil && f.AuthConfig.Username != "" && f.AuthConfig.Password != "" {
-      req.SetBasicAuth(f.AuthConfig.Username, f.AuthConfig.Password)
+   pendingClaims := make(map[string][]*gtsmodel.RssFeedClaim)
+   for _, claim := range claims {
+      pendingClaims[claim.AccountID] = append(pendingClaims[claim.AccountID], claim)
    }
 
-   resp, err := client.Do(req)
-
-   if err != nil {
-      return nil, err
-   }
+   for _, infos := range toPoll {
+      account, err := n.state.DB.GetAccountByID(ctx, infos.DBAccountID)
+      if( err != nil ) {
+         log.Errorf(nil, "Failed to retrieve account: %s", err)
+         continue
+      }
 
-   if resp.StatusCode != 200 && resp.StatusCode != 206 && resp.StatusCode != 304 {
-      return nil, fmt.Errorf("Invalid returned HTTPCode: %s - %s", resp.StatusCode, resp.Status)
-   }
+      etag := ""
+      if len(account.Fields) > 0 && account.Fields[0].Name == "etag" {
+         etag = account.Fields[0].Value
+      }
 
-   httpFeed := HTTPFeed {
-      Etag: resp.Header.Get("Etag"),
-      LastModified: lastModified,
-   }
+      lastModified := &account.FetchedAt
 
-   if lastModified := resp.Header.Get("Last-Modified"); lastModified != "" {
-      parsed, err := time.ParseInLocation(time.RFC1123, lastModified, location)
-      if err == nil {
-         httpFeed.LastModified = &parsed
+      fetchStart := time.Now()
+      feedCtx, feedSpan := startSpan(ctx, "rss.PollFeed", attrFeedURL.String(infos.Url), attrAccountID.String(account.ID))
+      var feed *HTTPFeed
+      if infos.Scraper != nil {
+         feed, err = n.scrapeFeed(feedCtx, account, infos.Url, infos.Scraper)
+      } else {
+         feed, err = n.fetcher.FetchFeed(feedCtx, infos.Url, etag, lastModified)
+      }
+      switch n.metrics.recordFetch(ctx, infos.Url, time.Since(fetchStart), feed, err) {
+         case pollResultFetched: fetched++
+         case pollResultNotModified: notModified++
+      }
+      if err != nil {
+         log.Errorf(nil, "Invalid feed url: %s", err)
+         n.failing.failed(account.ID)
+         endSpan(feedSpan, err)
+         continue
+      }
+      n.failing.succeeded(account.ID)
+
+      if feed.Feed != nil {
+         size := len(toCreate)
+         for _, item := range datedItems(feed.Feed.Items) {
+            // Scraped items are told apart by link instead, as their date may be anything.
+            if( infos.Scraper != nil || item.PublishedParsed.After(infos.LastTweet) ){
+               toCreate = append(toCreate, ToCreate { Account: account, Item: item, Digest: infos.Digest != gtsmodel.RssDigestNone })
+            }
+            if commentFeed(item) != "" {
+               toComment = append(toComment, ToCreate { Account: account, Item: item })
+            }
+         }
+         if len(feed.Feed.Items) > 0 && len(toCreate) == size {
+            log.Warnf(nil, "Feed was not cached but returned no new items :( (%s)", infos.Url)
+         }
+         feedSpan.SetAttributes(attrItemCount.Int(len(toCreate) - size))
       }
-   }
-
-   if resp.StatusCode == 304 {
-      return &httpFeed, nil
-   }
 
-   reader := resp.Body
+      // The website of the feed may hold a claim token, modified or not.
+      if claims := pendingClaims[account.ID]; len(claims) > 0 {
+         n.verifyClaims(feedCtx, account, infos.Url, feed, claims)
+      }
 
-   if !resp.Uncompressed {
-      switch ce := resp.Header.Get("Content-Encoding"); ce {
-      case "gzip":
-         reader, err = gzip.NewReader(reader)
-         if err != nil {
-            return nil, fmt.Errorf("Failed to initialize gzip reader", err)
+      if feed.LastModified != nil {
+         account.FetchedAt = *feed.LastModified
+      }
+      // The etag is kept as first field, before the verified website.
+      fields := []*gtsmodel.Field{
+         &gtsmodel.Field { Name: "etag", Value: feed.Etag, },
+      }
+      for _, field := range account.Fields {
+         if field.Name != "etag" {
+            fields = append(fields, field)
          }
-      case "deflate":
-         reader = flate.NewReader(reader)
-      case "":
-         break // Decompression was handled by transport
-      default:
-         return nil, fmt.Errorf("Unknow Content-Encoding: %s", ce)
       }
-   }
+      account.Fields = fields
 
-   defer func() {
-      ce := reader.Close()
-      if ce != nil {
-         err = ce
+      err = n.state.DB.UpdateAccount(feedCtx, account, "fields", "fetched_at")
+      if err != nil {
+         log.Errorf(nil, "Failed to save modified account: %s", err)
       }
-   }()
+      endSpan(feedSpan, err)
+   }
 
-   res, err := f.Parse(reader)
-   if err != nil {
-      return nil, err
+   sort.SliceStable(toCreate, func(i, j int) bool {
+      return toCreate[i].Item.PublishedParsed.Before(*toCreate[j].Item.PublishedParsed)
+   })
+
+   for _, create := range toCreate {
+      itemCtx, itemSpan := startSpan(ctx, "rss.PutStatus", attrItemLink.String(create.Item.Link), attrAccountID.String(create.Account.ID), attrDigest.Bool(create.Digest))
+      if create.Digest {
+         _, err = n.storeItem(itemCtx, &create)
+      } else {
+         err = n.PutStatus(itemCtx, &create)
+      }
+      endSpan(itemSpan, err)
+      if( err != nil ) {
+         log.Errorf(nil, "Failed to create tweet %s: %s", create.Item.Link, err)
+         continue
+      }
+      n.metrics.itemsIngested.Add(ctx, 1)
    }
-   httpFeed.Feed = res
 
-   return &httpFeed, nil
+   if len(toComment) > 0 {
+      n.pruneCommentFeeds(ctx)
+   }
+   for _, comment := range toComment {
+      commentCtx, commentSpan := startSpan(ctx, "rss.IngestComments", attrItemLink.String(comment.Item.Link), attrAccountID.String(comment.Account.ID))
+      n.ingestComments(commentCtx, comment.Account, comment.Item)
+      commentSpan.End()
+   }
 }
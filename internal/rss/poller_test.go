@@ -0,0 +1,331 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"golang.org/x/net/html"
+)
+
+type PollerTestSuite struct {
+	RssStandardTestSuite
+}
+
+// feedAccount turns a followed testrig account into the feed account of fixture.
+func (suite *PollerTestSuite) feedAccount(fixture string) *gtsmodel.Account {
+	ctx := context.Background()
+
+	account, err := suite.state.DB.GetAccountByID(ctx, suite.testAccounts["local_account_1"].ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// Accounts are polled for their feed record, whatever their actor type.
+	if err := suite.tooter.PutRssFeed(ctx, &gtsmodel.RssFeed{
+		ID:        id.NewULID(),
+		AccountID: account.ID,
+		URL:       suite.fixtureURL(fixture),
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	return account
+}
+
+func (suite *PollerTestSuite) TestPoll() {
+	ctx := context.Background()
+	account := suite.feedAccount("rss2.xml")
+
+	suite.tooter.poll(ctx)
+
+	tomatoes, err := suite.tooter.GetStatusByAccountURL(ctx, account.ID, "https://blog.example.org/2024/06/03/tomatoes-again/")
+	suite.NoError(err)
+	suite.Equal("2024-06-03T09:12:44Z", tomatoes.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"))
+	suite.Contains(tomatoes.Content, "The first tomatoes of the year are in.")
+	suite.Equal(gtsmodel.VisibilityPublic, tomatoes.Visibility)
+
+	_, err = suite.tooter.GetStatusByAccountURL(ctx, account.ID, suite.fixtureURL("2024/05/28/relative/"))
+	suite.NoError(err)
+
+	// The item with a broken date is skipped.
+	_, err = suite.tooter.GetStatusByAccountURL(ctx, account.ID, "https://blog.example.org/2024/05/20/broken-date/")
+	suite.True(errors.Is(err, db.ErrNoEntries))
+
+	// The cache headers are kept for the next poll.
+	account, err = suite.state.DB.GetAccountByID(ctx, account.ID)
+	suite.NoError(err)
+	suite.Equal(`"rss2.xml-v1"`, account.Fields[0].Value)
+	suite.True(fixtureModTime.Equal(account.FetchedAt))
+
+	feed, err := suite.tooter.GetRssFeedByAccountID(ctx, account.ID)
+	suite.NoError(err)
+	suite.True(tomatoes.CreatedAt.Equal(feed.LastItemAt))
+}
+
+func (suite *PollerTestSuite) TestPollNotModified() {
+	ctx := context.Background()
+	account := suite.feedAccount("rss2.xml")
+
+	suite.tooter.poll(ctx)
+	statuses, err := suite.tooter.GetStatusesSince(ctx, account.ID, "")
+	suite.NoError(err)
+
+	// The second poll gets a 304, and creates nothing.
+	suite.tooter.poll(ctx)
+	again, err := suite.tooter.GetStatusesSince(ctx, account.ID, "")
+	suite.NoError(err)
+	suite.Len(again, len(statuses))
+}
+
+func (suite *PollerTestSuite) TestBackfill() {
+	ctx := context.Background()
+	account := suite.feedAccount("rss2.xml")
+
+	_, feed, err := NewRssFeed(&suite.state, suite.tooter.fetcher, ctx, suite.fixtureURL("gzip/rss2.xml"))
+	suite.NoError(err)
+
+	// The items of the next page of the feed are imported
+	// too, without the duplicated one nor fetching the feed again.
+	fetcher := &recordingFetcher{Fetcher: suite.tooter.fetcher}
+	suite.tooter.fetcher = fetcher
+	suite.tooter.backfill(ctx, account, feed)
+	suite.Equal([]string{suite.fixtureURL("rss2-page2.xml")}, fetcher.urls)
+
+	for _, url := range []string{
+		"https://blog.example.org/2024/06/03/tomatoes-again/",
+		suite.fixtureURL("2024/05/28/relative/"),
+		"https://blog.example.org/2024/04/11/fixing-a-flat/",
+	} {
+		_, err := suite.tooter.GetStatusByAccountURL(ctx, account.ID, url)
+		suite.NoError(err, url)
+	}
+}
+
+func (suite *PollerTestSuite) TestPollBeforeBackfill() {
+	ctx := context.Background()
+
+	username, err := suite.tooter.NewUser(ctx, suite.fixtureURL("rss2.xml"))
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	account, err := suite.state.DB.GetAccountByUsernameDomain(ctx, username, "")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// Feed accounts are polled once followed.
+	admin := suite.testAccounts["admin_account"]
+	if err := suite.state.DB.PutFollow(ctx, &gtsmodel.Follow{
+		ID:              id.NewULID(),
+		URI:             admin.URI + "/follow/" + account.ID,
+		AccountID:       admin.ID,
+		TargetAccountID: account.ID,
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// A poll running before the backfill leaves
+	// it the current items, without posting them.
+	queued := suite.state.Workers.Client.Queue.Len()
+	suite.tooter.poll(ctx)
+	suite.Equal(queued, suite.state.Workers.Client.Queue.Len())
+
+	statuses, err := suite.tooter.GetStatusesSince(ctx, account.ID, "")
+	suite.NoError(err)
+	suite.Empty(statuses)
+
+	backfill, ok := suite.state.Workers.Dereference.Queue.Pop()
+	if !ok {
+		suite.FailNow("no backfill queued")
+	}
+	backfill(ctx)
+
+	_, err = suite.tooter.GetStatusByAccountURL(ctx, account.ID, "https://blog.example.org/2024/06/03/tomatoes-again/")
+	suite.NoError(err)
+	suite.Equal(queued, suite.state.Workers.Client.Queue.Len())
+}
+
+// recordMetrics makes the poller record its metrics in a reader
+// whose collected data points are returned by the returned func.
+func (suite *PollerTestSuite) recordMetrics() func(name string) []metricdata.DataPoint[int64] {
+	reader := sdk.NewManualReader()
+	metrics, err := initPollerMetrics(sdk.NewMeterProvider(sdk.WithReader(reader)).Meter("test"), suite.tooter.failing)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.tooter.metrics = metrics
+
+	return func(name string) []metricdata.DataPoint[int64] {
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(context.Background(), &rm); err != nil {
+			suite.FailNow(err.Error())
+		}
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if m.Name != name {
+					continue
+				}
+				switch data := m.Data.(type) {
+				case metricdata.Sum[int64]:
+					return data.DataPoints
+				case metricdata.Gauge[int64]:
+					return data.DataPoints
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// dataPoint returns the value of the data point having attribute key=value.
+func dataPoint(points []metricdata.DataPoint[int64], key string, value string) int64 {
+	for _, point := range points {
+		if v, ok := point.Attributes.Value(attribute.Key(key)); ok && v.AsString() == value {
+			return point.Value
+		}
+	}
+	return 0
+}
+
+func (suite *PollerTestSuite) TestPollMetrics() {
+	ctx := context.Background()
+	collect := suite.recordMetrics()
+	suite.feedAccount("rss2.xml")
+
+	suite.tooter.poll(ctx)
+	suite.tooter.poll(ctx)
+
+	polled := collect("gotosocial.rss.feeds_polled")
+	suite.EqualValues(1, dataPoint(polled, "result", pollResultFetched))
+	suite.EqualValues(1, dataPoint(polled, "result", pollResultNotModified))
+
+	responses := collect("gotosocial.rss.fetch_responses")
+	suite.EqualValues(1, dataPoint(responses, "status_code", "200"))
+	suite.EqualValues(1, dataPoint(responses, "status_code", "304"))
+
+	ingested := collect("gotosocial.rss.items_ingested")
+	suite.Len(ingested, 1)
+	suite.Positive(ingested[0].Value)
+
+	suite.Equal(1.0, suite.tooter.metrics.loadCacheHitRatio())
+}
+
+func (suite *PollerTestSuite) TestPollFailingFeed() {
+	ctx := context.Background()
+	collect := suite.recordMetrics()
+	account := suite.feedAccount("missing.xml")
+
+	suite.tooter.poll(ctx)
+	suite.EqualValues(1, dataPoint(collect("gotosocial.rss.fetch_responses"), "status_code", "404"))
+	suite.EqualValues(1, collect("gotosocial.rss.feeds_in_backoff")[0].Value)
+
+	// Failing feeds are still fetched on each cycle.
+	suite.tooter.poll(ctx)
+	suite.EqualValues(2, dataPoint(collect("gotosocial.rss.feeds_polled"), "result", pollResultError))
+
+	suite.tooter.failing.succeeded(account.ID)
+	suite.EqualValues(0, collect("gotosocial.rss.feeds_in_backoff")[0].Value)
+}
+
+func (suite *PollerTestSuite) TestPollParseError() {
+	ctx := context.Background()
+	collect := suite.recordMetrics()
+	suite.feedAccount("page.html")
+
+	suite.tooter.poll(ctx)
+
+	suite.Len(collect("gotosocial.rss.parse_errors"), 1)
+	suite.EqualValues(1, dataPoint(collect("gotosocial.rss.fetch_responses"), "status_code", "200"))
+}
+
+// spanRecorder keeps the ended spans exported to it.
+type spanRecorder struct {
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (r *spanRecorder) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	r.spans = append(r.spans, spans...)
+	return nil
+}
+
+func (r *spanRecorder) Shutdown(context.Context) error {
+	return nil
+}
+
+// span returns the attributes of the first span named name.
+func (r *spanRecorder) span(name string) map[attribute.Key]attribute.Value {
+	for _, span := range r.spans {
+		if span.Name() == name {
+			attrs := make(map[attribute.Key]attribute.Value)
+			for _, attr := range span.Attributes() {
+				attrs[attr.Key] = attr.Value
+			}
+			return attrs
+		}
+	}
+	return nil
+}
+
+func (suite *PollerTestSuite) TestPollSpans() {
+	ctx := context.Background()
+	recorder := &spanRecorder{}
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	url := suite.fixtureURL("rss2.xml")
+	suite.feedAccount("rss2.xml")
+	suite.tooter.poll(ctx)
+
+	poll := recorder.span("rss.Poll")
+	suite.EqualValues(1, poll[attrFeedCount].AsInt64())
+	suite.Positive(poll[attrItemCount].AsInt64())
+
+	suite.Equal(url, recorder.span("rss.PollFeed")[attrFeedURL].AsString())
+
+	fetch := recorder.span("rss.FetchFeed")
+	suite.Equal("miss", fetch[attrCacheOutcome].AsString())
+	suite.EqualValues(200, fetch[attrStatusCode].AsInt64())
+	suite.Positive(fetch[attrBytes].AsInt64())
+
+	suite.EqualValues(3, recorder.span("rss.ParseFeed")[attrItemCount].AsInt64())
+	suite.NotNil(recorder.span("rss.PutStatus"))
+
+	// All the spans of the cycle belong to the same trace.
+	for _, span := range recorder.spans {
+		suite.Equal(recorder.spans[0].SpanContext().TraceID(), span.SpanContext().TraceID(), span.Name())
+	}
+}
+
+func TestPollerTestSuite(t *testing.T) {
+	suite.Run(t, new(PollerTestSuite))
+}
+
+// recordingFetcher records the urls of the feeds and pages it fetches.
+type recordingFetcher struct {
+	Fetcher
+	urls  []string
+	pages []string
+}
+
+func (f *recordingFetcher) FetchFeed(ctx context.Context, url string, etag string, lastModified *time.Time) (*HTTPFeed, error) {
+	f.urls = append(f.urls, url)
+	return f.Fetcher.FetchFeed(ctx, url, etag, lastModified)
+}
+
+func (f *recordingFetcher) FetchHTML(ctx context.Context, url string) (*html.Node, error) {
+	f.pages = append(f.pages, url)
+	return f.Fetcher.FetchHTML(ctx, url)
+}
@@ -0,0 +1,172 @@
+package rss
+
+import (
+	"bytes"
+	"context"
+	"io"
+	netUrl "net/url"
+	"slices"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+// loadAvatar stores icon through the media pipeline,
+// and sets it as the avatar of account.
+func (n *rssTooter) loadAvatar(ctx context.Context, account *gtsmodel.Account, icon *Icon) error {
+	data := func(context.Context) (io.ReadCloser, int64, error) {
+		return io.NopCloser(bytes.NewReader(icon.Data)), int64(len(icon.Data)), nil
+	}
+
+	processing := n.mediaManager.PreProcessMedia(data, account.ID, &media.AdditionalMediaInfo{
+		Avatar:    util.Ptr(true),
+		RemoteURL: &icon.URL,
+	})
+
+	attachment, err := processing.LoadAttachment(ctx)
+	if err != nil {
+		return err
+	} else if attachment.Type == gtsmodel.FileTypeUnknown {
+		return gtserror.Newf("could not process icon of type %s", attachment.File.ContentType)
+	}
+
+	account.AvatarRemoteURL = icon.URL
+	account.AvatarMediaAttachmentID = attachment.ID
+	account.AvatarMediaAttachment = attachment
+
+	return nil
+}
+
+// refreshProfiles reads again the metadata of every feed and its
+// website, and updates the profile of the feed accounts.
+func (n *rssTooter) refreshProfiles(ctx context.Context, _ time.Time) {
+	feeds, err := n.GetRssFeeds(ctx)
+	if err != nil {
+		log.Errorf(ctx, "Failed to retrieve feeds: %s", err)
+		return
+	}
+
+	for _, feed := range feeds {
+		if _, err := n.refreshProfile(ctx, feed); err != nil {
+			log.Errorf(ctx, "Failed to refresh profile of %s: %s", feed.URL, err)
+		}
+	}
+}
+
+// refreshProfile updates the display name, note, avatar and header
+// of the account of feed, and federates an update of the account
+// if any of them changed. It returns whether the account changed.
+func (n *rssTooter) refreshProfile(ctx context.Context, feed *gtsmodel.RssFeed) (bool, error) {
+	account, err := n.state.DB.GetAccountByID(ctx, feed.AccountID)
+	if err != nil {
+		return false, gtserror.Newf("couldn't get feed account: %w", err)
+	}
+
+	feedUrl, err := netUrl.Parse(feed.URL)
+	if err != nil {
+		return false, gtserror.Newf("invalid feed url: %w", err)
+	}
+
+	var rssFeed *rssFeed
+	if feed.Scraper != nil {
+		rssFeed, err = LoadScrapedFeed(ctx, n.fetcher, feedUrl, feed.Scraper)
+	} else {
+		rssFeed, err = LoadRssFeed(ctx, n.fetcher, feedUrl)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// The media type of the feed is part of the actor of the account.
+	var feedColumns []string
+	if mediaType := rssFeed.MediaType(); mediaType != feed.MediaType {
+		feed.MediaType = mediaType
+		feedColumns = append(feedColumns, "media_type")
+	}
+
+	var columns []string
+
+	// The display name and note are left alone once edited
+	// by an admin or a delegate, and never emptied.
+	if title := rssFeed.Feed.Title; title != "" && title != account.DisplayName && !profileEdited(account.DisplayName, feed.ProfileDisplayName) {
+		account.DisplayName = title
+		feed.ProfileDisplayName = title
+		columns = append(columns, "display_name")
+		feedColumns = append(feedColumns, "profile_display_name")
+	}
+
+	if note := rssFeed.ExtractDescription(); note != "" && note != account.Note && !profileEdited(account.Note, feed.ProfileNote) {
+		account.Note = note
+		feed.ProfileNote = note
+		columns = append(columns, "note")
+		feedColumns = append(feedColumns, "profile_note")
+	}
+
+	if icon := rssFeed.ResolveIcon(ctx, n.fetcher); icon != nil && icon.URL != account.AvatarRemoteURL {
+		if err := n.loadAvatar(ctx, account, icon); err != nil {
+			log.Warnf(ctx, "Failed to load avatar %s: %s", icon.URL, err)
+		} else {
+			columns = append(columns, "avatar_remote_url", "avatar_media_attachment_id")
+		}
+	}
+
+	if header := rssFeed.ExtractHeader(); header != account.HeaderRemoteURL {
+		tsport, err := n.transportController.NewTransportForUsername(ctx, "")
+		if err != nil {
+			return false, gtserror.Newf("couldn't create transport: %w", err)
+		}
+
+		latest := *account
+		latest.HeaderRemoteURL = header
+		latest.HeaderMediaAttachmentID = ""
+		latest.HeaderMediaAttachment = nil
+
+		if err := n.dereferencer.FetchRemoteAccountHeader(ctx, tsport, account, &latest); err != nil {
+			log.Warnf(ctx, "Failed to fetch header %s: %s", header, err)
+		} else {
+			account.HeaderRemoteURL = latest.HeaderRemoteURL
+			account.HeaderMediaAttachmentID = latest.HeaderMediaAttachmentID
+			account.HeaderMediaAttachment = latest.HeaderMediaAttachment
+			columns = append(columns, "header_remote_url", "header_media_attachment_id")
+		}
+	}
+
+	if len(feedColumns) != 0 {
+		if err := n.UpdateRssFeed(ctx, feed, feedColumns...); err != nil {
+			return false, gtserror.Newf("couldn't update feed: %w", err)
+		}
+	}
+
+	if len(columns) == 0 && !slices.Contains(feedColumns, "media_type") {
+		return false, nil
+	}
+
+	if len(columns) != 0 {
+		if err := n.state.DB.UpdateAccount(ctx, account, columns...); err != nil {
+			return false, gtserror.Newf("couldn't update feed account: %w", err)
+		}
+	}
+
+	// send it to the client API worker, which federates the update.
+	n.state.Workers.Client.Queue.Push(&messages.FromClientAPI{
+		APObjectType:   ap.ActorPerson,
+		APActivityType: ap.ActivityUpdate,
+		GTSModel:       account,
+		Origin:         account,
+	})
+
+	return true, nil
+}
+
+// profileEdited returns whether a profile field of a feed account was edited
+// since the refresh last set it to previous. Fields set before their value was
+// tracked are considered unedited.
+func profileEdited(current string, previous string) bool {
+	return previous != "" && current != previous
+}
@@ -0,0 +1,89 @@
+package rss
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+type ProfileTestSuite struct {
+	RssStandardTestSuite
+}
+
+func (suite *ProfileTestSuite) TestRefreshProfile() {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_1"]
+
+	feed := &gtsmodel.RssFeed{
+		ID:        id.NewULID(),
+		AccountID: account.ID,
+		URL:       suite.fixtureURL("rss2.xml"),
+	}
+	if err := suite.tooter.PutRssFeed(ctx, feed); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	changed, err := suite.tooter.refreshProfile(ctx, feed)
+	suite.NoError(err)
+	suite.True(changed)
+
+	refreshed, err := suite.state.DB.GetAccountByID(ctx, account.ID)
+	suite.NoError(err)
+	suite.Equal("A Small Blog", refreshed.DisplayName)
+	suite.Contains(refreshed.Note, "Notes about gardening and bikes")
+	suite.Equal(suite.fixtureURL("icon-192.png"), refreshed.AvatarRemoteURL)
+	suite.NotEmpty(refreshed.AvatarMediaAttachmentID)
+	suite.Equal("application/rss+xml", feed.MediaType)
+
+	// Nothing changed since the previous refresh.
+	changed, err = suite.tooter.refreshProfile(ctx, feed)
+	suite.NoError(err)
+	suite.False(changed)
+}
+
+func (suite *ProfileTestSuite) TestRefreshProfileEdited() {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_1"]
+
+	// The display name was edited since the
+	// previous refresh, the note wasn't tracked.
+	feed := &gtsmodel.RssFeed{
+		ID:                 id.NewULID(),
+		AccountID:          account.ID,
+		URL:                suite.fixtureURL("rss2.xml"),
+		ProfileDisplayName: "A Small Blog",
+	}
+	if err := suite.tooter.PutRssFeed(ctx, feed); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	_, err := suite.tooter.refreshProfile(ctx, feed)
+	suite.NoError(err)
+
+	refreshed, err := suite.state.DB.GetAccountByID(ctx, account.ID)
+	suite.NoError(err)
+	suite.Equal(account.DisplayName, refreshed.DisplayName)
+	suite.Contains(refreshed.Note, "Notes about gardening and bikes")
+
+	stored, err := suite.tooter.GetRssFeedByAccountID(ctx, account.ID)
+	suite.NoError(err)
+	suite.Equal("A Small Blog", stored.ProfileDisplayName)
+	suite.Equal(refreshed.Note, stored.ProfileNote)
+}
+
+func (suite *ProfileTestSuite) TestRefreshProfileMissingFeed() {
+	ctx := context.Background()
+
+	_, err := suite.tooter.refreshProfile(ctx, &gtsmodel.RssFeed{
+		AccountID: suite.testAccounts["local_account_1"].ID,
+		URL:       suite.fixtureURL("missing.xml"),
+	})
+	suite.ErrorContains(err, "Invalid feed at")
+}
+
+func TestProfileTestSuite(t *testing.T) {
+	suite.Run(t, new(ProfileTestSuite))
+}
@@ -0,0 +1,662 @@
+package rss
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+// Paths the reader APIs are served at, given with app passwords.
+const (
+	GoogleReaderPath = "/api/greader"
+	FeverPath        = "/api/fever"
+)
+
+// Streams of the Google Reader API, also selecting the items of the Fever API.
+// Feed streams are followed by the id of their feed account, label streams by
+// the title of their list.
+const (
+	ReaderStreamReadingList = "user/-/state/com.google/reading-list"
+	ReaderStreamStarred     = "user/-/state/com.google/starred"
+	ReaderStreamRead        = "user/-/state/com.google/read"
+	ReaderStreamKeptUnread  = "user/-/state/com.google/kept-unread"
+	ReaderStreamFeed        = "feed/"
+	ReaderStreamLabel       = "user/-/label/"
+)
+
+// ReaderMaxItems is the maximum number of items returned at once to reader apps.
+const ReaderMaxItems = 1000
+
+// readerTitleMaxRunes is the length of the titles made from
+// the text of statuses which don't start with one.
+const readerTitleMaxRunes = 80
+
+// ReaderSubscription is a feed account followed by the owner of an app password.
+type ReaderSubscription struct {
+	Account *gtsmodel.Account
+	Feed    *gtsmodel.RssFeed
+	Lists   []*gtsmodel.List // lists of the owner including the feed account
+}
+
+// ReaderItem is a status of a subscription, as seen by reader apps.
+type ReaderItem struct {
+	ID      int64            // see ReaderItemID
+	Status  *gtsmodel.Status // the status of the timeline, maybe a boost
+	Title   string           // the title of the feed item
+	Content string           // the html content of the status, without its title
+	Read    bool             // the status is not newer than the home timeline marker
+	Starred bool             // the status is bookmarked
+}
+
+// ReaderQuery selects the items of a stream.
+type ReaderQuery struct {
+	Stream string // one of the ReaderStream streams
+	Unread bool   // leave out the read items
+	MaxID  string // only items older than this status id
+	MinID  string // only items newer than this status id
+	Oldest bool   // oldest items first
+	Limit  int
+}
+
+// ReaderItemCount is the number of items of a subscription.
+type ReaderItemCount struct {
+	AccountID string
+	Count     int
+	NewestID  string
+}
+
+// ReaderItemID returns the integer id of the item of a status, as reader apps
+// need: the milliseconds of the status ULID followed by 15 bits of its entropy.
+// Item ids are ordered like statuses, and lead back to them with readerIDRange.
+func ReaderItemID(statusID string) int64 {
+	parsed, err := ulid.Parse(statusID)
+	if err != nil {
+		return 0
+	}
+
+	entropy := parsed.Entropy()
+	return int64(parsed.Time())<<15 | int64(entropy[0])<<7 | int64(entropy[1]>>1)
+}
+
+// readerIDRange returns the lowest and highest ULIDs having the item id itemID.
+func readerIDRange(itemID int64) (string, string, error) {
+	if itemID <= 0 {
+		return "", "", fmt.Errorf("invalid item id %d", itemID)
+	}
+
+	var low, high ulid.ULID
+	if err := low.SetTime(uint64(itemID >> 15)); err != nil {
+		return "", "", err
+	}
+	high = low
+
+	lowEntropy := make([]byte, 10)
+	lowEntropy[0] = byte(itemID >> 7)
+	lowEntropy[1] = byte(itemID&0x7f) << 1
+	highEntropy := bytes.Repeat([]byte{0xff}, 10)
+	highEntropy[0] = lowEntropy[0]
+	highEntropy[1] = lowEntropy[1] | 1
+
+	if err := low.SetEntropy(lowEntropy); err != nil {
+		return "", "", err
+	}
+	if err := high.SetEntropy(highEntropy); err != nil {
+		return "", "", err
+	}
+
+	return low.String(), high.String(), nil
+}
+
+// ReaderItemIDBounds returns the status ids bounding the items newer than
+// sinceID and older than maxID, empty for the ids which are not set.
+func ReaderItemIDBounds(sinceID int64, maxID int64) (string, string) {
+	var minStatusID, maxStatusID string
+	if sinceID > 0 {
+		_, minStatusID, _ = readerIDRange(sinceID)
+	}
+	if maxID > 0 {
+		maxStatusID, _, _ = readerIDRange(maxID)
+	}
+	return minStatusID, maxStatusID
+}
+
+// ReaderIDTime returns the time a status was received at, from its id.
+func ReaderIDTime(statusID string) time.Time {
+	parsed, err := ulid.Parse(statusID)
+	if err != nil {
+		return time.Time{}
+	}
+	return ulid.Time(parsed.Time())
+}
+
+// ReaderTimeID returns the highest status id of the statuses received
+// at t, or now if t is in the future, to select the items received since
+// or mark the items received before as read.
+func ReaderTimeID(t time.Time) string {
+	if now := time.Now(); t.After(now) {
+		t = now
+	}
+
+	var timeID ulid.ULID
+	if err := timeID.SetTime(ulid.Timestamp(t)); err != nil {
+		return id.Lowest
+	}
+	if err := timeID.SetEntropy(bytes.Repeat([]byte{0xff}, 10)); err != nil {
+		return id.Lowest
+	}
+	return timeID.String()
+}
+
+// GetAppPasswords returns the app passwords of account.
+func (n *rssTooter) GetAppPasswords(ctx context.Context, account *gtsmodel.Account) ([]*apimodel.AppPassword, gtserror.WithCode) {
+	appPasswords, err := n.GetAppPasswordsByAccountID(ctx, account.ID)
+	if err != nil {
+		err := gtserror.Newf("db error getting app passwords: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiPasswords := make([]*apimodel.AppPassword, 0, len(appPasswords))
+	for _, appPassword := range appPasswords {
+		apiPasswords = append(apiPasswords, apiAppPassword(appPassword))
+	}
+
+	return apiPasswords, nil
+}
+
+// CreateAppPassword creates a new app password for account, returning
+// the password itself: it can't be read again once created.
+func (n *rssTooter) CreateAppPassword(ctx context.Context, account *gtsmodel.Account, form *apimodel.AppPasswordCreateRequest) (*apimodel.AppPassword, gtserror.WithCode) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		err := gtserror.Newf("error generating app password: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	password := feedTokenEncoding.EncodeToString(secret)
+
+	appPassword := &gtsmodel.AppPassword{
+		ID:           id.NewULID(),
+		CreatedAt:    time.Now(),
+		AccountID:    account.ID,
+		Account:      account,
+		Name:         strings.TrimSpace(form.Name),
+		PasswordHash: appPasswordHash(password),
+		FeverKeyHash: appPasswordHash(feverKey(account.Username, password)),
+	}
+
+	if err := n.PutAppPassword(ctx, appPassword); err != nil {
+		err := gtserror.Newf("db error putting app password: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// The password is only stored hashed, and only ever shown here.
+	apiPassword := apiAppPassword(appPassword)
+	apiPassword.Password = password
+	return apiPassword, nil
+}
+
+// DeleteAppPassword revokes an app password of account.
+func (n *rssTooter) DeleteAppPassword(ctx context.Context, account *gtsmodel.Account, passwordID string) gtserror.WithCode {
+	if err := n.DeleteAppPasswordByID(ctx, account.ID, passwordID); err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err := fmt.Errorf("app password %s not found", passwordID)
+			return gtserror.NewErrorNotFound(err, err.Error())
+		}
+		err := gtserror.Newf("db error deleting app password: %w", err)
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	return nil
+}
+
+// ReaderLogin returns the account logging in with the given username or
+// email address and app password, to the Google Reader API.
+func (n *rssTooter) ReaderLogin(ctx context.Context, login string, password string) (*gtsmodel.Account, gtserror.WithCode) {
+	appPassword, err := n.GetAppPasswordByPassword(ctx, password)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err := gtserror.Newf("db error getting app password: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if appPassword != nil {
+		user, account, errWithCode := n.tokenOwner(ctx, appPassword.AccountID)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+		if strings.EqualFold(login, account.Username) || strings.EqualFold(login, user.Email) {
+			return account, nil
+		}
+	}
+
+	err = errors.New("invalid login or app password")
+	return nil, gtserror.NewErrorUnauthorized(err, err.Error())
+}
+
+// ReaderAuth returns the account of the app password used as the auth token of the Google Reader API.
+func (n *rssTooter) ReaderAuth(ctx context.Context, token string) (*gtsmodel.Account, gtserror.WithCode) {
+	appPassword, err := n.GetAppPasswordByPassword(ctx, token)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err := errors.New("invalid auth token")
+			return nil, gtserror.NewErrorUnauthorized(err, err.Error())
+		}
+		err := gtserror.Newf("db error getting app password: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	_, account, errWithCode := n.tokenOwner(ctx, appPassword.AccountID)
+	return account, errWithCode
+}
+
+// FeverAuth returns the account of the app password of the given Fever API key.
+func (n *rssTooter) FeverAuth(ctx context.Context, apiKey string) (*gtsmodel.Account, gtserror.WithCode) {
+	appPassword, err := n.GetAppPasswordByFeverKey(ctx, strings.ToLower(apiKey))
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err := errors.New("invalid api key")
+			return nil, gtserror.NewErrorUnauthorized(err, err.Error())
+		}
+		err := gtserror.Newf("db error getting app password: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	_, account, errWithCode := n.tokenOwner(ctx, appPassword.AccountID)
+	return account, errWithCode
+}
+
+// ReaderSubscriptions returns the feed accounts followed by account, with the lists including them.
+func (n *rssTooter) ReaderSubscriptions(ctx context.Context, account *gtsmodel.Account) ([]*ReaderSubscription, gtserror.WithCode) {
+	feeds, err := n.GetFollowedRssFeeds(ctx, account.ID)
+	if err != nil {
+		err := gtserror.Newf("db error getting followed feeds: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	follows, err := n.state.DB.GetAccountFollows(ctx, account.ID, nil)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err := gtserror.Newf("db error getting follows: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	followed := make(map[string]string, len(follows))
+	for _, follow := range follows {
+		followed[follow.ID] = follow.TargetAccountID
+	}
+
+	lists, err := n.state.DB.GetListsForAccountID(ctx, account.ID)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err := gtserror.Newf("db error getting lists: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	accountLists := make(map[string][]*gtsmodel.List)
+	for _, list := range lists {
+		entries, err := n.state.DB.GetListEntries(ctx, list.ID, "", "", "", 0)
+		if err != nil && !errors.Is(err, db.ErrNoEntries) {
+			err := gtserror.Newf("db error getting list entries: %w", err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		for _, entry := range entries {
+			if targetID, ok := followed[entry.FollowID]; ok {
+				accountLists[targetID] = append(accountLists[targetID], list)
+			}
+		}
+	}
+
+	subscriptions := make([]*ReaderSubscription, 0, len(feeds))
+	for _, feed := range feeds {
+		feedAccount, err := n.state.DB.GetAccountByID(ctx, feed.AccountID)
+		if err != nil {
+			err := gtserror.Newf("db error getting feed account: %w", err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		subscriptions = append(subscriptions, &ReaderSubscription{
+			Account: feedAccount,
+			Feed:    feed,
+			Lists:   accountLists[feed.AccountID],
+		})
+	}
+
+	return subscriptions, nil
+}
+
+// ReaderSubscribe returns the feed account of the website or feed at
+// feedURL, to be followed, creating it if this instance has none yet.
+func (n *rssTooter) ReaderSubscribe(ctx context.Context, feedURL string) (*gtsmodel.Account, gtserror.WithCode) {
+	username, err := n.NewUser(ctx, feedURL)
+	if err != nil || username == "" {
+		err := fmt.Errorf("no feed found at %s: %v", feedURL, err)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	account, err := n.state.DB.GetAccountByUsernameDomain(ctx, username, "")
+	if err != nil {
+		err := gtserror.Newf("db error getting feed account: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return account, nil
+}
+
+// ReaderItems returns the items of the stream selected by query, visible to
+// account, with the status id to continue from for the next ones, if any.
+func (n *rssTooter) ReaderItems(ctx context.Context, account *gtsmodel.Account, query *ReaderQuery) ([]*ReaderItem, string, gtserror.WithCode) {
+	limit := query.Limit
+	if limit <= 0 || limit > ReaderMaxItems {
+		limit = ReaderMaxItems
+	}
+
+	lastReadID, errWithCode := n.readerLastReadID(ctx, account)
+	if errWithCode != nil {
+		return nil, "", errWithCode
+	}
+
+	minID := query.MinID
+	if query.Unread && lastReadID > minID {
+		minID = lastReadID
+	}
+
+	var (
+		statusIDs []string
+		err       error
+	)
+	if query.Stream == ReaderStreamStarred {
+		statusIDs, err = n.GetBookmarkedStatusIDs(ctx, account.ID, query.MaxID, minID, limit, query.Oldest)
+	} else {
+		accountIDs, errWithCode := n.readerStreamAccounts(ctx, account, query.Stream)
+		if errWithCode != nil {
+			return nil, "", errWithCode
+		}
+		statusIDs, err = n.GetStatusIDsOfAccounts(ctx, accountIDs, query.MaxID, minID, limit, query.Oldest)
+	}
+	if err != nil {
+		err := gtserror.Newf("db error getting stream statuses: %w", err)
+		return nil, "", gtserror.NewErrorInternalError(err)
+	}
+
+	var continuation string
+	if len(statusIDs) == limit {
+		continuation = statusIDs[len(statusIDs)-1]
+	}
+
+	items, errWithCode := n.readerItems(ctx, account, statusIDs, lastReadID)
+	return items, continuation, errWithCode
+}
+
+// ReaderItemsByIDs returns the items of the given ids visible to account.
+func (n *rssTooter) ReaderItemsByIDs(ctx context.Context, account *gtsmodel.Account, itemIDs []int64) ([]*ReaderItem, gtserror.WithCode) {
+	statusIDs := make([]string, 0, len(itemIDs))
+	for _, itemID := range itemIDs {
+		low, high, err := readerIDRange(itemID)
+		if err != nil {
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+
+		ids, err := n.GetStatusIDsBetween(ctx, low, high)
+		if err != nil {
+			err := gtserror.Newf("db error getting item statuses: %w", err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		statusIDs = append(statusIDs, ids...)
+	}
+
+	lastReadID, errWithCode := n.readerLastReadID(ctx, account)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return n.readerItems(ctx, account, statusIDs, lastReadID)
+}
+
+// ReaderItemCounts returns the number of items of each subscription of
+// account having some, or the number of unread ones if unread is set.
+func (n *rssTooter) ReaderItemCounts(ctx context.Context, account *gtsmodel.Account, unread bool) ([]*ReaderItemCount, gtserror.WithCode) {
+	accountIDs, errWithCode := n.readerStreamAccounts(ctx, account, ReaderStreamReadingList)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	var minID string
+	if unread {
+		minID, errWithCode = n.readerLastReadID(ctx, account)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+	}
+
+	counts, err := n.CountStatusesOfAccounts(ctx, accountIDs, minID)
+	if err != nil {
+		err := gtserror.Newf("db error counting statuses: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return counts, nil
+}
+
+// ReaderMarkRead returns the home timeline marker of account moved forward
+// to lastReadID, marking the items up to it as read, or nil if they already are.
+//
+// Reader apps keep the read state of each item, but markers only keep the
+// position of the newest item read: the older ones are marked read too.
+func (n *rssTooter) ReaderMarkRead(ctx context.Context, account *gtsmodel.Account, lastReadID string) (*gtsmodel.Marker, gtserror.WithCode) {
+	current, errWithCode := n.readerLastReadID(ctx, account)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if lastReadID <= current {
+		return nil, nil
+	}
+
+	return &gtsmodel.Marker{
+		AccountID:  account.ID,
+		Name:       gtsmodel.MarkerNameHome,
+		LastReadID: lastReadID,
+	}, nil
+}
+
+// ReaderMarkUnread returns the home timeline marker of account moved back
+// before statusID, marking its item as unread, or nil if it already is.
+// The newer items are marked unread too.
+func (n *rssTooter) ReaderMarkUnread(ctx context.Context, account *gtsmodel.Account, statusID string) (*gtsmodel.Marker, gtserror.WithCode) {
+	current, errWithCode := n.readerLastReadID(ctx, account)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if statusID > current {
+		return nil, nil
+	}
+
+	parsed, err := ulid.Parse(statusID)
+	if err != nil {
+		err := fmt.Errorf("invalid status id %s", statusID)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	// Decrement the 128 bits of the ULID.
+	for i := len(parsed) - 1; i >= 0; i-- {
+		parsed[i]--
+		if parsed[i] != 0xff {
+			break
+		}
+	}
+
+	return &gtsmodel.Marker{
+		AccountID:  account.ID,
+		Name:       gtsmodel.MarkerNameHome,
+		LastReadID: parsed.String(),
+	}, nil
+}
+
+// readerLastReadID returns the last read status id of the home timeline marker of account, empty if none.
+func (n *rssTooter) readerLastReadID(ctx context.Context, account *gtsmodel.Account) (string, gtserror.WithCode) {
+	marker, err := n.state.DB.GetMarker(ctx, account.ID, gtsmodel.MarkerNameHome)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			return "", nil
+		}
+		err := gtserror.Newf("db error getting marker: %w", err)
+		return "", gtserror.NewErrorInternalError(err)
+	}
+
+	return marker.LastReadID, nil
+}
+
+// readerStreamAccounts returns the ids of the feed accounts of a stream
+// followed by account: all of them for the reading list, those of a list
+// for its label, or the one of a feed.
+func (n *rssTooter) readerStreamAccounts(ctx context.Context, account *gtsmodel.Account, stream string) ([]string, gtserror.WithCode) {
+	subscriptions, errWithCode := n.ReaderSubscriptions(ctx, account)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	stream = ReaderStream(stream)
+	accountIDs := make([]string, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		switch {
+		case stream == ReaderStreamReadingList:
+		case stream == ReaderStreamFeed+subscription.Account.ID:
+		case strings.HasPrefix(stream, ReaderStreamLabel):
+			if !readerListsInclude(subscription.Lists, strings.TrimPrefix(stream, ReaderStreamLabel)) {
+				continue
+			}
+		default:
+			continue
+		}
+		accountIDs = append(accountIDs, subscription.Account.ID)
+	}
+
+	if stream != ReaderStreamReadingList && len(accountIDs) == 0 {
+		err := fmt.Errorf("stream %s not found", stream)
+		return nil, gtserror.NewErrorNotFound(err, err.Error())
+	}
+
+	return accountIDs, nil
+}
+
+// readerItems returns the items of the given statuses visible to account.
+func (n *rssTooter) readerItems(ctx context.Context, account *gtsmodel.Account, statusIDs []string, lastReadID string) ([]*ReaderItem, gtserror.WithCode) {
+	statuses, err := n.state.DB.GetStatusesByIDs(ctx, statusIDs)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err := gtserror.Newf("db error getting statuses: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	items := make([]*ReaderItem, 0, len(statuses))
+	for _, status := range statuses {
+		visible, err := n.visFilter.StatusVisible(ctx, account, status)
+		if err != nil {
+			err := gtserror.Newf("error checking status visibility: %w", err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		if !visible {
+			continue
+		}
+
+		content := status
+		if status.BoostOf != nil {
+			content = status.BoostOf
+		}
+
+		starred, err := n.state.DB.IsStatusBookmarkedBy(ctx, account.ID, content.ID)
+		if err != nil {
+			err := gtserror.Newf("db error checking bookmark: %w", err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		title, html := readerContent(content)
+		items = append(items, &ReaderItem{
+			ID:      ReaderItemID(status.ID),
+			Status:  status,
+			Title:   title,
+			Content: html,
+			Read:    lastReadID != "" && status.ID <= lastReadID,
+			Starred: starred,
+		})
+	}
+
+	return items, nil
+}
+
+// ReaderStream returns stream with the user of label and state streams
+// replaced by "-", as apps may use the id of the user instead.
+func ReaderStream(stream string) string {
+	if parts := strings.SplitN(stream, "/", 3); len(parts) == 3 && parts[0] == "user" {
+		return "user/-/" + parts[2]
+	}
+	return stream
+}
+
+// readerListsInclude returns whether lists include a list titled title.
+func readerListsInclude(lists []*gtsmodel.List, title string) bool {
+	for _, list := range lists {
+		if list.Title == title {
+			return true
+		}
+	}
+	return false
+}
+
+// readerContent returns the title of the item of a status, and its html content
+// without it. Feed statuses start with a paragraph holding the item title, the
+// others get their content warning or the beginning of their text.
+func readerContent(status *gtsmodel.Status) (string, string) {
+	content := status.Content
+	end := strings.Index(content, "</p>")
+	if end > 0 && (strings.HasPrefix(content, `<p><a href="`+status.URL+`">`) || (status.URL == "" && strings.HasPrefix(content, "<p>"))) {
+		return text.SanitizeToPlaintext(content[:end]), strings.TrimSpace(content[end+len("</p>"):])
+	}
+
+	if status.ContentWarning != "" {
+		return status.ContentWarning, content
+	}
+
+	title := []rune(strings.Join(strings.Fields(text.SanitizeToPlaintext(content)), " "))
+	if len(title) > readerTitleMaxRunes {
+		title = append(title[:readerTitleMaxRunes-1], '…')
+	}
+	return string(title), content
+}
+
+// appPasswordHash returns the hash of an app password, Fever API key or feed token
+// stored in the database. These being long random secrets, a fast unsalted hash is
+// enough, and lets them be looked up on each request of the Google Reader API or feed.
+func appPasswordHash(password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(sum[:])
+}
+
+// feverKey returns the Fever API key of an app password, the md5 of "username:password".
+func feverKey(username string, password string) string {
+	sum := md5.Sum([]byte(username + ":" + password))
+	return hex.EncodeToString(sum[:])
+}
+
+func apiAppPassword(appPassword *gtsmodel.AppPassword) *apimodel.AppPassword {
+	instanceURL := config.GetProtocol() + "://" + config.GetHost()
+	return &apimodel.AppPassword{
+		ID:              appPassword.ID,
+		CreatedAt:       util.FormatISO8601(appPassword.CreatedAt),
+		Name:            appPassword.Name,
+		GoogleReaderURL: instanceURL + GoogleReaderPath,
+		FeverURL:        instanceURL + FeverPath,
+	}
+}
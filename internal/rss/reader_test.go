@@ -0,0 +1,183 @@
+package rss
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+type ReaderTestSuite struct {
+	RssStandardTestSuite
+}
+
+// subscribe turns local_account_1, followed by the admin, into the feed account of fixture, polled once.
+func (suite *ReaderTestSuite) subscribe(fixture string) *gtsmodel.Account {
+	ctx := context.Background()
+
+	account, err := suite.state.DB.GetAccountByID(ctx, suite.testAccounts["local_account_1"].ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	if err := suite.tooter.PutRssFeed(ctx, &gtsmodel.RssFeed{
+		ID:        id.NewULID(),
+		AccountID: account.ID,
+		URL:       suite.fixtureURL(fixture),
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.tooter.poll(ctx)
+	return account
+}
+
+func (suite *ReaderTestSuite) TestAppPasswords() {
+	ctx := context.Background()
+	admin := suite.testAccounts["admin_account"]
+
+	appPassword, errWithCode := suite.tooter.CreateAppPassword(ctx, admin, &apimodel.AppPasswordCreateRequest{Name: "Reeder"})
+	suite.Nil(errWithCode)
+	suite.Equal("Reeder", appPassword.Name)
+	suite.Equal("http://localhost:8080/api/greader", appPassword.GoogleReaderURL)
+
+	account, errWithCode := suite.tooter.ReaderLogin(ctx, "admin", appPassword.Password)
+	suite.Nil(errWithCode)
+	suite.Equal(admin.ID, account.ID)
+
+	account, errWithCode = suite.tooter.ReaderLogin(ctx, suite.testUsers["admin_account"].Email, appPassword.Password)
+	suite.Nil(errWithCode)
+	suite.Equal(admin.ID, account.ID)
+
+	_, errWithCode = suite.tooter.ReaderLogin(ctx, "the_mighty_zork", appPassword.Password)
+	suite.Equal(http.StatusUnauthorized, errWithCode.Code())
+
+	account, errWithCode = suite.tooter.FeverAuth(ctx, feverKey("admin", appPassword.Password))
+	suite.Nil(errWithCode)
+	suite.Equal(admin.ID, account.ID)
+
+	// Passwords are stored hashed, and never listed.
+	stored, err := suite.tooter.GetAppPasswordByPassword(ctx, appPassword.Password)
+	suite.NoError(err)
+	suite.Equal(appPasswordHash(appPassword.Password), stored.PasswordHash)
+	suite.NotContains(stored.PasswordHash, appPassword.Password)
+	suite.Equal(appPasswordHash(feverKey("admin", appPassword.Password)), stored.FeverKeyHash)
+
+	appPasswords, errWithCode := suite.tooter.GetAppPasswords(ctx, admin)
+	suite.Nil(errWithCode)
+	suite.Len(appPasswords, 1)
+	suite.Empty(appPasswords[0].Password)
+
+	// App passwords are only deleted by their owner.
+	errWithCode = suite.tooter.DeleteAppPassword(ctx, suite.testAccounts["local_account_1"], appPassword.ID)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+
+	suite.Nil(suite.tooter.DeleteAppPassword(ctx, admin, appPassword.ID))
+	_, errWithCode = suite.tooter.ReaderAuth(ctx, appPassword.Password)
+	suite.Equal(http.StatusUnauthorized, errWithCode.Code())
+}
+
+func (suite *ReaderTestSuite) TestReaderItemID() {
+	statusID := id.NewULID()
+	itemID := ReaderItemID(statusID)
+	suite.Positive(itemID)
+
+	low, high, err := readerIDRange(itemID)
+	suite.NoError(err)
+	suite.LessOrEqual(low, statusID)
+	suite.GreaterOrEqual(high, statusID)
+	suite.Equal(itemID, ReaderItemID(low))
+	suite.Equal(itemID, ReaderItemID(high))
+
+	_, _, err = readerIDRange(0)
+	suite.Error(err)
+}
+
+func (suite *ReaderTestSuite) TestReaderItems() {
+	ctx := context.Background()
+	admin := suite.testAccounts["admin_account"]
+	feedAccount := suite.subscribe("rss2.xml")
+
+	subscriptions, errWithCode := suite.tooter.ReaderSubscriptions(ctx, admin)
+	suite.Nil(errWithCode)
+	suite.Len(subscriptions, 1)
+	suite.Equal(feedAccount.ID, subscriptions[0].Account.ID)
+
+	items, continuation, errWithCode := suite.tooter.ReaderItems(ctx, admin, &ReaderQuery{
+		Stream: ReaderStreamFeed + feedAccount.ID,
+		Limit:  2,
+	})
+	suite.Nil(errWithCode)
+	suite.Len(items, 2)
+	suite.Equal(items[1].Status.ID, continuation)
+	suite.Greater(items[0].Status.ID, items[1].Status.ID)
+	suite.False(items[0].Read)
+
+	tomatoes, err := suite.tooter.GetStatusByAccountURL(ctx, feedAccount.ID, "https://blog.example.org/2024/06/03/tomatoes-again/")
+	suite.NoError(err)
+	byID, errWithCode := suite.tooter.ReaderItemsByIDs(ctx, admin, []int64{ReaderItemID(tomatoes.ID)})
+	suite.Nil(errWithCode)
+	suite.Len(byID, 1)
+	suite.Equal(tomatoes.ID, byID[0].Status.ID)
+	suite.NotContains(byID[0].Content, "<a href=\""+tomatoes.URL+"\">")
+
+	// Marking the newest item read marks them all read.
+	marker, errWithCode := suite.tooter.ReaderMarkRead(ctx, admin, items[0].Status.ID)
+	suite.Nil(errWithCode)
+	suite.NoError(suite.state.DB.UpdateMarker(ctx, marker))
+
+	unread, _, errWithCode := suite.tooter.ReaderItems(ctx, admin, &ReaderQuery{Stream: ReaderStreamReadingList, Unread: true})
+	suite.Nil(errWithCode)
+	suite.Empty(unread)
+
+	counts, errWithCode := suite.tooter.ReaderItemCounts(ctx, admin, true)
+	suite.Nil(errWithCode)
+	suite.Empty(counts)
+
+	// Marking it unread again moves the marker just before it.
+	marker, errWithCode = suite.tooter.ReaderMarkUnread(ctx, admin, items[0].Status.ID)
+	suite.Nil(errWithCode)
+	suite.Less(marker.LastReadID, items[0].Status.ID)
+	suite.Greater(marker.LastReadID, items[1].Status.ID)
+	suite.NoError(suite.state.DB.UpdateMarker(ctx, marker))
+
+	unread, _, errWithCode = suite.tooter.ReaderItems(ctx, admin, &ReaderQuery{Stream: ReaderStreamReadingList, Unread: true})
+	suite.Nil(errWithCode)
+	suite.Len(unread, 1)
+	suite.Equal(items[0].Status.ID, unread[0].Status.ID)
+
+	// Items already read aren't marked read again.
+	marker, errWithCode = suite.tooter.ReaderMarkRead(ctx, admin, items[1].Status.ID)
+	suite.Nil(errWithCode)
+	suite.Nil(marker)
+}
+
+func (suite *ReaderTestSuite) TestReaderStreamNotFound() {
+	_, _, errWithCode := suite.tooter.ReaderItems(context.Background(), suite.testAccounts["admin_account"], &ReaderQuery{
+		Stream: ReaderStreamLabel + "nothing here",
+	})
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+}
+
+func (suite *ReaderTestSuite) TestReaderContent() {
+	title, html := readerContent(&gtsmodel.Status{
+		URL:     "https://blog.example.org/post",
+		Content: `<p><a href="https://blog.example.org/post">Tomatoes &amp; more</a></p><p>Text</p>`,
+	})
+	suite.Equal("Tomatoes & more", title)
+	suite.Equal("<p>Text</p>", html)
+
+	title, html = readerContent(&gtsmodel.Status{
+		URL:     "http://localhost:8080/@the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY",
+		Content: "<p>just a toot</p>",
+	})
+	suite.Equal("just a toot", title)
+	suite.Equal("<p>just a toot</p>", html)
+}
+
+func TestReaderTestSuite(t *testing.T) {
+	suite.Run(t, new(ReaderTestSuite))
+}
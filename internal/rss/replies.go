@@ -0,0 +1,142 @@
+package rss
+
+import (
+	"context"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/email"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+// replyForwardFrequency is how often new replies to feed statuses are forwarded.
+const replyForwardFrequency = time.Minute
+
+// replyWebhook is the JSON body POSTed to the reply webhook of a feed.
+type replyWebhook struct {
+	Event       string `json:"event"`
+	Feed        string `json:"feed"`
+	Item        string `json:"item"`
+	Account     string `json:"account"`
+	AccountURL  string `json:"account_url"`
+	URL         string `json:"url"`
+	CreatedAt   string `json:"created_at"`
+	SpoilerText string `json:"spoiler_text"`
+	Content     string `json:"content"`
+}
+
+// forwardReplies forwards the new replies to the statuses of every
+// feed having a reply webhook url or email address.
+//
+// Replies are found through the mention notifications of the feed
+// account, created the same way whether the reply was posted through
+// the client API or received through federation.
+func (n *rssTooter) forwardReplies(ctx context.Context, now time.Time) {
+	feeds, err := n.GetReplyForwardFeeds(ctx)
+	if err != nil {
+		log.Errorf(ctx, "Failed to retrieve reply forwarding feeds: %s", err)
+		return
+	}
+
+	for _, feed := range feeds {
+		if err := n.forwardFeedReplies(ctx, feed); err != nil {
+			log.Errorf(ctx, "Failed to forward replies of %s: %s", feed.URL, err)
+		}
+	}
+}
+
+// forwardFeedReplies forwards the replies received by the statuses of a feed
+// since the previous run. Only public and unlisted replies are forwarded:
+// the others are meant for the feed account and its delegates only.
+// Webhook deliveries are queued and retried, failed emails are not.
+func (n *rssTooter) forwardFeedReplies(ctx context.Context, feed *gtsmodel.RssFeed) error {
+	notifications, err := n.GetMentionsSince(ctx, feed.AccountID, feed.RepliesForwardedID)
+	if err != nil {
+		return gtserror.Newf("couldn't get mentions: %w", err)
+	}
+
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	for _, notification := range notifications {
+		reply := notification.Status
+		if reply == nil || reply.InReplyToAccountID != feed.AccountID || reply.AccountID == feed.AccountID ||
+			(reply.Visibility != gtsmodel.VisibilityPublic && reply.Visibility != gtsmodel.VisibilityUnlocked) {
+			continue
+		}
+
+		// Notification statuses are barebones: the author and
+		// the replied status are needed to forward the reply.
+		if err := n.state.DB.PopulateStatus(ctx, reply); err != nil {
+			log.Warnf(ctx, "Failed to populate reply %s: %s", reply.URI, err)
+		}
+		if reply.Account != nil && reply.InReplyTo != nil {
+			n.forwardReply(ctx, feed, reply)
+		}
+	}
+
+	feed.RepliesForwardedID = notifications[len(notifications)-1].ID
+	return n.UpdateRssFeed(ctx, feed, "replies_forwarded_id")
+}
+
+// forwardReply enqueues a reply to the webhook and / or emails it to the address of the feed.
+func (n *rssTooter) forwardReply(ctx context.Context, feed *gtsmodel.RssFeed, reply *gtsmodel.Status) {
+	account := "@" + reply.Account.Username
+	if reply.Account.Domain != "" {
+		account += "@" + reply.Account.Domain
+	} else {
+		account += "@" + config.GetAccountDomain()
+	}
+
+	replyUrl := reply.URL
+	if replyUrl == "" {
+		replyUrl = reply.URI
+	}
+
+	if feed.ReplyWebhookURL != "" {
+		if err := n.enqueueReplyWebhook(ctx, feed, reply, replyWebhook{
+			Event:       webhookEventReplyCreated,
+			Feed:        feed.URL,
+			Item:        reply.InReplyTo.URL,
+			Account:     account,
+			AccountURL:  reply.Account.URL,
+			URL:         replyUrl,
+			CreatedAt:   util.FormatISO8601(reply.CreatedAt),
+			SpoilerText: reply.ContentWarning,
+			Content:     reply.Content,
+		}); err != nil {
+			log.Errorf(ctx, "Failed to enqueue reply %s to webhook of %s: %s", reply.URI, feed.URL, err)
+		}
+	}
+
+	if feed.ReplyEmail != "" {
+		instance, err := n.state.DB.GetInstance(ctx, config.GetHost())
+		if err != nil {
+			log.Errorf(ctx, "Failed to get instance: %s", err)
+			return
+		}
+
+		feedName := feed.URL
+		if feedAccount, err := n.state.DB.GetAccountByID(ctx, feed.AccountID); err == nil && feedAccount.DisplayName != "" {
+			feedName = feedAccount.DisplayName
+		}
+
+		if err := n.emailSender.SendFeedReplyEmail(feed.ReplyEmail, email.FeedReplyData{
+			InstanceURL:  instance.URI,
+			InstanceName: instance.Title,
+			FeedName:     feedName,
+			ItemURL:      reply.InReplyTo.URL,
+			ReplyAccount: account,
+			ReplyURL:     replyUrl,
+			ReplySpoiler: reply.ContentWarning,
+			ReplyText:    text.SanitizeToPlaintext(reply.Content),
+		}); err != nil {
+			log.Warnf(ctx, "Failed to email reply %s to owner of %s: %s", reply.URI, feed.URL, err)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package rss
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type RepliesTestSuite struct {
+	RssStandardTestSuite
+}
+
+// putReply stores a reply of admin to the first status of
+// local_account_1, and the mention notification it creates.
+func (suite *RepliesTestSuite) putReply(visibility gtsmodel.Visibility) *gtsmodel.Status {
+	ctx := context.Background()
+	admin := suite.testAccounts["admin_account"]
+	item := testrig.NewTestStatuses()["local_account_1_status_1"]
+
+	statusID := id.NewULID()
+	reply := &gtsmodel.Status{
+		ID:                  statusID,
+		URI:                 admin.URI + "/statuses/" + statusID,
+		URL:                 admin.URL + "/statuses/" + statusID,
+		Content:             "<p>Nice post!</p>",
+		Local:               util.Ptr(true),
+		CreatedAt:           time.Now(),
+		UpdatedAt:           time.Now(),
+		AccountID:           admin.ID,
+		AccountURI:          admin.URI,
+		InReplyToID:         item.ID,
+		InReplyToURI:        item.URI,
+		InReplyToAccountID:  item.AccountID,
+		ThreadID:            item.ThreadID,
+		Visibility:          visibility,
+		ActivityStreamsType: ap.ObjectNote,
+		Federated:           util.Ptr(true),
+		Boostable:           util.Ptr(true),
+		Replyable:           util.Ptr(true),
+		Likeable:            util.Ptr(true),
+	}
+	if err := suite.state.DB.PutStatus(ctx, reply); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	if err := suite.state.DB.PutNotification(ctx, &gtsmodel.Notification{
+		ID:               id.NewULID(),
+		NotificationType: gtsmodel.NotificationMention,
+		TargetAccountID:  item.AccountID,
+		OriginAccountID:  admin.ID,
+		StatusID:         reply.ID,
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	return reply
+}
+
+func (suite *RepliesTestSuite) TestForwardPublicRepliesOnly() {
+	ctx := context.Background()
+
+	feed := &gtsmodel.RssFeed{
+		ID:                 id.NewULID(),
+		AccountID:          suite.testAccounts["local_account_1"].ID,
+		URL:                suite.fixtureURL("rss2.xml"),
+		ReplyEmail:         "owner@example.org",
+		RepliesForwardedID: id.NewULID(),
+	}
+	if err := suite.tooter.PutRssFeed(ctx, feed); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// Followers-only and direct replies are left out.
+	suite.putReply(gtsmodel.VisibilityFollowersOnly)
+	suite.putReply(gtsmodel.VisibilityDirect)
+	if err := suite.tooter.forwardFeedReplies(ctx, feed); err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Empty(suite.sentEmails)
+
+	reply := suite.putReply(gtsmodel.VisibilityUnlocked)
+	if err := suite.tooter.forwardFeedReplies(ctx, feed); err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Contains(suite.sentEmails["owner@example.org"], reply.URL)
+}
+
+func (suite *RepliesTestSuite) TestForwardRepliesToWebhook() {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_1"]
+
+	receiver := newWebhookReceiver()
+	defer receiver.Close()
+
+	if err := suite.tooter.PutRssFeed(ctx, &gtsmodel.RssFeed{
+		ID:        id.NewULID(),
+		AccountID: account.ID,
+		URL:       suite.fixtureURL("rss2.xml"),
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+	apiFeed, errWithCode := suite.tooter.UpdateFeed(ctx, suite.testUsers["admin_account"], account.ID, &apimodel.RssFeedUpdateRequest{
+		ReplyWebhookURL: util.Ptr(receiver.URL),
+	})
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+	suite.NotEmpty(apiFeed.ReplyWebhookSecret)
+	receiver.set(apiFeed.ReplyWebhookSecret, http.StatusNoContent)
+
+	feed, err := suite.tooter.GetRssFeedByAccountID(ctx, account.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	reply := suite.putReply(gtsmodel.VisibilityPublic)
+	if err := suite.tooter.forwardFeedReplies(ctx, feed); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// Replies are queued, then delivered signed like new statuses.
+	suite.Empty(receiver.receivedReplies())
+	suite.tooter.deliverWebhooks(ctx, time.Now())
+
+	if received := receiver.receivedReplies(); suite.Len(received, 1) {
+		suite.Equal(webhookEventReplyCreated, received[0].Event)
+		suite.Equal(reply.URL, received[0].URL)
+		suite.Equal("@admin@localhost:8080", received[0].Account)
+	}
+	deliveries, err := suite.state.DB.DB().NewSelect().
+		TableExpr("rss_webhook_deliveries").
+		Where("status_id = ?", reply.ID).
+		Count(ctx)
+	suite.NoError(err)
+	suite.Zero(deliveries)
+}
+
+func TestRepliesTestSuite(t *testing.T) {
+	suite.Run(t, new(RepliesTestSuite))
+}
@@ -0,0 +1,81 @@
+package rss
+
+import (
+	"context"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+)
+
+// retentionFrequency is how often feeds are checked for statuses past their retention.
+const retentionFrequency = time.Hour
+
+// retentionBatchSize is the maximum number of statuses deleted from a feed at once,
+// so that a new retention doesn't flood the client API worker: the rest go next time.
+const retentionBatchSize = 200
+
+// applyRetention deletes the statuses of every feed past its retention.
+func (n *rssTooter) applyRetention(ctx context.Context, now time.Time) {
+	feeds, err := n.GetRssFeeds(ctx)
+	if err != nil {
+		log.Errorf(ctx, "Failed to retrieve feeds: %s", err)
+		return
+	}
+
+	for _, feed := range feeds {
+		settings := resolveSettings(feed)
+		if settings.RetentionDays <= 0 && settings.RetentionItems <= 0 {
+			continue
+		}
+
+		if err := n.deleteExpiredStatuses(ctx, feed, settings, now); err != nil {
+			log.Errorf(ctx, "Failed to apply retention of %s: %s", feed.URL, err)
+		}
+	}
+}
+
+// deleteExpiredStatuses deletes the item statuses of a feed older than its retention days
+// or most recent items, through the client API worker, as if the feed account deleted them:
+// they are removed from the timelines and the deletes federated. Their attachments are
+// unattached, and removed from the storage by the next media cleanup.
+func (n *rssTooter) deleteExpiredStatuses(ctx context.Context, feed *gtsmodel.RssFeed, settings statusSettings, now time.Time) error {
+	var before time.Time
+	if settings.RetentionDays > 0 {
+		before = now.AddDate(0, 0, -settings.RetentionDays)
+	}
+
+	statusIDs, err := n.GetExpiredStatusIDs(ctx, feed.AccountID, before, settings.RetentionItems, retentionBatchSize)
+	if err != nil {
+		return gtserror.Newf("couldn't get expired statuses: %w", err)
+	}
+	if len(statusIDs) == 0 {
+		return nil
+	}
+
+	account, err := n.state.DB.GetAccountByID(ctx, feed.AccountID)
+	if err != nil {
+		return gtserror.Newf("couldn't get feed account: %w", err)
+	}
+
+	statuses, err := n.state.DB.GetStatusesByIDs(ctx, statusIDs)
+	if err != nil {
+		return gtserror.Newf("couldn't get expired statuses: %w", err)
+	}
+
+	for _, status := range statuses {
+		n.state.Workers.Client.Queue.Push(&messages.FromClientAPI{
+			APObjectType:   ap.ObjectNote,
+			APActivityType: ap.ActivityDelete,
+			GTSModel:       status,
+			Origin:         account,
+			Target:         account,
+		})
+	}
+
+	log.Infof(ctx, "Deleting %d statuses of %s past their retention", len(statuses), feed.URL)
+	return nil
+}
@@ -0,0 +1,130 @@
+package rss
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type RetentionTestSuite struct {
+	RssStandardTestSuite
+}
+
+// pollFeed turns local_account_1 into the feed account of rss2.xml, polled once, and drops the queued side effects.
+func (suite *RetentionTestSuite) pollFeed() *gtsmodel.RssFeed {
+	ctx := context.Background()
+
+	feed := &gtsmodel.RssFeed{
+		ID:        id.NewULID(),
+		AccountID: suite.testAccounts["local_account_1"].ID,
+		URL:       suite.fixtureURL("rss2.xml"),
+	}
+	if err := suite.tooter.PutRssFeed(ctx, feed); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.tooter.poll(ctx)
+	for _, ok := suite.state.Workers.Client.Queue.Pop(); ok; _, ok = suite.state.Workers.Client.Queue.Pop() {
+	}
+
+	return feed
+}
+
+func (suite *RetentionTestSuite) TestGetExpiredStatusIDs() {
+	ctx := context.Background()
+	feed := suite.pollFeed()
+
+	tomatoes, err := suite.tooter.GetStatusByAccountURL(ctx, feed.AccountID, "https://blog.example.org/2024/06/03/tomatoes-again/")
+	suite.NoError(err)
+	relative, err := suite.tooter.GetStatusByAccountURL(ctx, feed.AccountID, suite.fixtureURL("2024/05/28/relative/"))
+	suite.NoError(err)
+
+	// Nothing expires without a retention.
+	expired, err := suite.tooter.GetExpiredStatusIDs(ctx, feed.AccountID, time.Time{}, 0, retentionBatchSize)
+	suite.NoError(err)
+	suite.Empty(expired)
+
+	expired, err = suite.tooter.GetExpiredStatusIDs(ctx, feed.AccountID, tomatoes.CreatedAt.Add(-time.Hour), 0, retentionBatchSize)
+	suite.NoError(err)
+	suite.Contains(expired, relative.ID)
+	suite.NotContains(expired, tomatoes.ID)
+
+	// The most recent status is kept.
+	expired, err = suite.tooter.GetExpiredStatusIDs(ctx, feed.AccountID, time.Time{}, 1, retentionBatchSize)
+	suite.NoError(err)
+	suite.Contains(expired, relative.ID)
+	suite.NotContains(expired, tomatoes.ID)
+
+	// Only the statuses of feed items expire, not the ones
+	// the account posted otherwise, like its testrig statuses.
+	all, err := suite.tooter.GetExpiredStatusIDs(ctx, feed.AccountID, time.Now().Add(time.Hour), 0, retentionBatchSize)
+	suite.NoError(err)
+	suite.Subset(all, expired)
+	for _, status := range testrig.NewTestStatuses() {
+		if status.AccountID == feed.AccountID {
+			suite.NotContains(all, status.ID)
+		}
+	}
+
+	statuses, err := suite.state.DB.GetStatusesByIDs(ctx, expired)
+	suite.NoError(err)
+	for _, status := range statuses {
+		suite.True(status.PinnedAt.IsZero())
+		faved, err := suite.state.DB.IsStatusFavedBy(ctx, status.ID, suite.testAccounts["admin_account"].ID)
+		suite.NoError(err)
+		suite.False(faved)
+	}
+
+	// Bookmarked statuses are kept.
+	if err := suite.state.DB.PutStatusBookmark(ctx, &gtsmodel.StatusBookmark{
+		ID:              id.NewULID(),
+		AccountID:       suite.testAccounts["admin_account"].ID,
+		TargetAccountID: feed.AccountID,
+		StatusID:        relative.ID,
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	expired, err = suite.tooter.GetExpiredStatusIDs(ctx, feed.AccountID, time.Time{}, 1, retentionBatchSize)
+	suite.NoError(err)
+	suite.NotContains(expired, relative.ID)
+}
+
+func (suite *RetentionTestSuite) TestApplyRetention() {
+	ctx := context.Background()
+	feed := suite.pollFeed()
+
+	// Without any retention, nothing is deleted.
+	suite.tooter.applyRetention(ctx, time.Now())
+	suite.Zero(suite.state.Workers.Client.Queue.Len())
+
+	feed.RetentionItems = util.Ptr(1)
+	if err := suite.tooter.UpdateRssFeed(ctx, feed, "retention_items"); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	expired, err := suite.tooter.GetExpiredStatusIDs(ctx, feed.AccountID, time.Time{}, 1, retentionBatchSize)
+	suite.NoError(err)
+	suite.NotEmpty(expired)
+
+	suite.tooter.applyRetention(ctx, time.Now())
+
+	deleted := []string{}
+	for msg, ok := suite.state.Workers.Client.Queue.Pop(); ok; msg, ok = suite.state.Workers.Client.Queue.Pop() {
+		suite.Equal(ap.ActivityDelete, msg.APActivityType)
+		suite.Equal(feed.AccountID, msg.Origin.ID)
+		deleted = append(deleted, msg.GTSModel.(*gtsmodel.Status).ID)
+	}
+	suite.ElementsMatch(expired, deleted)
+}
+
+func TestRetentionTestSuite(t *testing.T) {
+	suite.Run(t, new(RetentionTestSuite))
+}
@@ -0,0 +1,110 @@
+package rss
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/filter/visibility"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+// fixtureModTime is the Last-Modified date of every fixture.
+var fixtureModTime = time.Date(2024, time.June, 4, 12, 0, 0, 0, time.UTC)
+
+// RssStandardTestSuite runs an rssTooter against the testrig sqlite database,
+// fetching the fixtures of testdata from a local server instead of the network.
+type RssStandardTestSuite struct {
+	suite.Suite
+	state  state.State
+	server *httptest.Server
+	tooter *rssTooter
+
+	testAccounts map[string]*gtsmodel.Account
+	testUsers    map[string]*gtsmodel.User
+	sentEmails   map[string]string
+}
+
+func (suite *RssStandardTestSuite) SetupSuite() {
+	suite.testAccounts = testrig.NewTestAccounts()
+	suite.testUsers = testrig.NewTestUsers()
+}
+
+func (suite *RssStandardTestSuite) SetupTest() {
+	suite.state.Caches.Init()
+	testrig.StartNoopWorkers(&suite.state)
+
+	testrig.InitTestConfig()
+	testrig.InitTestLog()
+
+	suite.state.DB = testrig.NewTestDB(&suite.state)
+	suite.state.Storage = testrig.NewInMemoryStorage()
+
+	converter := typeutils.NewConverter(&suite.state)
+	visFilter := visibility.NewFilter(&suite.state)
+	testrig.StartTimelines(&suite.state, visFilter, converter)
+
+	mediaManager := testrig.NewTestMediaManager(&suite.state)
+	transportController := testrig.NewTestTransportController(&suite.state, testrig.NewMockHTTPClient(nil, "../../testrig/media"))
+	suite.sentEmails = make(map[string]string)
+	emailSender := testrig.NewEmailSender("../../web/template/", suite.sentEmails)
+
+	suite.server = httptest.NewServer(http.HandlerFunc(serveFixture))
+	suite.tooter = NewRssTooter(context.Background(), &suite.state, mediaManager, transportController, converter, visFilter, emailSender).(*rssTooter)
+	suite.tooter.httpclient = suite.server.Client()
+	suite.tooter.fetcher = NewFetcher(suite.server.Client())
+
+	testrig.StandardDBSetup(suite.state.DB, nil)
+}
+
+func (suite *RssStandardTestSuite) TearDownTest() {
+	suite.tooter.Stop()
+	suite.server.Close()
+	testrig.StandardDBTeardown(suite.state.DB)
+	testrig.StopWorkers(&suite.state)
+}
+
+// fixtureURL returns the url the given fixture is served at.
+func (suite *RssStandardTestSuite) fixtureURL(name string) string {
+	return suite.server.URL + "/" + name
+}
+
+// serveFixture serves the files of testdata, with an ETag and Last-Modified
+// header so conditional requests get a 304. The root serves page.html, and
+// files below /gzip/ are served gzip encoded.
+func serveFixture(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	gzipped := strings.HasPrefix(name, "gzip/")
+	name = strings.TrimPrefix(name, "gzip/")
+	if name == "" {
+		name = "page.html"
+	}
+
+	data, err := os.ReadFile(path.Join("testdata", path.Clean("/"+name)))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if gzipped {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write(data)
+		gz.Close()
+		data = buf.Bytes()
+		w.Header().Set("Content-Encoding", "gzip")
+	}
+
+	w.Header().Set("Etag", `"`+name+`-v1"`)
+	http.ServeContent(w, r, name, fixtureModTime, bytes.NewReader(data))
+}
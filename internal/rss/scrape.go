@@ -0,0 +1,359 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	netUrl "net/url"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/htmlquery"
+	"github.com/mmcdole/gofeed"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+	"golang.org/x/net/html"
+)
+
+// scrapedFeedType is the type of the feeds read from a web page by a scraper.
+const scrapedFeedType = "html"
+
+// scrapedDateLayouts are the layouts tried, in order, to parse the date of scraped items.
+var scrapedDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC822Z,
+	time.RFC822,
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+}
+
+// selector selects nodes in a html document, or in one of its elements.
+type selector func(node *html.Node) []*html.Node
+
+// CreateScrapedFeed creates the account of a website without any feed,
+// whose items are read from a web page with selectors, if requester is an admin.
+func (n *rssTooter) CreateScrapedFeed(ctx context.Context, requester *gtsmodel.User, form *apimodel.RssScrapedFeedRequest) (*apimodel.RssFeed, gtserror.WithCode) {
+	if !*requester.Admin {
+		err := fmt.Errorf("user %s not allowed to create scraped feeds", requester.ID)
+		return nil, gtserror.NewErrorForbidden(err, err.Error())
+	}
+
+	pageUrl, scraper, errWithCode := scrapedFeedForm(form)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	username := feedUsername(pageUrl)
+	available, err := n.state.DB.IsUsernameAvailable(ctx, username)
+	if err != nil {
+		err := gtserror.Newf("db error checking username: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	} else if !available {
+		err := fmt.Errorf("a feed account already exists for %s", pageUrl)
+		return nil, gtserror.NewErrorConflict(err, err.Error())
+	}
+
+	rssFeed, err := LoadScrapedFeed(ctx, n.fetcher, pageUrl, scraper)
+	if err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	} else if len(rssFeed.Feed.Items) == 0 {
+		err := fmt.Errorf("selectors match no item on %s", pageUrl)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+	rssFeed.DbUsername = username
+
+	feed, err := n.createFeedAccount(ctx, rssFeed, scraper)
+	if err != nil {
+		err := gtserror.Newf("error creating scraped feed account: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return n.apiFeed(ctx, feed), nil
+}
+
+// PreviewScrapedFeed returns the items read from a web page with selectors, without creating
+// anything, if requester is an admin. The page is the saved html of the form, if any.
+func (n *rssTooter) PreviewScrapedFeed(ctx context.Context, requester *gtsmodel.User, form *apimodel.RssScrapedFeedRequest) ([]*apimodel.RssScrapedItem, gtserror.WithCode) {
+	if !*requester.Admin {
+		err := fmt.Errorf("user %s not allowed to preview scraped feeds", requester.ID)
+		return nil, gtserror.NewErrorForbidden(err, err.Error())
+	}
+
+	pageUrl, scraper, errWithCode := scrapedFeedForm(form)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	var doc *html.Node
+	var err error
+	if form.HTML != "" {
+		doc, err = htmlquery.Parse(strings.NewReader(form.HTML))
+	} else {
+		doc, err = n.fetcher.FetchHTML(ctx, pageUrl.String())
+	}
+	if err != nil {
+		err := fmt.Errorf("failed to load html of %s: %w", pageUrl, err)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	feed, err := scrapeDoc(pageUrl, doc, scraper)
+	if err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	items := make([]*apimodel.RssScrapedItem, 0, len(feed.Items))
+	for _, item := range feed.Items {
+		var date *string
+		if item.PublishedParsed != nil {
+			date = util.Ptr(util.FormatISO8601(*item.PublishedParsed))
+		}
+		items = append(items, &apimodel.RssScrapedItem{
+			Title:   item.Title,
+			Link:    item.Link,
+			Date:    date,
+			Summary: item.Description,
+		})
+	}
+
+	return items, nil
+}
+
+// LoadScrapedFeed fetches the web page at pageUrl, and reads
+// its items with scraper. Items without a date are dated now.
+func LoadScrapedFeed(ctx context.Context, fetcher Fetcher, pageUrl *netUrl.URL, scraper *gtsmodel.RssScraper) (*rssFeed, error) {
+	doc, err := fetcher.FetchHTML(ctx, pageUrl.String())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load HTML from %s: %s", pageUrl, err)
+	}
+
+	feed, err := scrapeDoc(pageUrl, doc, scraper)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, item := range feed.Items {
+		if item.PublishedParsed == nil {
+			item.PublishedParsed = &now
+		}
+	}
+
+	return &rssFeed{
+		BaseUrl: pageUrl,
+		Doc:     doc,
+		FeedUrl: pageUrl,
+		Feed:    feed,
+	}, nil
+}
+
+// scrapeFeed reads the items of the web page of a scraped feed, leaving out
+// the ones already published by account, as they may have no date to tell,
+// even if their status was deleted since, for instance past its retention.
+func (n *rssTooter) scrapeFeed(ctx context.Context, account *gtsmodel.Account, pageUrl string, scraper *gtsmodel.RssScraper) (*HTTPFeed, error) {
+	parsed, err := netUrl.Parse(pageUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	rssFeed, err := LoadScrapedFeed(ctx, n.fetcher, parsed, scraper)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*gofeed.Item, 0, len(rssFeed.Feed.Items))
+	for _, item := range rssFeed.Feed.Items {
+		ingested, err := n.IsRssItemIngested(ctx, account.ID, item.Link)
+		if err != nil {
+			return nil, gtserror.Newf("couldn't get ingested item: %w", err)
+		}
+		if ingested {
+			continue
+		}
+		items = append(items, item)
+	}
+	rssFeed.Feed.Items = items
+
+	return &HTTPFeed{Feed: rssFeed.Feed}, nil
+}
+
+// scrapeDoc returns the feed of the items selected by scraper in the
+// page doc at pageUrl. Items without link are left out, as they
+// couldn't be told apart from one poll to the next.
+func scrapeDoc(pageUrl *netUrl.URL, doc *html.Node, scraper *gtsmodel.RssScraper) (*gofeed.Feed, error) {
+	selectors := make(map[string]selector)
+	for name, expr := range map[string]string{
+		"items":   scraper.Items,
+		"title":   scraper.Title,
+		"link":    scraper.Link,
+		"date":    scraper.Date,
+		"summary": scraper.Summary,
+	} {
+		if expr == "" {
+			continue
+		}
+		sel, err := compileSelector(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s selector %q: %w", name, expr, err)
+		}
+		selectors[name] = sel
+	}
+
+	if selectors["items"] == nil {
+		return nil, errors.New("missing items selector")
+	}
+
+	feed := &gofeed.Feed{
+		Title:    pageUrl.Host,
+		Link:     pageUrl.String(),
+		FeedType: scrapedFeedType,
+	}
+	if node := htmlquery.FindOne(doc, "//head/title"); node != nil {
+		if title := strings.TrimSpace(htmlquery.InnerText(node)); title != "" {
+			feed.Title = title
+		}
+	}
+	if node := htmlquery.FindOne(doc, "//head/meta[@name='description']"); node != nil {
+		feed.Description = strings.TrimSpace(htmlquery.SelectAttr(node, "content"))
+	}
+
+	for _, container := range selectors["items"](doc) {
+		item := &gofeed.Item{
+			Title: html.EscapeString(selectText(container, selectors["title"])),
+			Link:  selectLink(pageUrl, container, selectors["link"]),
+		}
+		if item.Link == "" {
+			continue
+		}
+		item.GUID = item.Link
+
+		if summary := selectText(container, selectors["summary"]); summary != "" {
+			item.Description = html.EscapeString(summary)
+		}
+
+		if sel := selectors["date"]; sel != nil {
+			item.PublishedParsed = selectDate(container, sel)
+		}
+
+		feed.Items = append(feed.Items, item)
+	}
+
+	return feed, nil
+}
+
+// compileSelector compiles an XPath expression, starting with "/",
+// "." or "(", or else a CSS selector.
+func compileSelector(expr string) (selector, error) {
+	if strings.HasPrefix(expr, "/") || strings.HasPrefix(expr, ".") || strings.HasPrefix(expr, "(") {
+		if _, err := htmlquery.QueryAll(&html.Node{Type: html.DocumentNode}, expr); err != nil {
+			return nil, err
+		}
+		return func(node *html.Node) []*html.Node {
+			nodes, _ := htmlquery.QueryAll(node, expr)
+			return nodes
+		}, nil
+	}
+
+	sel, err := cascadia.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return sel.MatchAll, nil
+}
+
+// selectText returns the text of the first node selected in container, empty if none.
+func selectText(container *html.Node, sel selector) string {
+	if sel == nil {
+		return ""
+	}
+	nodes := sel(container)
+	if len(nodes) == 0 {
+		return ""
+	}
+	return strings.Join(strings.Fields(htmlquery.InnerText(nodes[0])), " ")
+}
+
+// selectLink returns the href of the first link selected in container, or of
+// the container or its first link if there is no selector, resolved against pageUrl.
+func selectLink(pageUrl *netUrl.URL, container *html.Node, sel selector) string {
+	var node *html.Node
+	switch {
+	case sel != nil:
+		if nodes := sel(container); len(nodes) > 0 {
+			node = nodes[0]
+		}
+	case htmlquery.SelectAttr(container, "href") != "":
+		node = container
+	default:
+		node = htmlquery.FindOne(container, ".//a[@href]")
+	}
+	if node == nil {
+		return ""
+	}
+
+	// The link may be the selected element or one of its descendants.
+	href := htmlquery.SelectAttr(node, "href")
+	if href == "" {
+		if link := htmlquery.FindOne(node, ".//a[@href]"); link != nil {
+			href = htmlquery.SelectAttr(link, "href")
+		}
+	}
+	return resolveCardURL(pageUrl, href)
+}
+
+// selectDate returns the date of the first node selected in container, read from
+// its datetime or content attribute or its text, nil if none or not a date.
+func selectDate(container *html.Node, sel selector) *time.Time {
+	nodes := sel(container)
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	value := htmlquery.SelectAttr(nodes[0], "datetime")
+	if value == "" {
+		value = htmlquery.SelectAttr(nodes[0], "content")
+	}
+	if value == "" {
+		value = strings.Join(strings.Fields(htmlquery.InnerText(nodes[0])), " ")
+	}
+
+	for _, layout := range scrapedDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return &date
+		}
+	}
+	return nil
+}
+
+// scrapedFeedForm returns the page url and the scraper of a scraped feed form.
+func scrapedFeedForm(form *apimodel.RssScrapedFeedRequest) (*netUrl.URL, *gtsmodel.RssScraper, gtserror.WithCode) {
+	pageUrl, err := netUrl.Parse(form.URL)
+	if err != nil || (pageUrl.Scheme != "http" && pageUrl.Scheme != "https") {
+		err := fmt.Errorf("invalid page url %q", form.URL)
+		return nil, nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if form.Items == "" || form.Title == "" {
+		err := errors.New("items and title selectors are required")
+		return nil, nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	return pageUrl, &gtsmodel.RssScraper{
+		Items:   form.Items,
+		Title:   form.Title,
+		Link:    form.Link,
+		Date:    form.Date,
+		Summary: form.Summary,
+	}, nil
+}
@@ -0,0 +1,218 @@
+package rss
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+type ScrapeTestSuite struct {
+	RssStandardTestSuite
+}
+
+func (suite *ScrapeTestSuite) scrapeFixture(scraper *gtsmodel.RssScraper) []string {
+	file, err := os.Open("testdata/blog.html")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	defer file.Close()
+
+	doc, err := htmlquery.Parse(file)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	pageUrl, _ := url.Parse("https://handmade.example.org/blog/")
+	feed, err := scrapeDoc(pageUrl, doc, scraper)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal("A Handmade Blog", feed.Title)
+	suite.Equal("Written by hand, without any feed", feed.Description)
+	suite.Equal(scrapedFeedType, feed.FeedType)
+
+	// The item without a link is left out.
+	suite.Len(feed.Items, 2)
+	suite.Equal("2024-06-02T10:00:00Z", feed.Items[0].PublishedParsed.UTC().Format("2006-01-02T15:04:05Z"))
+	suite.Equal("2024-05-30T00:00:00Z", feed.Items[1].PublishedParsed.UTC().Format("2006-01-02T15:04:05Z"))
+
+	lines := []string{}
+	for _, item := range feed.Items {
+		lines = append(lines, item.Title+"|"+item.Link+"|"+item.Description)
+	}
+	return lines
+}
+
+func (suite *ScrapeTestSuite) TestScrapeCSS() {
+	suite.Equal([]string{
+		"Building kites|https://handmade.example.org/posts/kites.html|Paper &amp; bamboo.",
+		"Baking bread|https://handmade.example.org/blog/posts/bread.html|Sourdough, again.",
+	}, suite.scrapeFixture(&gtsmodel.RssScraper{
+		Items:   "article.post",
+		Title:   "h2",
+		Date:    "time",
+		Summary: "p",
+	}))
+}
+
+func (suite *ScrapeTestSuite) TestScrapeXPath() {
+	suite.Equal([]string{
+		"Building kites|https://handmade.example.org/posts/kites.html|",
+		"Baking bread|https://handmade.example.org/blog/posts/bread.html|",
+	}, suite.scrapeFixture(&gtsmodel.RssScraper{
+		Items: "//article[@class='post']",
+		Title: ".//h2",
+		Link:  ".//h2/a",
+		Date:  ".//time",
+	}))
+}
+
+func (suite *ScrapeTestSuite) TestScrapeInvalidSelector() {
+	pageUrl, _ := url.Parse("https://handmade.example.org/blog/")
+	doc, _ := htmlquery.Parse(strings.NewReader("<html></html>"))
+
+	_, err := scrapeDoc(pageUrl, doc, &gtsmodel.RssScraper{Items: "article[", Title: "h2"})
+	suite.ErrorContains(err, "invalid items selector")
+
+	_, err = scrapeDoc(pageUrl, doc, &gtsmodel.RssScraper{Items: "//article[", Title: "h2"})
+	suite.ErrorContains(err, "invalid items selector")
+}
+
+func (suite *ScrapeTestSuite) TestPreviewScrapedFeed() {
+	ctx := context.Background()
+	form := &apimodel.RssScrapedFeedRequest{
+		RssScraper: apimodel.RssScraper{Items: "li", Title: "a"},
+		URL:        "https://handmade.example.org/",
+		HTML:       `<ul><li><a href="/one">One</a></li><li><a href="/two">Two</a> <time>June 3, 2024</time></li></ul>`,
+	}
+
+	_, errWithCode := suite.tooter.PreviewScrapedFeed(ctx, suite.testUsers["local_account_1"], form)
+	suite.Equal(http.StatusForbidden, errWithCode.Code())
+
+	items, errWithCode := suite.tooter.PreviewScrapedFeed(ctx, suite.testUsers["admin_account"], form)
+	suite.Nil(errWithCode)
+	suite.Len(items, 2)
+	suite.Equal("One", items[0].Title)
+	suite.Equal("https://handmade.example.org/one", items[0].Link)
+	suite.Nil(items[0].Date)
+
+	form.Date = "time"
+	items, errWithCode = suite.tooter.PreviewScrapedFeed(ctx, suite.testUsers["admin_account"], form)
+	suite.Nil(errWithCode)
+	suite.Nil(items[0].Date)
+	suite.Equal("2024-06-03T00:00:00.000Z", *items[1].Date)
+
+	form.Title = ""
+	_, errWithCode = suite.tooter.PreviewScrapedFeed(ctx, suite.testUsers["admin_account"], form)
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+}
+
+func (suite *ScrapeTestSuite) TestCreateScrapedFeed() {
+	ctx := context.Background()
+	form := &apimodel.RssScrapedFeedRequest{
+		RssScraper: apimodel.RssScraper{Items: "article.post", Title: "h2", Date: "time"},
+		URL:        suite.fixtureURL("blog.html"),
+	}
+
+	_, errWithCode := suite.tooter.CreateScrapedFeed(ctx, suite.testUsers["local_account_1"], form)
+	suite.Equal(http.StatusForbidden, errWithCode.Code())
+
+	// A page where the selectors match nothing isn't a feed.
+	form.Items = "section.post"
+	_, errWithCode = suite.tooter.CreateScrapedFeed(ctx, suite.testUsers["admin_account"], form)
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+
+	form.Items = "article.post"
+	feed, errWithCode := suite.tooter.CreateScrapedFeed(ctx, suite.testUsers["admin_account"], form)
+	suite.Nil(errWithCode)
+	account, err := suite.state.DB.GetAccountByID(ctx, feed.AccountID)
+	suite.NoError(err)
+	suite.Equal("A Handmade Blog", account.DisplayName)
+	suite.Equal(suite.fixtureURL("blog.html"), feed.URL)
+	suite.Equal(&apimodel.RssScraper{Items: "article.post", Title: "h2", Date: "time"}, feed.Scraper)
+
+	_, errWithCode = suite.tooter.CreateScrapedFeed(ctx, suite.testUsers["admin_account"], form)
+	suite.Equal(http.StatusConflict, errWithCode.Code())
+}
+
+func (suite *ScrapeTestSuite) TestPollScrapedFeed() {
+	ctx := context.Background()
+
+	account, err := suite.state.DB.GetAccountByID(ctx, suite.testAccounts["local_account_1"].ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	if err := suite.tooter.PutRssFeed(ctx, &gtsmodel.RssFeed{
+		ID:        id.NewULID(),
+		AccountID: account.ID,
+		URL:       suite.fixtureURL("blog.html"),
+		Scraper:   &gtsmodel.RssScraper{Items: "article.post", Title: "h2"},
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.tooter.poll(ctx)
+
+	kites, err := suite.tooter.GetStatusByAccountURL(ctx, account.ID, suite.fixtureURL("posts/kites.html"))
+	suite.NoError(err)
+	suite.Contains(kites.Content, "Building kites")
+
+	statuses, err := suite.tooter.GetStatusesSince(ctx, account.ID, "")
+	suite.NoError(err)
+
+	// The undated items already published aren't published again.
+	suite.tooter.poll(ctx)
+
+	again, err := suite.tooter.GetStatusesSince(ctx, account.ID, "")
+	suite.NoError(err)
+	suite.Len(again, len(statuses))
+
+	// Nor are the ones whose status was deleted, for instance past its retention.
+	if err := suite.state.DB.DeleteStatusByID(ctx, kites.ID); err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.tooter.poll(ctx)
+
+	_, err = suite.tooter.GetStatusByAccountURL(ctx, account.ID, suite.fixtureURL("posts/kites.html"))
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
+func (suite *ScrapeTestSuite) TestPollScrapedFeedOlderItems() {
+	ctx := context.Background()
+
+	account, err := suite.state.DB.GetAccountByID(ctx, suite.testAccounts["local_account_1"].ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	if err := suite.tooter.PutRssFeed(ctx, &gtsmodel.RssFeed{
+		ID:         id.NewULID(),
+		AccountID:  account.ID,
+		URL:        suite.fixtureURL("blog.html"),
+		Scraper:    &gtsmodel.RssScraper{Items: "article.post", Title: "h2", Date: "time"},
+		LastItemAt: time.Now(),
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// New items are published whatever their date.
+	suite.tooter.poll(ctx)
+
+	kites, err := suite.tooter.GetStatusByAccountURL(ctx, account.ID, suite.fixtureURL("posts/kites.html"))
+	suite.NoError(err)
+	suite.Equal(2024, kites.CreatedAt.Year())
+}
+
+func TestScrapeTestSuite(t *testing.T) {
+	suite.Run(t, new(ScrapeTestSuite))
+}
@@ -0,0 +1,343 @@
+package rss
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+	"github.com/superseriousbusiness/gotosocial/internal/validate"
+)
+
+// GetFeed returns the feed of the given feed account, if requester is allowed to manage it.
+func (n *rssTooter) GetFeed(ctx context.Context, requester *gtsmodel.User, accountID string) (*apimodel.RssFeed, gtserror.WithCode) {
+	feed, errWithCode := n.getManagedFeed(ctx, requester, accountID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return n.apiFeed(ctx, feed), nil
+}
+
+// UpdateFeed updates the settings of the given feed account, if requester is allowed to manage it.
+func (n *rssTooter) UpdateFeed(ctx context.Context, requester *gtsmodel.User, accountID string, form *apimodel.RssFeedUpdateRequest) (*apimodel.RssFeed, gtserror.WithCode) {
+	feed, errWithCode := n.getManagedFeed(ctx, requester, accountID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	var columns []string
+
+	if form.Digest != nil {
+		digest := gtsmodel.RssDigest(*form.Digest)
+		if digest != gtsmodel.RssDigestNone && digest.Period() == 0 {
+			err := fmt.Errorf("invalid digest period %s", *form.Digest)
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+
+		if digest != feed.Digest {
+			// Only items ingested from now on
+			// will be part of the first digest.
+			feed.Digest = digest
+			feed.DigestedAt = time.Now()
+			feed.DigestLastStatusID = id.NewULID()
+			columns = append(columns, "digest", "digested_at", "digest_last_status_id")
+		}
+	}
+
+	if form.Visibility.Set {
+		feed.Visibility = ""
+		if v := util.PtrValueOr(form.Visibility.Value, ""); v != "" {
+			visibility := typeutils.APIVisToVis(apimodel.Visibility(v))
+			if !validVisibility(visibility) {
+				err := fmt.Errorf("invalid visibility %s", v)
+				return nil, gtserror.NewErrorBadRequest(err, err.Error())
+			}
+			feed.Visibility = visibility
+		}
+		columns = append(columns, "visibility")
+	}
+
+	if form.LocalOnly.Set {
+		feed.Federated = nil
+		if form.LocalOnly.Value != nil {
+			feed.Federated = util.Ptr(!*form.LocalOnly.Value)
+		}
+		columns = append(columns, "federated")
+	}
+
+	if form.Sensitive.Set {
+		feed.Sensitive = form.Sensitive.Value
+		columns = append(columns, "sensitive")
+	}
+
+	if form.SpoilerText.Set {
+		feed.ContentWarning = form.SpoilerText.Value
+		columns = append(columns, "content_warning")
+	}
+
+	if form.SpoilerFromTitle.Set {
+		feed.ContentWarningFromTitle = form.SpoilerFromTitle.Value
+		columns = append(columns, "content_warning_from_title")
+	}
+
+	if form.Boostable.Set {
+		feed.Boostable = form.Boostable.Value
+		columns = append(columns, "boostable")
+	}
+
+	if form.Likeable.Set {
+		feed.Likeable = form.Likeable.Value
+		columns = append(columns, "likeable")
+	}
+
+	if form.Replyable.Set {
+		feed.Replyable = form.Replyable.Value
+		columns = append(columns, "replyable")
+	}
+
+	if form.ReplyWebhookURL != nil {
+		if *form.ReplyWebhookURL != "" {
+			if u, err := url.Parse(*form.ReplyWebhookURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				err := fmt.Errorf("invalid reply webhook url %s", *form.ReplyWebhookURL)
+				return nil, gtserror.NewErrorBadRequest(err, err.Error())
+			}
+		}
+		feed.ReplyWebhookURL = *form.ReplyWebhookURL
+		columns = append(columns, "reply_webhook_url")
+
+		if feed.ReplyWebhookURL != "" && feed.ReplyWebhookSecret == "" {
+			secret := make([]byte, 20)
+			if _, err := rand.Read(secret); err != nil {
+				err := gtserror.Newf("error generating reply webhook secret: %w", err)
+				return nil, gtserror.NewErrorInternalError(err)
+			}
+			feed.ReplyWebhookSecret = feedTokenEncoding.EncodeToString(secret)
+			columns = append(columns, "reply_webhook_secret")
+		}
+	}
+
+	if form.ReplyEmail != nil {
+		if *form.ReplyEmail != "" {
+			if err := validate.Email(*form.ReplyEmail); err != nil {
+				return nil, gtserror.NewErrorBadRequest(err, err.Error())
+			}
+		}
+		feed.ReplyEmail = *form.ReplyEmail
+		columns = append(columns, "reply_email")
+	}
+
+	if form.Byline.Set {
+		feed.Byline = form.Byline.Value
+		columns = append(columns, "byline")
+	}
+
+	if form.AuthorHandles != nil {
+		for author, handle := range form.AuthorHandles {
+			if _, _, err := util.ExtractNamestringParts(handle); err != nil || author == "" {
+				err := fmt.Errorf("invalid handle %s of author %s", handle, author)
+				return nil, gtserror.NewErrorBadRequest(err, err.Error())
+			}
+		}
+		feed.AuthorHandles = form.AuthorHandles
+		columns = append(columns, "author_handles")
+	}
+
+	if form.RetentionDays.Set {
+		if util.PtrValueOr(form.RetentionDays.Value, 0) < 0 {
+			err := fmt.Errorf("invalid retention days %d", *form.RetentionDays.Value)
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+		feed.RetentionDays = form.RetentionDays.Value
+		columns = append(columns, "retention_days")
+	}
+
+	if form.RetentionItems.Set {
+		if util.PtrValueOr(form.RetentionItems.Value, 0) < 0 {
+			err := fmt.Errorf("invalid retention items %d", *form.RetentionItems.Value)
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+		feed.RetentionItems = form.RetentionItems.Value
+		columns = append(columns, "retention_items")
+	}
+
+	if feed.RepliesForwardedID == "" && (feed.ReplyWebhookURL != "" || feed.ReplyEmail != "") {
+		// Only replies received from now
+		// on will be forwarded.
+		feed.RepliesForwardedID = id.NewULID()
+		columns = append(columns, "replies_forwarded_id")
+	}
+
+	if len(columns) > 0 {
+		if err := n.UpdateRssFeed(ctx, feed, columns...); err != nil {
+			err := gtserror.Newf("db error updating feed: %w", err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+	}
+
+	return n.apiFeed(ctx, feed), nil
+}
+
+// getManagedFeed fetches the feed of a feed account, checking that requester
+// is allowed to manage it: admins manage every feed, delegates their own.
+func (n *rssTooter) getManagedFeed(ctx context.Context, requester *gtsmodel.User, accountID string) (*gtsmodel.RssFeed, gtserror.WithCode) {
+	if !*requester.Admin {
+		delegate, err := n.state.DB.IsRssFeedDelegate(ctx, accountID, requester.AccountID)
+		if err != nil {
+			err := gtserror.Newf("db error checking feed delegate: %w", err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		if !delegate {
+			err := fmt.Errorf("user %s not allowed to manage feed account %s", requester.ID, accountID)
+			return nil, gtserror.NewErrorForbidden(err, err.Error())
+		}
+	}
+
+	feed, err := n.GetRssFeedByAccountID(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err := fmt.Errorf("account %s is not a feed account", accountID)
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		err := gtserror.Newf("db error getting feed: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return feed, nil
+}
+
+// statusSettings are the settings applied to the statuses of a feed,
+// resolved from the feed record and the server-wide defaults.
+type statusSettings struct {
+	Visibility              gtsmodel.Visibility
+	Federated               bool
+	Sensitive               bool
+	ContentWarning          string
+	ContentWarningFromTitle bool
+	Boostable               bool
+	Likeable                bool
+	Replyable               bool
+	Byline                  bool
+	RetentionDays           int
+	RetentionItems          int
+}
+
+// resolveSettings returns the status settings of a feed, which may be nil.
+func resolveSettings(feed *gtsmodel.RssFeed) statusSettings {
+	settings := statusSettings{
+		Visibility:              typeutils.APIVisToVis(apimodel.Visibility(config.GetRssDefaultVisibility())),
+		Federated:               !config.GetRssDefaultLocalOnly(),
+		Sensitive:               config.GetRssDefaultSensitive(),
+		ContentWarning:          config.GetRssDefaultContentWarning(),
+		ContentWarningFromTitle: config.GetRssDefaultContentWarningFromTitle(),
+		Boostable:               config.GetRssDefaultBoostable(),
+		Likeable:                config.GetRssDefaultLikeable(),
+		Replyable:               config.GetRssDefaultReplyable(),
+		Byline:                  config.GetRssDefaultByline(),
+		RetentionDays:           config.GetRssDefaultRetentionDays(),
+		RetentionItems:          config.GetRssDefaultRetentionItems(),
+	}
+
+	if feed == nil {
+		return settings
+	}
+
+	if feed.Visibility != "" {
+		settings.Visibility = feed.Visibility
+	}
+	settings.Federated = util.PtrValueOr(feed.Federated, settings.Federated)
+	settings.Sensitive = util.PtrValueOr(feed.Sensitive, settings.Sensitive)
+	settings.ContentWarning = util.PtrValueOr(feed.ContentWarning, settings.ContentWarning)
+	settings.ContentWarningFromTitle = util.PtrValueOr(feed.ContentWarningFromTitle, settings.ContentWarningFromTitle)
+	settings.Boostable = util.PtrValueOr(feed.Boostable, settings.Boostable)
+	settings.Likeable = util.PtrValueOr(feed.Likeable, settings.Likeable)
+	settings.Replyable = util.PtrValueOr(feed.Replyable, settings.Replyable)
+	settings.Byline = util.PtrValueOr(feed.Byline, settings.Byline)
+	settings.RetentionDays = util.PtrValueOr(feed.RetentionDays, settings.RetentionDays)
+	settings.RetentionItems = util.PtrValueOr(feed.RetentionItems, settings.RetentionItems)
+
+	return settings
+}
+
+// apply sets the settings on a status created from item, or on a digest status if item is nil.
+func (s statusSettings) apply(status *gtsmodel.Status, item *gofeed.Item) {
+	status.Visibility = s.Visibility
+	status.Federated = util.Ptr(s.Federated)
+	status.Boostable = util.Ptr(s.Boostable)
+	status.Likeable = util.Ptr(s.Likeable)
+	status.Replyable = util.Ptr(s.Replyable)
+
+	status.ContentWarning = s.ContentWarning
+	if s.ContentWarningFromTitle && item != nil && item.Title != "" {
+		status.ContentWarning = item.Title
+	}
+
+	status.Sensitive = util.Ptr(s.Sensitive || status.ContentWarning != "")
+}
+
+// validVisibility returns whether feed statuses can be created with the given visibility.
+func validVisibility(visibility gtsmodel.Visibility) bool {
+	switch visibility {
+	case gtsmodel.VisibilityPublic,
+		gtsmodel.VisibilityUnlocked,
+		gtsmodel.VisibilityFollowersOnly:
+		return true
+	default:
+		return false
+	}
+}
+
+func (n *rssTooter) apiFeed(ctx context.Context, feed *gtsmodel.RssFeed) *apimodel.RssFeed {
+	var lastItemAt *string
+	if !feed.LastItemAt.IsZero() {
+		lastItemAt = util.Ptr(util.FormatISO8601(feed.LastItemAt))
+	}
+
+	settings := resolveSettings(feed)
+
+	var scraper *apimodel.RssScraper
+	if feed.Scraper != nil {
+		scraper = &apimodel.RssScraper{
+			Items:   feed.Scraper.Items,
+			Title:   feed.Scraper.Title,
+			Link:    feed.Scraper.Link,
+			Date:    feed.Scraper.Date,
+			Summary: feed.Scraper.Summary,
+		}
+	}
+
+	return &apimodel.RssFeed{
+		AccountID:          feed.AccountID,
+		URL:                feed.URL,
+		LastItemAt:         lastItemAt,
+		Digest:             string(feed.Digest),
+		Visibility:         n.converter.VisToAPIVis(ctx, settings.Visibility),
+		LocalOnly:          !settings.Federated,
+		Sensitive:          settings.Sensitive,
+		SpoilerText:        settings.ContentWarning,
+		SpoilerFromTitle:   settings.ContentWarningFromTitle,
+		Boostable:          settings.Boostable,
+		Likeable:           settings.Likeable,
+		Replyable:          settings.Replyable,
+		ReplyWebhookURL:    feed.ReplyWebhookURL,
+		ReplyWebhookSecret: feed.ReplyWebhookSecret,
+		ReplyEmail:         feed.ReplyEmail,
+		Byline:             settings.Byline,
+		AuthorHandles:      feed.AuthorHandles,
+		Scraper:            scraper,
+		RetentionDays:      settings.RetentionDays,
+		RetentionItems:     settings.RetentionItems,
+	}
+}
@@ -0,0 +1,88 @@
+package rss
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+type SettingsTestSuite struct {
+	RssStandardTestSuite
+}
+
+// updateFeed updates the settings of a feed as the admin, from a JSON request body.
+func (suite *SettingsTestSuite) updateFeed(accountID string, body string) (*apimodel.RssFeed, int) {
+	form := &apimodel.RssFeedUpdateRequest{}
+	if err := json.Unmarshal([]byte(body), form); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	feed, errWithCode := suite.tooter.UpdateFeed(context.Background(), suite.testUsers["admin_account"], accountID, form)
+	if errWithCode != nil {
+		return nil, errWithCode.Code()
+	}
+	return feed, http.StatusOK
+}
+
+func (suite *SettingsTestSuite) TestUpdateFeedReset() {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_2"]
+	if err := suite.tooter.PutRssFeed(ctx, &gtsmodel.RssFeed{
+		ID:        id.NewULID(),
+		AccountID: account.ID,
+		URL:       suite.fixtureURL("rss2.xml"),
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	defaults, code := suite.updateFeed(account.ID, `{}`)
+	suite.Equal(http.StatusOK, code)
+
+	feed, code := suite.updateFeed(account.ID, `{"visibility":"private","sensitive":true,"spoiler_text":"","retention_days":3}`)
+	suite.Equal(http.StatusOK, code)
+	suite.Equal(apimodel.VisibilityPrivate, feed.Visibility)
+	suite.True(feed.Sensitive)
+	suite.Equal(3, feed.RetentionDays)
+
+	// Settings left out are unchanged, the others
+	// are reset to the server default.
+	feed, code = suite.updateFeed(account.ID, `{"visibility":"","sensitive":null,"spoiler_text":null}`)
+	suite.Equal(http.StatusOK, code)
+	suite.Equal(defaults.Visibility, feed.Visibility)
+	suite.Equal(defaults.Sensitive, feed.Sensitive)
+	suite.Equal(3, feed.RetentionDays)
+
+	stored, err := suite.tooter.GetRssFeedByAccountID(ctx, account.ID)
+	if suite.NoError(err) {
+		suite.Empty(stored.Visibility)
+		suite.Nil(stored.Sensitive)
+		suite.Nil(stored.ContentWarning)
+		suite.Equal(3, *stored.RetentionDays)
+	}
+
+	_, code = suite.updateFeed(account.ID, `{"visibility":"direct"}`)
+	suite.Equal(http.StatusBadRequest, code)
+}
+
+func (suite *SettingsTestSuite) TestRssSettingParam() {
+	var setting apimodel.RssSetting[int]
+	suite.NoError(setting.UnmarshalParam("7"))
+	suite.True(setting.Set)
+	suite.Equal(7, *setting.Value)
+
+	suite.NoError(setting.UnmarshalParam(""))
+	suite.True(setting.Set)
+	suite.Nil(setting.Value)
+
+	suite.Error(setting.UnmarshalParam("seven"))
+}
+
+func TestSettingsTestSuite(t *testing.T) {
+	suite.Run(t, new(SettingsTestSuite))
+}
@@ -2,6 +2,7 @@ package rss
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -9,6 +10,8 @@ import (
 	"codeberg.org/gruf/go-kv"
 	"github.com/antchfx/htmlquery"
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/id"
@@ -35,13 +38,67 @@ func createMediaAttachement(ctx context.Context, text string) []*gtsmodel.MediaA
 		}
 	}
 
-	log.Infof(ctx, "Attachments: ", attachments)
+	log.Infof(ctx, "Attachments: %v", attachments)
 
 
 	return attachments
 }
 
 func (n *rssTooter) PutStatus(ctx context.Context, toCreate *ToCreate) error {
+	newStatus, err := n.storeItem(ctx, toCreate)
+	if err != nil || newStatus == nil {
+		return err
+	}
+
+	// send it back to the client API worker for async side-effects.
+	n.state.Workers.Client.Queue.Push(&messages.FromClientAPI{
+		APObjectType:   ap.ObjectNote,
+		APActivityType: ap.ActivityCreate,
+		GTSModel:       newStatus,
+		Origin:         toCreate.Account,
+	})
+
+	return nil
+}
+
+// storeItem stores the status of a feed item with createStatus, and enqueues
+// its webhook deliveries unless backfilled, without sending it to the client
+// API worker. It returns no status for the articles already posted,
+// boosted or skipped by boostDuplicate.
+func (n *rssTooter) storeItem(ctx context.Context, toCreate *ToCreate) (*gtsmodel.Status, error) {
+	// Articles already posted by other feeds are boosted instead.
+	if config.GetRssDeduplicate() && !toCreate.Comment && toCreate.Item.Link != "" {
+		if toCreate.ArticleURL == "" {
+			toCreate.ArticleURL = n.resolveArticleURL(ctx, toCreate.page())
+		}
+		done, err := n.boostDuplicate(ctx, toCreate)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return nil, nil
+		}
+	}
+
+	newStatus, err := n.createStatus(ctx, toCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Comments are replies in a thread, not new items of the feed,
+	// and backfilled items are the past of the feed, not news.
+	if !toCreate.Comment && !toCreate.Backfill {
+		if err := n.enqueueWebhooks(ctx, toCreate, newStatus); err != nil {
+			log.Errorf(ctx, "Failed to enqueue webhooks of %s: %s", toCreate.Item.Link, err)
+		}
+	}
+
+	return newStatus, nil
+}
+
+// createStatus converts a feed item to a status and stores it,
+// without triggering any side effect (timelines, federation...).
+func (n *rssTooter) createStatus(ctx context.Context, toCreate *ToCreate) (*gtsmodel.Status, error) {
 	l := log.WithFields(kv.Fields{
 		{ K: "ID", V: toCreate.Account.ID,},
 		{ K: "item", V: toCreate.Item.Link,},
@@ -50,7 +107,7 @@ func (n *rssTooter) PutStatus(ctx context.Context, toCreate *ToCreate) error {
 	// Pre-fetch a transport for requesting username, used by later dereferencing.
 	tsport, err := n.transportController.NewTransportForUsername(ctx, toCreate.Account.Username)
 	if err != nil {
-		return gtserror.Newf("couldn't create transport: %w", err)
+		return nil, gtserror.Newf("couldn't create transport: %w", err)
 	}
 
 	accountURIs := uris.GenerateURIsForAccount(toCreate.Account.Username)
@@ -63,8 +120,26 @@ func (n *rssTooter) PutStatus(ctx context.Context, toCreate *ToCreate) error {
 		text = toCreate.Item.Content
 	}
 
+	// Visibility, content warning and interaction
+	// policy come from the feed, or server defaults.
+	feed, err := n.GetRssFeedByAccountID(ctx, toCreate.Account.ID)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return nil, gtserror.Newf("couldn't get feed: %w", err)
+	}
+	settings := resolveSettings(feed)
+
+	var byline string
+	var mentions []*gtsmodel.Mention
+	if settings.Byline {
+		byline, mentions = n.byline(ctx, toCreate.Account, statusId, feed, toCreate.Item, toCreate.page())
+	}
+
 	attachments := createMediaAttachement(ctx, text)
-	content := fmt.Sprintf(`<p><a href="%s">%s</a></p><p>%s</p>`, toCreate.Item.Link, toCreate.Item.Title, text)
+	content := fmt.Sprintf(`<p><a href="%s">%s</a></p>%s<p>%s</p>`, toCreate.Item.Link, toCreate.Item.Title, byline, text)
+	if toCreate.Item.Link == "" {
+		// Newsletter messages have no page.
+		content = fmt.Sprintf(`<p>%s</p>%s%s`, toCreate.Item.Title, byline, text)
+	}
 
 	newStatus := &gtsmodel.Status{
 		ID:                       statusId,
@@ -80,36 +155,87 @@ func (n *rssTooter) PutStatus(ctx context.Context, toCreate *ToCreate) error {
 		ActivityStreamsType:      ap.ObjectNote,
 		Content:  				  content,
 		Text:                     toCreate.Item.Description,
-		Visibility: 			  gtsmodel.VisibilityPublic,
-		Sensitive:                &[]bool{false}[0],
-		Federated: 				  &[]bool{true}[0],
-		Boostable: 				  &[]bool{true}[0],
-		Replyable: 				  &[]bool{false}[0],
-		Likeable: 				  &[]bool{true}[0],
 	}
 
-	if errWithCode := n.processThreadID(ctx, newStatus); errWithCode != nil {
-		return errWithCode
+	settings.apply(newStatus, toCreate.Item)
+
+	// Comments link to the page of their item, already previewed.
+	if config.GetRssPreviewCards() && !toCreate.Comment && toCreate.Item.Link != "" {
+		card, err := n.previewCard(ctx, toCreate.page())
+		if err != nil {
+			l.Warnf("Failed to read preview card: %s", err)
+		} else if card != nil {
+			newStatus.PreviewCardID = card.ID
+			newStatus.PreviewCard = card
+		}
+	}
+
+	if toCreate.InReplyTo == nil {
+		toCreate.InReplyTo = n.itemParent(ctx, toCreate.Account, toCreate.Item)
+	}
+
+	if toCreate.InReplyTo != nil {
+		// Replies join the thread of the status they reply to.
+		newStatus.InReplyToID = toCreate.InReplyTo.ID
+		newStatus.InReplyToURI = toCreate.InReplyTo.URI
+		newStatus.InReplyToAccountID = toCreate.InReplyTo.AccountID
+		newStatus.InReplyTo = toCreate.InReplyTo
+		newStatus.InReplyToAccount = toCreate.Account
+		newStatus.ThreadID = toCreate.InReplyTo.ThreadID
+	} else if errWithCode := n.processThreadID(ctx, newStatus); errWithCode != nil {
+		return nil, errWithCode
 	}
 
 	n.dereferencer.FetchStatusAttachments(n.ctx, tsport, newStatus, newStatus)
 
+	for _, attachment := range toCreate.Attachments {
+		attachment.StatusID = newStatus.ID
+		if err := n.state.DB.UpdateAttachment(ctx, attachment, "status_id"); err != nil {
+			l.Errorf("Failed to attach media %s: %s", attachment.ID, err)
+			continue
+		}
+		newStatus.Attachments = append(newStatus.Attachments, attachment)
+		newStatus.AttachmentIDs = append(newStatus.AttachmentIDs, attachment.ID)
+	}
+
+	for _, mention := range mentions {
+		if err := n.state.DB.PutMention(ctx, mention); err != nil {
+			l.Errorf("Failed to put author mention in DB: %s", err)
+			continue
+		}
+		newStatus.Mentions = append(newStatus.Mentions, mention)
+		newStatus.MentionIDs = append(newStatus.MentionIDs, mention.ID)
+	}
+
 	// put the new status in the database
 	l.Infof(fmt.Sprintf("Pushing item to DB (time: %s)", toCreate.Item.PublishedParsed))
 	if err := n.state.DB.PutStatus(ctx, newStatus); err != nil {
 		l.Errorf("Failed to push item to DB: %s", err)
-		return gtserror.NewErrorInternalError(err)
+		return nil, gtserror.NewErrorInternalError(err)
 	}
 
-	// send it back to the client API worker for async side-effects.
-	n.state.Workers.Client.Queue.Push(&messages.FromClientAPI{
-		APObjectType:   ap.ObjectNote,
-		APActivityType: ap.ActivityCreate,
-		GTSModel:       newStatus,
-		Origin:         toCreate.Account,
-	})
+	if err := n.PutRssItem(ctx, &gtsmodel.RssItem{
+		ID:        id.NewULID(),
+		AccountID: toCreate.Account.ID,
+		StatusID:  newStatus.ID,
+		Link:      toCreate.Item.Link,
+	}); err != nil {
+		l.Errorf("Failed to record item: %s", err)
+	}
 
-	return nil
+	if !toCreate.Comment {
+		if err := n.updateLastItemAt(ctx, toCreate.Account.ID, newStatus.CreatedAt); err != nil {
+			l.Errorf("Failed to update feed last item date: %s", err)
+		}
+	}
+
+	if config.GetRssDeduplicate() {
+		if err := n.indexArticle(ctx, toCreate, newStatus); err != nil {
+			l.Errorf("Failed to index article: %s", err)
+		}
+	}
+
+	return newStatus, nil
 }
 
 
@@ -0,0 +1,298 @@
+package rss
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gorilla/feeds"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+// Timelines served as feeds by GetTimelineFeed.
+const (
+	TimelineHome   = "home"
+	TimelinePublic = "public"
+	TimelineLocal  = "local"
+	TimelineList   = "lists"
+	TimelineTag    = "tags"
+)
+
+// TimelineFeedsPath is the path timeline feeds are served at, followed by the token.
+const TimelineFeedsPath = "/api/v1/timeline_feeds"
+
+// timelineFeedLength is the number of statuses of a timeline feed.
+const timelineFeedLength = 40
+
+// feedTokenEncoding encodes the random bytes of feed tokens.
+var feedTokenEncoding = base32.NewEncoding("0123456789abcdefghjkmnpqrstvwxyz").WithPadding(base32.NoPadding)
+
+// GetFeedTokens returns the feed tokens of account.
+func (n *rssTooter) GetFeedTokens(ctx context.Context, account *gtsmodel.Account) ([]*apimodel.FeedToken, gtserror.WithCode) {
+	tokens, err := n.GetFeedTokensByAccountID(ctx, account.ID)
+	if err != nil {
+		err := gtserror.Newf("db error getting feed tokens: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiTokens := make([]*apimodel.FeedToken, 0, len(tokens))
+	for _, token := range tokens {
+		apiTokens = append(apiTokens, apiFeedToken(token))
+	}
+
+	return apiTokens, nil
+}
+
+// CreateFeedToken creates a new feed token for account.
+func (n *rssTooter) CreateFeedToken(ctx context.Context, account *gtsmodel.Account) (*apimodel.FeedToken, gtserror.WithCode) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		err := gtserror.Newf("error generating feed token: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	secretToken := feedTokenEncoding.EncodeToString(secret)
+	token := &gtsmodel.FeedToken{
+		ID:        id.NewULID(),
+		CreatedAt: time.Now(),
+		AccountID: account.ID,
+		Account:   account,
+		TokenHash: appPasswordHash(secretToken),
+	}
+
+	if err := n.PutFeedToken(ctx, token); err != nil {
+		err := gtserror.Newf("db error putting feed token: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// The token is only stored hashed, and only ever shown here.
+	apiToken := apiFeedToken(token)
+	apiToken.Token = secretToken
+	apiToken.URL = config.GetProtocol() + "://" + config.GetHost() + TimelineFeedsPath + "/" + secretToken
+	return apiToken, nil
+}
+
+// DeleteFeedToken revokes a feed token of account.
+func (n *rssTooter) DeleteFeedToken(ctx context.Context, account *gtsmodel.Account, tokenID string) gtserror.WithCode {
+	if err := n.DeleteFeedTokenByID(ctx, account.ID, tokenID); err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err := fmt.Errorf("feed token %s not found", tokenID)
+			return gtserror.NewErrorNotFound(err, err.Error())
+		}
+		err := gtserror.Newf("db error deleting feed token: %w", err)
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	return nil
+}
+
+// GetTimelineFeed returns a timeline of the owner of token, serialized to
+// format. name is the list id for TimelineList, the tag name for TimelineTag.
+//
+// Statuses are filtered for the token owner the same way as the timelines
+// of the client API, so the feed can be read by any feed reader, or be
+// polled back by a feed account to re-publish an aggregated timeline.
+func (n *rssTooter) GetTimelineFeed(ctx context.Context, token string, timeline string, name string, format typeutils.FeedFormat) (string, gtserror.WithCode) {
+	account, errWithCode := n.feedTokenAccount(ctx, token)
+	if errWithCode != nil {
+		return "", errWithCode
+	}
+
+	var (
+		title    string
+		statuses []*gtsmodel.Status
+		list     *gtsmodel.List
+		tag      *gtsmodel.Tag
+		err      error
+	)
+
+	switch timeline {
+	case TimelineHome:
+		title = "Home timeline of @" + account.Username
+		statuses, err = n.state.DB.GetHomeTimeline(ctx, account.ID, "", "", "", timelineFeedLength, false)
+		if err == nil {
+			statuses, err = n.filterStatuses(ctx, statuses, account, n.visFilter.StatusHomeTimelineable)
+		}
+
+	case TimelinePublic, TimelineLocal:
+		title = "Public timeline"
+		if timeline == TimelineLocal {
+			title = "Local timeline"
+		}
+		statuses, err = n.state.DB.GetPublicTimeline(ctx, "", "", "", timelineFeedLength, timeline == TimelineLocal)
+		if err == nil {
+			statuses, err = n.filterStatuses(ctx, statuses, account, n.visFilter.StatusPublicTimelineable)
+		}
+
+	case TimelineList:
+		list, err = n.state.DB.GetListByID(ctx, name)
+		if err != nil && !errors.Is(err, db.ErrNoEntries) {
+			err := gtserror.Newf("db error getting list: %w", err)
+			return "", gtserror.NewErrorInternalError(err)
+		}
+		if list == nil || list.AccountID != account.ID {
+			err := fmt.Errorf("list %s not found", name)
+			return "", gtserror.NewErrorNotFound(err, err.Error())
+		}
+
+		title = "List " + list.Title
+		statuses, err = n.state.DB.GetListTimeline(ctx, list.ID, "", "", "", timelineFeedLength)
+		if err == nil {
+			statuses, err = n.filterStatuses(ctx, statuses, account, n.visFilter.StatusVisible)
+		}
+
+	case TimelineTag:
+		tagName, ok := text.NormalizeHashtag(name)
+		if !ok {
+			err := fmt.Errorf("invalid tag %s", name)
+			return "", gtserror.NewErrorBadRequest(err, err.Error())
+		}
+
+		tag, err = n.state.DB.GetTagByName(ctx, tagName)
+		if err != nil && !errors.Is(err, db.ErrNoEntries) {
+			err := gtserror.Newf("db error getting tag: %w", err)
+			return "", gtserror.NewErrorInternalError(err)
+		}
+		if tag == nil {
+			err := fmt.Errorf("tag %s not found", name)
+			return "", gtserror.NewErrorNotFound(err, err.Error())
+		}
+
+		title = "Posts tagged #" + tag.Name
+		statuses, err = n.state.DB.GetTagTimeline(ctx, tag.ID, "", "", "", timelineFeedLength)
+		if err == nil {
+			statuses, err = n.filterStatuses(ctx, statuses, account, n.visFilter.StatusTagTimelineable)
+		}
+
+	default:
+		err := fmt.Errorf("unknown timeline %s", timeline)
+		return "", gtserror.NewErrorNotFound(err, err.Error())
+	}
+
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err := gtserror.Newf("db error getting timeline: %w", err)
+		return "", gtserror.NewErrorInternalError(err)
+	}
+
+	instanceURL := config.GetProtocol() + "://" + config.GetHost()
+	feedPath := TimelineFeedsPath + "/" + token + "/" + timeline
+	if name != "" {
+		feedPath += "/" + name
+	}
+
+	feed := &feeds.Feed{
+		Title:       title + " on " + config.GetHost(),
+		Description: title + " on " + config.GetHost(),
+		Link:        &feeds.Link{Href: instanceURL},
+		Updated:     account.CreatedAt,
+	}
+	if len(statuses) > 0 {
+		feed.Updated = statuses[0].CreatedAt
+	}
+
+	for _, status := range statuses {
+		item, err := n.converter.StatusToRSSItem(ctx, status)
+		if err != nil {
+			err := gtserror.Newf("error converting status to feed item: %w", err)
+			return "", gtserror.NewErrorInternalError(err)
+		}
+		feed.Add(item)
+	}
+
+	links := typeutils.FeedLinks{Self: instanceURL + feedPath + "." + format.Extension()}
+	str, err := typeutils.FeedToString(feed, format, links, statuses)
+	if err != nil {
+		err := gtserror.Newf("error converting feed to string: %w", err)
+		return "", gtserror.NewErrorInternalError(err)
+	}
+
+	return str, nil
+}
+
+// feedTokenAccount returns the account owning a feed token, if it can still use it.
+func (n *rssTooter) feedTokenAccount(ctx context.Context, token string) (*gtsmodel.Account, gtserror.WithCode) {
+	feedToken, err := n.GetFeedTokenByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err := errors.New("invalid feed token")
+			return nil, gtserror.NewErrorUnauthorized(err, err.Error())
+		}
+		err := gtserror.Newf("db error getting feed token: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	_, account, errWithCode := n.tokenOwner(ctx, feedToken.AccountID)
+	return account, errWithCode
+}
+
+// tokenOwner returns the user and account owning a feed token
+// or an app password, if they can still use it.
+func (n *rssTooter) tokenOwner(ctx context.Context, accountID string) (*gtsmodel.User, *gtsmodel.Account, gtserror.WithCode) {
+	user, err := n.state.DB.GetUserByAccountID(ctx, accountID)
+	if err != nil {
+		err := gtserror.Newf("db error getting token user: %w", err)
+		return nil, nil, gtserror.NewErrorInternalError(err)
+	}
+
+	account, err := n.state.DB.GetAccountByID(ctx, accountID)
+	if err != nil {
+		err := gtserror.Newf("db error getting token account: %w", err)
+		return nil, nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if *user.Disabled || !*user.Approved || account.IsSuspended() {
+		err := errors.New("token owner can't access timelines")
+		return nil, nil, gtserror.NewErrorUnauthorized(err, err.Error())
+	}
+
+	return user, account, nil
+}
+
+// filterStatuses returns the statuses visible to account, using one of the
+// visibility.Filter timeline functions. Boosts are replaced by the boosted
+// status, as feed items can't represent them, and only kept once.
+func (n *rssTooter) filterStatuses(
+	ctx context.Context,
+	statuses []*gtsmodel.Status,
+	account *gtsmodel.Account,
+	visible func(context.Context, *gtsmodel.Account, *gtsmodel.Status) (bool, error),
+) ([]*gtsmodel.Status, error) {
+	filtered := make([]*gtsmodel.Status, 0, len(statuses))
+	seen := make(map[string]bool, len(statuses))
+
+	for _, status := range statuses {
+		ok, err := visible(ctx, account, status)
+		if err != nil {
+			return nil, err
+		}
+
+		if status.BoostOf != nil {
+			status = status.BoostOf
+		}
+
+		if ok && !seen[status.ID] {
+			seen[status.ID] = true
+			filtered = append(filtered, status)
+		}
+	}
+
+	return filtered, nil
+}
+
+func apiFeedToken(token *gtsmodel.FeedToken) *apimodel.FeedToken {
+	return &apimodel.FeedToken{
+		ID:        token.ID,
+		CreatedAt: util.FormatISO8601(token.CreatedAt),
+	}
+}
@@ -0,0 +1,52 @@
+package rss
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+)
+
+type TimelineFeedTestSuite struct {
+	RssStandardTestSuite
+}
+
+func (suite *TimelineFeedTestSuite) TestFeedToken() {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_1"]
+
+	token, errWithCode := suite.tooter.CreateFeedToken(ctx, account)
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+	suite.NotEmpty(token.Token)
+	suite.Contains(token.URL, token.Token)
+
+	feed, errWithCode := suite.tooter.GetTimelineFeed(ctx, token.Token, TimelineHome, "", typeutils.FeedFormatRSS)
+	suite.Nil(errWithCode)
+	suite.Contains(feed, "Home timeline of @"+account.Username)
+
+	// Tokens are stored hashed, and never listed.
+	stored, err := suite.tooter.GetFeedTokenByToken(ctx, token.Token)
+	suite.NoError(err)
+	suite.Equal(appPasswordHash(token.Token), stored.TokenHash)
+
+	tokens, errWithCode := suite.tooter.GetFeedTokens(ctx, account)
+	suite.Nil(errWithCode)
+	if suite.Len(tokens, 1) {
+		suite.Equal(token.ID, tokens[0].ID)
+		suite.Empty(tokens[0].Token)
+		suite.Empty(tokens[0].URL)
+	}
+
+	errWithCode = suite.tooter.DeleteFeedToken(ctx, account, token.ID)
+	suite.Nil(errWithCode)
+	_, errWithCode = suite.tooter.GetTimelineFeed(ctx, token.Token, TimelineHome, "", typeutils.FeedFormatRSS)
+	suite.Equal(http.StatusUnauthorized, errWithCode.Code())
+}
+
+func TestTimelineFeedTestSuite(t *testing.T) {
+	suite.Run(t, new(TimelineFeedTestSuite))
+}
@@ -4,10 +4,18 @@ import (
    "context"
    "errors"
    "fmt"
+   "os"
+   "path/filepath"
+   "time"
+
+   apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 
    "github.com/superseriousbusiness/gotosocial/internal/config"
+   "github.com/superseriousbusiness/gotosocial/internal/email"
    "github.com/superseriousbusiness/gotosocial/internal/federation/dereferencing"
    "github.com/superseriousbusiness/gotosocial/internal/filter/visibility"
+   "github.com/superseriousbusiness/gotosocial/internal/gtserror"
+   "github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
    "github.com/superseriousbusiness/gotosocial/internal/httpclient"
    "github.com/superseriousbusiness/gotosocial/internal/media"
    "github.com/superseriousbusiness/gotosocial/internal/state"
@@ -28,19 +36,124 @@ type RssTooter interface {
    Stop() error
 
    NewUser(ctx context.Context, username string) (string, error)
+
+   // GetFeed returns the feed of a feed account, if requester is allowed to manage it
+   GetFeed(ctx context.Context, requester *gtsmodel.User, accountID string) (*apimodel.RssFeed, gtserror.WithCode)
+
+   // UpdateFeed updates the settings of a feed account, if requester is allowed to manage it
+   UpdateFeed(ctx context.Context, requester *gtsmodel.User, accountID string, form *apimodel.RssFeedUpdateRequest) (*apimodel.RssFeed, gtserror.WithCode)
+
+   // GetFeedDelegates returns the local accounts acting on behalf of a feed account, if requester is an admin
+   GetFeedDelegates(ctx context.Context, requester *gtsmodel.User, accountID string) ([]*apimodel.Account, gtserror.WithCode)
+
+   // AddFeedDelegate lets a local account act on behalf of a feed account, if requester is an admin
+   AddFeedDelegate(ctx context.Context, requester *gtsmodel.User, accountID string, delegateAccountID string) ([]*apimodel.Account, gtserror.WithCode)
+
+   // RemoveFeedDelegate stops a local account acting on behalf of a feed account, if requester is an admin
+   RemoveFeedDelegate(ctx context.Context, requester *gtsmodel.User, accountID string, delegateAccountID string) ([]*apimodel.Account, gtserror.WithCode)
+
+   // ClaimFeed requests the ownership of a feed account by requester, returning the token to publish on the feed or its website
+   ClaimFeed(ctx context.Context, requester *gtsmodel.User, accountID string) (*apimodel.RssFeedClaim, gtserror.WithCode)
+
+   // GetFeedClaim returns the claim of requester on a feed account
+   GetFeedClaim(ctx context.Context, requester *gtsmodel.User, accountID string) (*apimodel.RssFeedClaim, gtserror.WithCode)
+
+   // CreateNewsletter creates the account of an email newsletter with its own address, if requester is an admin
+   CreateNewsletter(ctx context.Context, requester *gtsmodel.User, form *apimodel.RssNewsletterCreateRequest) (*apimodel.RssNewsletter, gtserror.WithCode)
+
+   // GetNewsletters returns the email newsletters with their unsubscribe and confirmation links, if requester is an admin
+   GetNewsletters(ctx context.Context, requester *gtsmodel.User) ([]*apimodel.RssNewsletter, gtserror.WithCode)
+
+   // CreateScrapedFeed creates the account of a website without any feed, read from a web page with selectors, if requester is an admin
+   CreateScrapedFeed(ctx context.Context, requester *gtsmodel.User, form *apimodel.RssScrapedFeedRequest) (*apimodel.RssFeed, gtserror.WithCode)
+
+   // PreviewScrapedFeed returns the items read from a web page, or its saved html, with selectors, if requester is an admin
+   PreviewScrapedFeed(ctx context.Context, requester *gtsmodel.User, form *apimodel.RssScrapedFeedRequest) ([]*apimodel.RssScrapedItem, gtserror.WithCode)
+
+   // GetWebhooks returns the webhooks receiving the new statuses of a feed account, if requester is allowed to manage it, or of every feed if accountID is empty and requester is an admin
+   GetWebhooks(ctx context.Context, requester *gtsmodel.User, accountID string) ([]*apimodel.RssWebhook, gtserror.WithCode)
+
+   // CreateWebhook creates a webhook receiving the new statuses of a feed account, if requester is allowed to manage it, or of every feed if accountID is empty and requester is an admin
+   CreateWebhook(ctx context.Context, requester *gtsmodel.User, accountID string, form *apimodel.RssWebhookCreateRequest) (*apimodel.RssWebhook, gtserror.WithCode)
+
+   // DeleteWebhook deletes a webhook with its pending deliveries, if requester is allowed to manage it
+   DeleteWebhook(ctx context.Context, requester *gtsmodel.User, webhookID string) gtserror.WithCode
+
+   // GetFailedWebhookDeliveries returns the webhook deliveries given up on, if requester is an admin
+   GetFailedWebhookDeliveries(ctx context.Context, requester *gtsmodel.User) ([]*apimodel.RssWebhookDelivery, gtserror.WithCode)
+
+   // RetryWebhookDelivery attempts again a webhook delivery given up on, if requester is an admin
+   RetryWebhookDelivery(ctx context.Context, requester *gtsmodel.User, deliveryID string) (*apimodel.RssWebhookDelivery, gtserror.WithCode)
+
+   // GetFeedTokens returns the tokens giving access to the timeline feeds of account
+   GetFeedTokens(ctx context.Context, account *gtsmodel.Account) ([]*apimodel.FeedToken, gtserror.WithCode)
+
+   // CreateFeedToken creates a token giving access to the timeline feeds of account
+   CreateFeedToken(ctx context.Context, account *gtsmodel.Account) (*apimodel.FeedToken, gtserror.WithCode)
+
+   // DeleteFeedToken revokes a token giving access to the timeline feeds of account
+   DeleteFeedToken(ctx context.Context, account *gtsmodel.Account, tokenID string) gtserror.WithCode
+
+   // GetTimelineFeed returns a timeline of the owner of token as a feed document
+   GetTimelineFeed(ctx context.Context, token string, timeline string, name string, format typeutils.FeedFormat) (string, gtserror.WithCode)
+
+   // GetAppPasswords returns the passwords of account for the apps speaking the Google Reader or Fever API
+   GetAppPasswords(ctx context.Context, account *gtsmodel.Account) ([]*apimodel.AppPassword, gtserror.WithCode)
+
+   // CreateAppPassword creates a password of account for an app speaking the Google Reader or Fever API
+   CreateAppPassword(ctx context.Context, account *gtsmodel.Account, form *apimodel.AppPasswordCreateRequest) (*apimodel.AppPassword, gtserror.WithCode)
+
+   // DeleteAppPassword revokes an app password of account
+   DeleteAppPassword(ctx context.Context, account *gtsmodel.Account, passwordID string) gtserror.WithCode
+
+   // ReaderLogin returns the account logging in to the Google Reader API with its username or email address and an app password
+   ReaderLogin(ctx context.Context, login string, password string) (*gtsmodel.Account, gtserror.WithCode)
+
+   // ReaderAuth returns the account of the app password used as auth token of the Google Reader API
+   ReaderAuth(ctx context.Context, token string) (*gtsmodel.Account, gtserror.WithCode)
+
+   // FeverAuth returns the account of the app password of a Fever API key
+   FeverAuth(ctx context.Context, apiKey string) (*gtsmodel.Account, gtserror.WithCode)
+
+   // ReaderSubscriptions returns the feed accounts followed by account, with the lists including them
+   ReaderSubscriptions(ctx context.Context, account *gtsmodel.Account) ([]*ReaderSubscription, gtserror.WithCode)
+
+   // ReaderSubscribe returns the feed account of a website or feed, creating it if needed
+   ReaderSubscribe(ctx context.Context, feedURL string) (*gtsmodel.Account, gtserror.WithCode)
+
+   // ReaderItems returns the items of a stream visible to account, with the status id to continue from
+   ReaderItems(ctx context.Context, account *gtsmodel.Account, query *ReaderQuery) ([]*ReaderItem, string, gtserror.WithCode)
+
+   // ReaderItemsByIDs returns the items of the given ids visible to account
+   ReaderItemsByIDs(ctx context.Context, account *gtsmodel.Account, itemIDs []int64) ([]*ReaderItem, gtserror.WithCode)
+
+   // ReaderItemCounts returns the number of items, or unread items, of the subscriptions of account
+   ReaderItemCounts(ctx context.Context, account *gtsmodel.Account, unread bool) ([]*ReaderItemCount, gtserror.WithCode)
+
+   // ReaderMarkRead returns the home timeline marker of account moved forward to lastReadID, nil if unchanged
+   ReaderMarkRead(ctx context.Context, account *gtsmodel.Account, lastReadID string) (*gtsmodel.Marker, gtserror.WithCode)
+
+   // ReaderMarkUnread returns the home timeline marker of account moved back before a status, nil if unchanged
+   ReaderMarkUnread(ctx context.Context, account *gtsmodel.Account, statusID string) (*gtsmodel.Marker, gtserror.WithCode)
 }
 
 // RssTooter just implements the RssTooter interface
 type rssTooter struct {
    state                *state.State
    dereferencer         dereferencing.Dereferencer
-   httpclient           *httpclient.Client
+   mediaManager         *media.Manager
+   httpclient           HTTPClient
+   fetcher              Fetcher
    ctx                  context.Context
    cancelFunc           context.CancelFunc
    transportController  transport.Controller
+   converter            *typeutils.Converter
+   visFilter            *visibility.Filter
+   emailSender          email.Sender
+   failing              *failingFeeds
+   metrics              *pollerMetrics
 
    nitterHost     string
-   userPassword   string
    pollFrequency  int
 }
 
@@ -52,29 +165,77 @@ func NewRssTooter(
    transportController  transport.Controller, 
    typeConverter        *typeutils.Converter,
    visFilter            *visibility.Filter,
+   emailSender          email.Sender,
 ) RssTooter {
    ctx, cancelFunc := context.WithCancel(pCtx)
+   client := httpclient.New(httpclient.Config{ MaxOpenConnsPerHost: 1, })
+   failing := newFailingFeeds()
 
    return &rssTooter{
       state:                  state,
       dereferencer:           dereferencing.NewDereferencer(state, typeConverter, transportController, visFilter, mediaManager),
-      httpclient:             httpclient.New(httpclient.Config{ MaxOpenConnsPerHost: 1, }),
+      mediaManager:           mediaManager,
+      httpclient:             client,
+      fetcher:                NewFetcher(client),
       ctx:                    ctx,
       cancelFunc:             cancelFunc,
       transportController:    transportController,
-      userPassword:           config.GetRssUserPassword(),
+      converter:              typeConverter,
+      visFilter:              visFilter,
+      emailSender:            emailSender,
+      failing:                failing,
+      metrics:                newPollerMetrics(failing),
       pollFrequency:          config.GetRssPollFrequency(),
    }
 }
 
 // Start starts the RssTooter, start fetching Status from Nitter
 func (n *rssTooter) Start() error {
-   if( n.userPassword == "" ){
-      return errors.New(fmt.Sprintf("Missing %s config", config.RssUserPasswordFlag()))
+   if( n.pollFrequency == 0 ){
+      return errors.New(fmt.Sprintf("Missing or invalid %s config %d", config.RssPollFrequencyFlag(), n.pollFrequency))
    }
 
-   if( n.pollFrequency == 0 ){
-      return errors.New(fmt.Sprintf("Missing or invalid %s config %s", config.RssPollFrequencyFlag(), n.pollFrequency))
+   if !validVisibility(resolveSettings(nil).Visibility) {
+      return fmt.Errorf("Invalid %s config %s", config.RssDefaultVisibilityFlag(), config.GetRssDefaultVisibility())
+   }
+
+   if !n.state.Workers.Scheduler.AddRecurring("@rssdigest", time.Time{}, digestFrequency, n.publishDigests) {
+      return errors.New("Failed to schedule feed digests")
+   }
+
+   if !n.state.Workers.Scheduler.AddRecurring("@rssreplies", time.Time{}, replyForwardFrequency, n.forwardReplies) {
+      return errors.New("Failed to schedule feed replies forwarding")
+   }
+
+   if !n.state.Workers.Scheduler.AddRecurring("@rssretention", time.Time{}, retentionFrequency, n.applyRetention) {
+      return errors.New("Failed to schedule feed statuses retention")
+   }
+
+   if !n.state.Workers.Scheduler.AddRecurring("@rsswebhooks", time.Time{}, webhookFrequency, n.deliverWebhooks) {
+      return errors.New("Failed to schedule feed webhooks delivery")
+   }
+
+   if frequency := config.GetRssProfileRefreshFrequency(); frequency > 0 {
+      if !n.state.Workers.Scheduler.AddRecurring("@rssprofiles", time.Time{}, time.Duration(frequency) * time.Hour, n.refreshProfiles) {
+         return errors.New("Failed to schedule feed profiles refresh")
+      }
+   }
+
+   if config.GetRssPreviewCardsLocal() {
+      if !n.state.Workers.Scheduler.AddRecurring("@rsscards", time.Time{}, cardFrequency, n.attachLocalCards) {
+         return errors.New("Failed to schedule local statuses preview cards")
+      }
+   }
+
+   if maildir := config.GetRssNewsletterMaildir(); maildir != "" {
+      for _, dir := range []string{"tmp", "new", "cur"} {
+         if err := os.MkdirAll(filepath.Join(maildir, dir), 0o700); err != nil {
+            return fmt.Errorf("Invalid %s config %s: %s", config.RssNewsletterMaildirFlag(), maildir, err)
+         }
+      }
+      if !n.state.Workers.Scheduler.AddRecurring("@rssnewsletters", time.Time{}, newsletterFrequency, n.receiveNewsletters) {
+         return errors.New("Failed to schedule newsletters reception")
+      }
    }
 
    go n.refresh()
@@ -0,0 +1,40 @@
+package rss
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/superseriousbusiness/gotosocial/internal/rss"
+
+// Attributes of the spans of the poller.
+const (
+	attrFeedURL      = attribute.Key("rss.feed.url")
+	attrAccountID    = attribute.Key("rss.account.id")
+	attrItemLink     = attribute.Key("rss.item.link")
+	attrItemCount    = attribute.Key("rss.item.count")
+	attrFeedCount    = attribute.Key("rss.feed.count")
+	attrBytes        = attribute.Key("rss.bytes")
+	attrCacheOutcome = attribute.Key("rss.cache")
+	attrStatusCode   = attribute.Key("http.response.status_code")
+	attrDigest       = attribute.Key("rss.digest")
+)
+
+// startSpan starts a span of the poller from the global tracer
+// provider, which only records them when tracing is enabled.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.GetTracerProvider().Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends span, marking it as failed with err if set.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -8,93 +8,155 @@ import (
    "time"
 
    "github.com/superseriousbusiness/gotosocial/internal/ap"
+   "github.com/superseriousbusiness/gotosocial/internal/config"
    "github.com/superseriousbusiness/gotosocial/internal/gtserror"
    "github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
    "github.com/superseriousbusiness/gotosocial/internal/id"
+   "github.com/superseriousbusiness/gotosocial/internal/log"
    "github.com/superseriousbusiness/gotosocial/internal/uris"
-   "golang.org/x/crypto/bcrypt"
 )
 
+// noLoginPassword is the password hash of the users of feed
+// accounts, which no password matches as it isn't a bcrypt hash.
+const noLoginPassword = "!"
+
 func (n *rssTooter) NewUser(ctx context.Context, resource string) (string, error) {
-   alreadyExistName, rssFeed, err := NewRssFeed(n.state, ctx, resource)
+   alreadyExistName, rssFeed, err := NewRssFeed(n.state, n.fetcher, ctx, resource)
 
    if len(alreadyExistName) == 0 && err == nil {
-      // Pre-fetch a transport for requesting username, used by later dereferencing.
-      tsport, err := n.transportController.NewTransportForUsername(ctx, "")
-      if err != nil {
-         return "", gtserror.Newf("couldn't create transport: %w", err)
+      if _, err := n.createFeedAccount(ctx, rssFeed, nil); err != nil {
+         return "", err
       }
+      return rssFeed.DbUsername, nil
+   }
 
-      key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
-      if err != nil {
-         return "", fmt.Errorf("Error geenrating account keys: (%s)", err)
-      }
+   return alreadyExistName, err
+}
 
-      accountID := id.NewULID()
-      settings := &gtsmodel.AccountSettings{
-         AccountID: accountID,
-         Privacy:   gtsmodel.VisibilityPublic,
-      }
+// createFeedAccount creates the account proxying rssFeed, scraped with scraper
+// if the website has no feed, and imports the feed history in the background.
+func (n *rssTooter) createFeedAccount(ctx context.Context, rssFeed *rssFeed, scraper *gtsmodel.RssScraper) (*gtsmodel.RssFeed, error) {
+   // Pre-fetch a transport for requesting username, used by later dereferencing.
+   tsport, err := n.transportController.NewTransportForUsername(ctx, "")
+   if err != nil {
+      return nil, gtserror.Newf("couldn't create transport: %w", err)
+   }
 
-      // if we have db.ErrNoEntries, we just don't have an
-      // account yet so create one before we proceed
-      accountURIs := uris.GenerateURIsForAccount(rssFeed.DbUsername)
-      acct := &gtsmodel.Account{
-         ID:                    accountID,
-         Username:              rssFeed.DbUsername,
-         DisplayName:           rssFeed.Feed.Title,
-         Note:                  rssFeed.ExtractDescription(),
-         Bot:                   &[]bool{true}[0],
-         Locked:                &[]bool{false}[0],
-         Discoverable:          &[]bool{true}[0],
-         URL:                   rssFeed.FeedUrl.String(),
-         PrivateKey:            key,
-         PublicKey:             &key.PublicKey,
-         PublicKeyURI:          accountURIs.PublicKeyURI,
-         ActorType:             ap.ActorPerson,
-         URI:                   accountURIs.UserURI,
-         AvatarRemoteURL:       rssFeed.ExtractIcon(),
-         InboxURI:              accountURIs.InboxURI,
-         OutboxURI:             accountURIs.OutboxURI,
-         FollowersURI:          accountURIs.FollowersURI,
-         FollowingURI:          accountURIs.FollowingURI,
-         FeaturedCollectionURI: accountURIs.FeaturedCollectionURI,
-         Settings:              settings,
-      }
+   acct, err := newProxyAccount(rssFeed.DbUsername, rssFeed.Feed.Title, rssFeed.ExtractDescription(), rssFeed.FeedUrl.String())
+   if err != nil {
+      return nil, err
+   }
+   acct.HeaderRemoteURL = rssFeed.ExtractHeader()
 
-      err = n.dereferencer.FetchRemoteAccountAvatar(ctx, tsport, acct, acct)
-      if err != nil {
-         return "", fmt.Errorf("Error fetching account (%s) media: %s", rssFeed.DbUsername, err)
+   if icon := rssFeed.ResolveIcon(ctx, n.fetcher); icon != nil {
+      if err := n.loadAvatar(ctx, acct, icon); err != nil {
+         return nil, fmt.Errorf("Error fetching account (%s) media: %s", rssFeed.DbUsername, err)
       }
+   }
 
-      // Insert the settings!
-      if err := n.state.DB.PutAccountSettings(ctx, acct.Settings); err != nil {
-         return "", err
-      }
+   err = n.dereferencer.FetchRemoteAccountHeader(ctx, tsport, acct, acct)
+   if err != nil {
+      log.Warnf(ctx, "Error fetching account (%s) header: %s", rssFeed.DbUsername, err)
+      acct.HeaderRemoteURL = ""
+   }
 
-      // insert the new account!
-      if err := n.state.DB.PutAccount(ctx, acct); err != nil {
-         return "", err
-      }
+   if err := n.putProxyAccount(ctx, acct); err != nil {
+      return nil, err
+   }
 
-      pw, err := bcrypt.GenerateFromPassword([]byte(n.userPassword), bcrypt.DefaultCost)
-      if err != nil {
-         return "", fmt.Errorf("error hashing password: %s", err)
+   // and the feed it proxies
+   feed := &gtsmodel.RssFeed{
+      ID:                 id.NewULID(),
+      AccountID:          acct.ID,
+      URL:                acct.URL,
+      MediaType:          rssFeed.MediaType(),
+      Scraper:            scraper,
+      ProfileDisplayName: acct.DisplayName,
+      ProfileNote:        acct.Note,
+   }
+   if config.GetRssBackfillCount() > 0 {
+      // The current items are left to the backfill, even
+      // if the feed is polled before it runs: they are
+      // only stored, not sent to the timelines.
+      for _, item := range datedItems(rssFeed.Feed.Items) {
+         if item.PublishedParsed.After(feed.LastItemAt) {
+            feed.LastItemAt = *item.PublishedParsed
+         }
       }
+   }
+   if err := n.PutRssFeed(ctx, feed); err != nil {
+      return nil, err
+   }
 
-      u := &gtsmodel.User{
-         ID:                     acct.ID,
-         AccountID:              acct.ID,
-         Account:                acct,
-         EncryptedPassword:      string(pw),
-         Email:                  rssFeed.DbUsername + "@rss.tooter.com",
-         ConfirmedAt:            time.Now(),
-         Approved:               &[]bool{true}[0],
-      }
+   // Import the feed history in the background,
+   // the account can already be looked up.
+   n.state.Workers.Dereference.Queue.Push(func(ctx context.Context) {
+      n.backfill(ctx, acct, rssFeed)
+   })
 
-      // insert the user!
-      return rssFeed.DbUsername, n.state.DB.PutUser(ctx, u)
+   return feed, nil
+}
+
+// newProxyAccount returns the account of a proxy (feed or newsletter), with its keys.
+func newProxyAccount(username string, displayName string, note string, url string) (*gtsmodel.Account, error) {
+   key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+   if err != nil {
+      return nil, fmt.Errorf("Error geenrating account keys: (%s)", err)
    }
 
-   return alreadyExistName, err
+   accountID := id.NewULID()
+   settings := &gtsmodel.AccountSettings{
+      AccountID: accountID,
+      Privacy:   gtsmodel.VisibilityPublic,
+   }
+
+   accountURIs := uris.GenerateURIsForAccount(username)
+   return &gtsmodel.Account{
+      ID:                    accountID,
+      Username:              username,
+      DisplayName:           displayName,
+      Note:                  note,
+      Bot:                   &[]bool{true}[0],
+      Locked:                &[]bool{false}[0],
+      Discoverable:          &[]bool{true}[0],
+      URL:                   url,
+      PrivateKey:            key,
+      PublicKey:             &key.PublicKey,
+      PublicKeyURI:          accountURIs.PublicKeyURI,
+      ActorType:             ap.ActorService,
+      URI:                   accountURIs.UserURI,
+      InboxURI:              accountURIs.InboxURI,
+      OutboxURI:             accountURIs.OutboxURI,
+      FollowersURI:          accountURIs.FollowersURI,
+      FollowingURI:          accountURIs.FollowingURI,
+      FeaturedCollectionURI: accountURIs.FeaturedCollectionURI,
+      Settings:              settings,
+   }, nil
+}
+
+// putProxyAccount inserts the account of a proxy, with its settings and user.
+func (n *rssTooter) putProxyAccount(ctx context.Context, acct *gtsmodel.Account) error {
+   // Insert the settings!
+   if err := n.state.DB.PutAccountSettings(ctx, acct.Settings); err != nil {
+      return err
+   }
+
+   // insert the new account!
+   if err := n.state.DB.PutAccount(ctx, acct); err != nil {
+      return err
+   }
+
+   // The user has no email and no password, proxy accounts
+   // can't log in: delegates act on their behalf instead.
+   u := &gtsmodel.User{
+      ID:                     acct.ID,
+      AccountID:              acct.ID,
+      Account:                acct,
+      EncryptedPassword:      noLoginPassword,
+      ConfirmedAt:            time.Now(),
+      Approved:               &[]bool{true}[0],
+   }
+
+   // insert the user!
+   return n.state.DB.PutUser(ctx, u)
 }
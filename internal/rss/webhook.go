@@ -0,0 +1,502 @@
+package rss
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	statusfilter "github.com/superseriousbusiness/gotosocial/internal/filter/status"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+// webhookFrequency is how often due webhook deliveries are attempted.
+const webhookFrequency = time.Minute
+
+// webhookBatchSize is the maximum number of deliveries attempted at once,
+// so that a slow webhook doesn't hold the scheduler: the rest go next time.
+const webhookBatchSize = 100
+
+// webhookMaxAttempts is the number of failed attempts after which a delivery is given up on.
+const webhookMaxAttempts = 8
+
+// webhookRetryDelay is the delay before the second attempt of a delivery,
+// doubled after each failure: the last attempt comes about 4 hours after the first.
+const webhookRetryDelay = time.Minute
+
+// webhookFailedRetention is how long deliveries given up on are kept for admins to retry them.
+const webhookFailedRetention = 30 * 24 * time.Hour
+
+// webhookEventStatusCreated is the event of the deliveries of a new feed status.
+const webhookEventStatusCreated = "status.created"
+
+// webhookEventReplyCreated is the event of the deliveries of a reply to a feed
+// status, to the reply webhook of the feed.
+const webhookEventReplyCreated = "reply.created"
+
+// webhookPayload is the JSON body POSTed to webhooks for a new feed status.
+type webhookPayload struct {
+	Event     string           `json:"event"`
+	CreatedAt string           `json:"created_at"`
+	Status    *apimodel.Status `json:"status"`
+	Item      webhookItem      `json:"item"`
+	Feed      webhookFeed      `json:"feed"`
+}
+
+// webhookItem is the feed item a status was posted from.
+type webhookItem struct {
+	Title       string   `json:"title"`
+	Link        string   `json:"link"`
+	GUID        string   `json:"guid"`
+	Description string   `json:"description"`
+	Content     string   `json:"content"`
+	Authors     []string `json:"authors"`
+	Categories  []string `json:"categories"`
+	Published   string   `json:"published"`
+}
+
+// webhookFeed is the feed a status was posted from.
+type webhookFeed struct {
+	AccountID string `json:"account_id"`
+	Account   string `json:"account"`
+	URL       string `json:"url"`
+}
+
+// enqueueWebhooks stores a delivery of a new feed status to each webhook of its
+// account and each server-wide webhook, to be POSTed by the next deliverWebhooks.
+// The payload is rendered once, so retries send the status as it was posted.
+func (n *rssTooter) enqueueWebhooks(ctx context.Context, toCreate *ToCreate, status *gtsmodel.Status) error {
+	webhooks, err := n.GetRssWebhooksForAccount(ctx, toCreate.Account.ID)
+	if err != nil {
+		return gtserror.Newf("couldn't get webhooks: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	apiStatus, err := n.converter.StatusToAPIStatus(ctx, status, nil, statusfilter.FilterContextNone, nil, nil)
+	if err != nil {
+		return gtserror.Newf("couldn't convert status: %w", err)
+	}
+
+	now := time.Now()
+	payload := webhookPayload{
+		Event:     webhookEventStatusCreated,
+		CreatedAt: util.FormatISO8601(now),
+		Status:    apiStatus,
+		Item: webhookItem{
+			Title:       toCreate.Item.Title,
+			Link:        toCreate.Item.Link,
+			GUID:        toCreate.Item.GUID,
+			Description: toCreate.Item.Description,
+			Content:     toCreate.Item.Content,
+			Authors:     make([]string, 0, len(toCreate.Item.Authors)),
+			Categories:  toCreate.Item.Categories,
+		},
+		Feed: webhookFeed{
+			AccountID: toCreate.Account.ID,
+			Account:   toCreate.Account.Username,
+		},
+	}
+	for _, author := range toCreate.Item.Authors {
+		if author != nil && author.Name != "" {
+			payload.Item.Authors = append(payload.Item.Authors, author.Name)
+		}
+	}
+	if payload.Item.Categories == nil {
+		payload.Item.Categories = []string{}
+	}
+	if toCreate.Item.PublishedParsed != nil {
+		payload.Item.Published = util.FormatISO8601(*toCreate.Item.PublishedParsed)
+	}
+	if feed, err := n.GetRssFeedByAccountID(ctx, toCreate.Account.ID); err == nil {
+		payload.Feed.URL = feed.URL
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return gtserror.Newf("couldn't marshal payload: %w", err)
+	}
+
+	for _, webhook := range webhooks {
+		delivery := &gtsmodel.RssWebhookDelivery{
+			ID:            id.NewULID(),
+			CreatedAt:     now,
+			WebhookID:     webhook.ID,
+			Event:         webhookEventStatusCreated,
+			StatusID:      status.ID,
+			Payload:       string(data),
+			NextAttemptAt: now,
+		}
+		if err := n.PutRssWebhookDelivery(ctx, delivery); err != nil {
+			return gtserror.Newf("couldn't put delivery to %s: %w", webhook.URL, err)
+		}
+	}
+
+	return nil
+}
+
+// enqueueReplyWebhook stores a delivery of a reply to the reply webhook of
+// its feed, to be POSTed by the next deliverWebhooks like status deliveries.
+func (n *rssTooter) enqueueReplyWebhook(ctx context.Context, feed *gtsmodel.RssFeed, reply *gtsmodel.Status, payload replyWebhook) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return gtserror.Newf("couldn't marshal payload: %w", err)
+	}
+
+	now := time.Now()
+	delivery := &gtsmodel.RssWebhookDelivery{
+		ID:            id.NewULID(),
+		CreatedAt:     now,
+		WebhookID:     feed.ID,
+		Event:         webhookEventReplyCreated,
+		StatusID:      reply.ID,
+		Payload:       string(data),
+		NextAttemptAt: now,
+	}
+	if err := n.PutRssWebhookDelivery(ctx, delivery); err != nil {
+		return gtserror.Newf("couldn't put delivery to %s: %w", feed.ReplyWebhookURL, err)
+	}
+
+	return nil
+}
+
+// deliverWebhooks POSTs the webhook deliveries due at now. Failed deliveries
+// are attempted again with an exponential backoff, and given up on after
+// webhookMaxAttempts attempts, then kept for admins to retry them.
+func (n *rssTooter) deliverWebhooks(ctx context.Context, now time.Time) {
+	if err := n.DeleteRssWebhookDeliveriesFailedBefore(ctx, now.Add(-webhookFailedRetention)); err != nil {
+		log.Errorf(ctx, "Failed to delete old webhook deliveries: %s", err)
+	}
+
+	deliveries, err := n.GetDueRssWebhookDeliveries(ctx, now, webhookBatchSize)
+	if err != nil {
+		log.Errorf(ctx, "Failed to retrieve webhook deliveries: %s", err)
+		return
+	}
+
+	webhooks := make(map[string]*gtsmodel.RssWebhook)
+	for _, delivery := range deliveries {
+		webhook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			webhook, err = n.deliveryWebhook(ctx, delivery)
+			if err != nil && !errors.Is(err, db.ErrNoEntries) {
+				log.Errorf(ctx, "Failed to retrieve webhook %s: %s", delivery.WebhookID, err)
+				continue
+			}
+			webhooks[delivery.WebhookID] = webhook
+		}
+
+		if webhook == nil {
+			// Left behind by a deleted webhook, or reply webhook.
+			if err := n.DeleteRssWebhookDeliveryByID(ctx, delivery.ID); err != nil {
+				log.Errorf(ctx, "Failed to delete webhook delivery %s: %s", delivery.ID, err)
+			}
+			continue
+		}
+
+		n.deliverWebhook(ctx, webhook, delivery, now)
+	}
+}
+
+// deliveryWebhook returns the webhook of a delivery. Reply deliveries go to the
+// reply webhook of their feed, as it is set when attempted, returned as a webhook
+// of the feed account with the id of the feed, or db.ErrNoEntries once unset.
+func (n *rssTooter) deliveryWebhook(ctx context.Context, delivery *gtsmodel.RssWebhookDelivery) (*gtsmodel.RssWebhook, error) {
+	if delivery.Event != webhookEventReplyCreated {
+		return n.GetRssWebhookByID(ctx, delivery.WebhookID)
+	}
+
+	feed, err := n.GetRssFeedByID(ctx, delivery.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	if feed.ReplyWebhookURL == "" {
+		return nil, db.ErrNoEntries
+	}
+
+	return &gtsmodel.RssWebhook{
+		ID:        feed.ID,
+		CreatedAt: feed.CreatedAt,
+		AccountID: feed.AccountID,
+		URL:       feed.ReplyWebhookURL,
+		Secret:    feed.ReplyWebhookSecret,
+	}, nil
+}
+
+// deliverWebhook attempts a delivery, deleting it once
+// delivered, or scheduling its next attempt on failure.
+func (n *rssTooter) deliverWebhook(ctx context.Context, webhook *gtsmodel.RssWebhook, delivery *gtsmodel.RssWebhookDelivery, now time.Time) {
+	err := n.postWebhook(ctx, webhook, delivery)
+	if err == nil {
+		if err := n.DeleteRssWebhookDeliveryByID(ctx, delivery.ID); err != nil {
+			log.Errorf(ctx, "Failed to delete webhook delivery %s: %s", delivery.ID, err)
+		}
+		return
+	}
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = now
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= webhookMaxAttempts {
+		log.Warnf(ctx, "Giving up webhook delivery %s to %s after %d attempts: %s", delivery.ID, webhook.URL, delivery.Attempts, err)
+		delivery.NextAttemptAt = time.Time{}
+		delivery.FailedAt = now
+	} else {
+		log.Infof(ctx, "Failed webhook delivery %s to %s: %s", delivery.ID, webhook.URL, err)
+		delivery.NextAttemptAt = now.Add(webhookRetryDelay << (delivery.Attempts - 1))
+	}
+
+	if err := n.UpdateRssWebhookDelivery(ctx, delivery, "attempts", "last_attempt_at", "last_error", "next_attempt_at", "failed_at"); err != nil {
+		log.Errorf(ctx, "Failed to update webhook delivery %s: %s", delivery.ID, err)
+	}
+}
+
+// postWebhook POSTs the payload of a delivery to its webhook,
+// signed with the webhook secret.
+func (n *rssTooter) postWebhook(ctx context.Context, webhook *gtsmodel.RssWebhook, delivery *gtsmodel.RssWebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader([]byte(delivery.Payload)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Feed-Event", delivery.Event)
+	req.Header.Set("X-Feed-Delivery", delivery.ID)
+	req.Header.Set("X-Feed-Signature", "sha256="+webhookSignature(webhook.Secret, []byte(delivery.Payload)))
+
+	resp, err := n.httpclient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+
+	return nil
+}
+
+// webhookSignature returns the hex encoded HMAC-SHA256 of body with secret.
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// checkWebhookAccess checks that requester may manage the webhooks of a feed account,
+// or the server-wide webhooks if accountID is empty.
+func (n *rssTooter) checkWebhookAccess(ctx context.Context, requester *gtsmodel.User, accountID string) gtserror.WithCode {
+	if accountID == "" {
+		if !*requester.Admin {
+			err := fmt.Errorf("user %s not allowed to manage server-wide webhooks", requester.ID)
+			return gtserror.NewErrorForbidden(err, err.Error())
+		}
+		return nil
+	}
+
+	_, errWithCode := n.getManagedFeed(ctx, requester, accountID)
+	return errWithCode
+}
+
+// GetWebhooks returns the webhooks of a feed account, or the server-wide
+// ones if accountID is empty, if requester is allowed to manage them.
+func (n *rssTooter) GetWebhooks(ctx context.Context, requester *gtsmodel.User, accountID string) ([]*apimodel.RssWebhook, gtserror.WithCode) {
+	if errWithCode := n.checkWebhookAccess(ctx, requester, accountID); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	webhooks, err := n.GetRssWebhooks(ctx, accountID)
+	if err != nil {
+		err := gtserror.Newf("db error getting webhooks: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiWebhooks := make([]*apimodel.RssWebhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		apiWebhooks = append(apiWebhooks, apiWebhook(webhook))
+	}
+
+	return apiWebhooks, nil
+}
+
+// CreateWebhook creates a webhook of a feed account, or a server-wide
+// one if accountID is empty, if requester is allowed to manage it.
+func (n *rssTooter) CreateWebhook(ctx context.Context, requester *gtsmodel.User, accountID string, form *apimodel.RssWebhookCreateRequest) (*apimodel.RssWebhook, gtserror.WithCode) {
+	if errWithCode := n.checkWebhookAccess(ctx, requester, accountID); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if u, err := url.Parse(form.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		err := fmt.Errorf("invalid webhook url %s", form.URL)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		err := gtserror.Newf("error generating webhook secret: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	webhook := &gtsmodel.RssWebhook{
+		ID:        id.NewULID(),
+		CreatedAt: time.Now(),
+		AccountID: accountID,
+		URL:       form.URL,
+		Secret:    feedTokenEncoding.EncodeToString(secret),
+	}
+
+	if err := n.PutRssWebhook(ctx, webhook); err != nil {
+		err := gtserror.Newf("db error putting webhook: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return apiWebhook(webhook), nil
+}
+
+// DeleteWebhook deletes a webhook with its pending and failed
+// deliveries, if requester is allowed to manage it.
+func (n *rssTooter) DeleteWebhook(ctx context.Context, requester *gtsmodel.User, webhookID string) gtserror.WithCode {
+	webhook, err := n.GetRssWebhookByID(ctx, webhookID)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err := fmt.Errorf("webhook %s not found", webhookID)
+			return gtserror.NewErrorNotFound(err, err.Error())
+		}
+		err := gtserror.Newf("db error getting webhook: %w", err)
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	if errWithCode := n.checkWebhookAccess(ctx, requester, webhook.AccountID); errWithCode != nil {
+		return errWithCode
+	}
+
+	if err := n.DeleteRssWebhookByID(ctx, webhook.ID); err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err := fmt.Errorf("webhook %s not found", webhookID)
+			return gtserror.NewErrorNotFound(err, err.Error())
+		}
+		err := gtserror.Newf("db error deleting webhook: %w", err)
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	return nil
+}
+
+// GetFailedWebhookDeliveries returns the deliveries given up on, most recent first, if requester is an admin.
+func (n *rssTooter) GetFailedWebhookDeliveries(ctx context.Context, requester *gtsmodel.User) ([]*apimodel.RssWebhookDelivery, gtserror.WithCode) {
+	if !*requester.Admin {
+		err := fmt.Errorf("user %s not allowed to list webhook deliveries", requester.ID)
+		return nil, gtserror.NewErrorForbidden(err, err.Error())
+	}
+
+	deliveries, err := n.GetFailedRssWebhookDeliveries(ctx, webhookBatchSize)
+	if err != nil {
+		err := gtserror.Newf("db error getting webhook deliveries: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiDeliveries := make([]*apimodel.RssWebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		apiDelivery, errWithCode := n.apiWebhookDelivery(ctx, delivery)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+		apiDeliveries = append(apiDeliveries, apiDelivery)
+	}
+
+	return apiDeliveries, nil
+}
+
+// RetryWebhookDelivery schedules a delivery given up on to be attempted
+// again by the next deliverWebhooks, with all its attempts, if requester is an admin.
+func (n *rssTooter) RetryWebhookDelivery(ctx context.Context, requester *gtsmodel.User, deliveryID string) (*apimodel.RssWebhookDelivery, gtserror.WithCode) {
+	if !*requester.Admin {
+		err := fmt.Errorf("user %s not allowed to retry webhook deliveries", requester.ID)
+		return nil, gtserror.NewErrorForbidden(err, err.Error())
+	}
+
+	delivery, err := n.GetRssWebhookDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err := fmt.Errorf("webhook delivery %s not found", deliveryID)
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		err := gtserror.Newf("db error getting webhook delivery: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if delivery.FailedAt.IsZero() {
+		err := fmt.Errorf("webhook delivery %s is still pending", deliveryID)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	delivery.FailedAt = time.Time{}
+	if err := n.UpdateRssWebhookDelivery(ctx, delivery, "attempts", "next_attempt_at", "failed_at"); err != nil {
+		err := gtserror.Newf("db error updating webhook delivery: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return n.apiWebhookDelivery(ctx, delivery)
+}
+
+// apiWebhook converts a webhook to its API model.
+func apiWebhook(webhook *gtsmodel.RssWebhook) *apimodel.RssWebhook {
+	var accountID *string
+	if webhook.AccountID != "" {
+		accountID = util.Ptr(webhook.AccountID)
+	}
+
+	return &apimodel.RssWebhook{
+		ID:        webhook.ID,
+		CreatedAt: util.FormatISO8601(webhook.CreatedAt),
+		AccountID: accountID,
+		URL:       webhook.URL,
+		Secret:    webhook.Secret,
+	}
+}
+
+// apiWebhookDelivery converts a webhook delivery to its API model, with its webhook.
+func (n *rssTooter) apiWebhookDelivery(ctx context.Context, delivery *gtsmodel.RssWebhookDelivery) (*apimodel.RssWebhookDelivery, gtserror.WithCode) {
+	webhook, err := n.deliveryWebhook(ctx, delivery)
+	if err != nil {
+		err := gtserror.Newf("db error getting webhook %s: %w", delivery.WebhookID, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiDelivery := &apimodel.RssWebhookDelivery{
+		ID:        delivery.ID,
+		CreatedAt: util.FormatISO8601(delivery.CreatedAt),
+		Webhook:   apiWebhook(webhook),
+		Event:     delivery.Event,
+		StatusID:  delivery.StatusID,
+		Attempts:  delivery.Attempts,
+		LastError: delivery.LastError,
+	}
+	if !delivery.LastAttemptAt.IsZero() {
+		apiDelivery.LastAttemptAt = util.FormatISO8601(delivery.LastAttemptAt)
+	}
+	if !delivery.FailedAt.IsZero() {
+		apiDelivery.FailedAt = util.FormatISO8601(delivery.FailedAt)
+	}
+
+	return apiDelivery, nil
+}
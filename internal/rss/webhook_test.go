@@ -0,0 +1,377 @@
+package rss
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type WebhookTestSuite struct {
+	RssStandardTestSuite
+
+	receiver *webhookReceiver
+}
+
+// webhookReceiver is a webhook recording the payloads of the
+// requests correctly signed with its secret, and the replies.
+type webhookReceiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	secret   string
+	status   int
+	payloads []webhookPayload
+	replies  []replyWebhook
+}
+
+func newWebhookReceiver() *webhookReceiver {
+	receiver := &webhookReceiver{status: http.StatusNoContent}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil || r.Header.Get("Content-Type") != "application/json" || r.Header.Get("X-Feed-Delivery") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		signature := "sha256=" + webhookSignature(receiver.secret, body)
+		if !hmac.Equal([]byte(signature), []byte(r.Header.Get("X-Feed-Signature"))) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.Header.Get("X-Feed-Event") == webhookEventReplyCreated {
+			var reply replyWebhook
+			if err := json.Unmarshal(body, &reply); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if receiver.status < 300 {
+				receiver.replies = append(receiver.replies, reply)
+			}
+			w.WriteHeader(receiver.status)
+			return
+		}
+
+		var payload webhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if receiver.status < 300 {
+			receiver.payloads = append(receiver.payloads, payload)
+		}
+		w.WriteHeader(receiver.status)
+	}))
+	return receiver
+}
+
+func (r *webhookReceiver) set(secret string, status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.secret, r.status = secret, status
+}
+
+func (r *webhookReceiver) received() []webhookPayload {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.payloads
+}
+
+func (r *webhookReceiver) receivedReplies() []replyWebhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.replies
+}
+
+func (suite *WebhookTestSuite) SetupTest() {
+	suite.RssStandardTestSuite.SetupTest()
+	suite.receiver = newWebhookReceiver()
+}
+
+func (suite *WebhookTestSuite) TearDownTest() {
+	suite.receiver.Close()
+	suite.RssStandardTestSuite.TearDownTest()
+}
+
+func (suite *WebhookTestSuite) TestDeliverWebhooks() {
+	ctx := context.Background()
+	admin := suite.testUsers["admin_account"]
+	account := suite.testAccounts["local_account_1"]
+	other := suite.testAccounts["local_account_2"]
+
+	if err := suite.tooter.PutRssFeed(ctx, &gtsmodel.RssFeed{
+		ID:        id.NewULID(),
+		AccountID: account.ID,
+		URL:       suite.fixtureURL("rss2.xml"),
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// The feed and server-wide webhooks receive the statuses,
+	// not the webhook of another account.
+	webhook, errWithCode := suite.tooter.CreateWebhook(ctx, admin, account.ID, &apimodel.RssWebhookCreateRequest{URL: suite.receiver.URL + "/feed"})
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+	suite.Equal(account.ID, *webhook.AccountID)
+	serverWide, errWithCode := suite.tooter.CreateWebhook(ctx, admin, "", &apimodel.RssWebhookCreateRequest{URL: suite.receiver.URL + "/all"})
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+	suite.Nil(serverWide.AccountID)
+	if err := suite.tooter.PutRssWebhook(ctx, &gtsmodel.RssWebhook{
+		ID:        id.NewULID(),
+		AccountID: other.ID,
+		URL:       suite.receiver.URL + "/other",
+		Secret:    "other",
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// Both webhooks share the secret, to be checked by the same receiver.
+	if _, err := suite.state.DB.DB().NewUpdate().
+		TableExpr("rss_webhooks").
+		Set("secret = ?", webhook.Secret).
+		Where("id = ?", serverWide.ID).
+		Exec(ctx); err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.receiver.set(webhook.Secret, http.StatusNoContent)
+
+	sinceID := id.NewULID()
+	suite.tooter.poll(ctx)
+
+	statuses, err := suite.tooter.GetStatusesSince(ctx, account.ID, sinceID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.NotEmpty(statuses)
+
+	due, err := suite.tooter.GetDueRssWebhookDeliveries(ctx, time.Now(), 1000)
+	suite.NoError(err)
+	suite.Len(due, 2*len(statuses))
+
+	suite.tooter.deliverWebhooks(ctx, time.Now())
+
+	payloads := suite.receiver.received()
+	suite.Len(payloads, 2*len(statuses))
+	for _, payload := range payloads {
+		suite.Equal(webhookEventStatusCreated, payload.Event)
+		suite.Equal(account.ID, payload.Feed.AccountID)
+		suite.Equal(suite.fixtureURL("rss2.xml"), payload.Feed.URL)
+		suite.Equal(account.ID, payload.Status.Account.ID)
+		suite.Equal(payload.Item.Link, payload.Status.URL)
+		suite.NotEmpty(payload.Item.Title)
+	}
+
+	// Delivered deliveries are deleted.
+	due, err = suite.tooter.GetDueRssWebhookDeliveries(ctx, time.Now(), 1000)
+	suite.NoError(err)
+	suite.Empty(due)
+}
+
+func (suite *WebhookTestSuite) TestWebhooksOfStoredItems() {
+	ctx := context.Background()
+	admin := suite.testUsers["admin_account"]
+	backfilled := suite.testAccounts["local_account_1"]
+	digested := suite.testAccounts["local_account_2"]
+
+	if _, errWithCode := suite.tooter.CreateWebhook(ctx, admin, "", &apimodel.RssWebhookCreateRequest{URL: suite.receiver.URL}); errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+
+	// Items waiting for a digest are new items of their feed too, though
+	// only stored for now, unlike backfilled items, the past of the feed.
+	sinceID := id.NewULID()
+	queued := suite.state.Workers.Client.Queue.Len()
+	_, feed, err := NewRssFeed(&suite.state, suite.tooter.fetcher, ctx, suite.fixtureURL("rss2.xml"))
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.tooter.backfill(ctx, backfilled, feed)
+
+	if err := suite.tooter.PutRssFeed(ctx, &gtsmodel.RssFeed{
+		ID:                 id.NewULID(),
+		AccountID:          digested.ID,
+		URL:                suite.fixtureURL("atom.xml"),
+		Digest:             gtsmodel.RssDigestDaily,
+		DigestedAt:         time.Now(),
+		DigestLastStatusID: id.NewULID(),
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.tooter.poll(ctx)
+	suite.Equal(queued, suite.state.Workers.Client.Queue.Len())
+
+	for account, expected := range map[*gtsmodel.Account]int{backfilled: 0, digested: 1} {
+		statuses, err := suite.tooter.GetStatusesSince(ctx, account.ID, sinceID)
+		suite.NoError(err)
+		suite.NotEmpty(statuses)
+
+		for _, status := range statuses {
+			deliveries, err := suite.state.DB.DB().NewSelect().
+				TableExpr("rss_webhook_deliveries").
+				Where("status_id = ?", status.ID).
+				Count(ctx)
+			suite.NoError(err)
+			suite.Equal(expected, deliveries, status.URL)
+		}
+	}
+}
+
+func (suite *WebhookTestSuite) TestRetryWebhookDelivery() {
+	ctx := context.Background()
+	admin := suite.testUsers["admin_account"]
+	user := suite.testUsers["local_account_1"]
+	account := suite.testAccounts["local_account_1"]
+	status := testrig.NewTestStatuses()["local_account_1_status_1"]
+
+	webhook, errWithCode := suite.tooter.CreateWebhook(ctx, admin, "", &apimodel.RssWebhookCreateRequest{URL: suite.receiver.URL})
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+	suite.receiver.set(webhook.Secret, http.StatusBadGateway)
+
+	item := &gofeed.Item{Title: "Hello", Link: "https://blog.example.org/hello"}
+	if err := suite.tooter.enqueueWebhooks(ctx, &ToCreate{Account: account, Item: item}, status); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// Failed attempts are retried with a growing delay, until given up on.
+	now := time.Now()
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		suite.tooter.deliverWebhooks(ctx, now)
+
+		deliveries, err := suite.tooter.GetFailedRssWebhookDeliveries(ctx, 10)
+		suite.NoError(err)
+		if attempt < webhookMaxAttempts {
+			suite.Empty(deliveries)
+
+			// Not due before its delay.
+			delay := webhookRetryDelay << (attempt - 1)
+			due, err := suite.tooter.GetDueRssWebhookDeliveries(ctx, now.Add(delay-time.Second), 10)
+			suite.NoError(err)
+			suite.Empty(due)
+			due, err = suite.tooter.GetDueRssWebhookDeliveries(ctx, now.Add(delay), 10)
+			suite.NoError(err)
+			suite.Len(due, 1)
+			suite.Equal(attempt, due[0].Attempts)
+			now = now.Add(delay)
+		} else {
+			suite.Len(deliveries, 1)
+		}
+	}
+
+	_, errWithCode = suite.tooter.GetFailedWebhookDeliveries(ctx, user)
+	suite.Equal(http.StatusForbidden, errWithCode.Code())
+
+	deliveries, errWithCode := suite.tooter.GetFailedWebhookDeliveries(ctx, admin)
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+	suite.Len(deliveries, 1)
+	suite.Equal(webhookMaxAttempts, deliveries[0].Attempts)
+	suite.Equal(status.ID, deliveries[0].StatusID)
+	suite.Equal(webhook.ID, deliveries[0].Webhook.ID)
+	suite.Contains(deliveries[0].LastError, "502")
+	suite.NotEmpty(deliveries[0].FailedAt)
+
+	// Retried deliveries start over.
+	_, errWithCode = suite.tooter.RetryWebhookDelivery(ctx, user, deliveries[0].ID)
+	suite.Equal(http.StatusForbidden, errWithCode.Code())
+
+	delivery, errWithCode := suite.tooter.RetryWebhookDelivery(ctx, admin, deliveries[0].ID)
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+	suite.Zero(delivery.Attempts)
+	suite.Empty(delivery.FailedAt)
+
+	_, errWithCode = suite.tooter.RetryWebhookDelivery(ctx, admin, deliveries[0].ID)
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+
+	suite.receiver.set(webhook.Secret, http.StatusOK)
+	suite.tooter.deliverWebhooks(ctx, time.Now())
+
+	payloads := suite.receiver.received()
+	suite.Len(payloads, 1)
+	suite.Equal(status.ID, payloads[0].Status.ID)
+	suite.Equal("Hello", payloads[0].Item.Title)
+	_, err := suite.tooter.GetRssWebhookDeliveryByID(ctx, delivery.ID)
+	suite.Error(err)
+}
+
+func (suite *WebhookTestSuite) TestManageWebhooks() {
+	ctx := context.Background()
+	admin := suite.testUsers["admin_account"]
+	user := suite.testUsers["local_account_2"]
+	account := suite.testAccounts["local_account_1"]
+
+	if err := suite.tooter.PutRssFeed(ctx, &gtsmodel.RssFeed{
+		ID:        id.NewULID(),
+		AccountID: account.ID,
+		URL:       suite.fixtureURL("rss2.xml"),
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	_, errWithCode := suite.tooter.CreateWebhook(ctx, admin, account.ID, &apimodel.RssWebhookCreateRequest{URL: "ftp://example.org/hook"})
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+
+	// Only admins manage server-wide webhooks, and admins or delegates those of a feed.
+	_, errWithCode = suite.tooter.CreateWebhook(ctx, user, "", &apimodel.RssWebhookCreateRequest{URL: suite.receiver.URL})
+	suite.Equal(http.StatusForbidden, errWithCode.Code())
+	_, errWithCode = suite.tooter.GetWebhooks(ctx, user, account.ID)
+	suite.Equal(http.StatusForbidden, errWithCode.Code())
+
+	if _, errWithCode := suite.tooter.AddFeedDelegate(ctx, admin, account.ID, user.AccountID); errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+	webhook, errWithCode := suite.tooter.CreateWebhook(ctx, user, account.ID, &apimodel.RssWebhookCreateRequest{URL: suite.receiver.URL})
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+	suite.NotEmpty(webhook.Secret)
+
+	webhooks, errWithCode := suite.tooter.GetWebhooks(ctx, user, account.ID)
+	suite.Nil(errWithCode)
+	suite.Len(webhooks, 1)
+	webhooks, errWithCode = suite.tooter.GetWebhooks(ctx, admin, "")
+	suite.Nil(errWithCode)
+	suite.Empty(webhooks)
+
+	// Deleting a webhook drops its pending deliveries.
+	status := testrig.NewTestStatuses()["local_account_1_status_1"]
+	item := &gofeed.Item{Title: "Hello", Link: "https://blog.example.org/hello"}
+	if err := suite.tooter.enqueueWebhooks(ctx, &ToCreate{Account: account, Item: item}, status); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Nil(suite.tooter.DeleteWebhook(ctx, user, webhook.ID))
+	suite.Equal(http.StatusNotFound, suite.tooter.DeleteWebhook(ctx, user, webhook.ID).Code())
+
+	due, err := suite.tooter.GetDueRssWebhookDeliveries(ctx, time.Now(), 10)
+	suite.NoError(err)
+	suite.Empty(due)
+}
+
+func TestWebhookTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookTestSuite))
+}
@@ -23,6 +23,7 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"html"
 	"net/url"
 	"strings"
 
@@ -38,9 +39,10 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/uris"
 )
 
-// AccountToAS converts a gts model account into an activity streams person, suitable for federation
-func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab.ActivityStreamsPerson, error) {
-	person := streams.NewActivityStreamsPerson()
+// AccountToAS converts a gts model account into an activity streams actor, suitable for federation.
+// The actor is a Person, unless the account has the Service or Application actor type.
+func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (ap.Accountable, error) {
+	accountable := newAccountable(a)
 
 	// id should be the activitypub URI of this user
 	// something like https://example.org/users/example_user
@@ -50,7 +52,7 @@ func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 	}
 	idProp := streams.NewJSONLDIdProperty()
 	idProp.SetIRI(profileIDURI)
-	person.SetJSONLDId(idProp)
+	accountable.SetJSONLDId(idProp)
 
 	// following
 	// The URI for retrieving a list of accounts this user is following
@@ -60,7 +62,7 @@ func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 	}
 	followingProp := streams.NewActivityStreamsFollowingProperty()
 	followingProp.SetIRI(followingURI)
-	person.SetActivityStreamsFollowing(followingProp)
+	accountable.SetActivityStreamsFollowing(followingProp)
 
 	// followers
 	// The URI for retrieving a list of this user's followers
@@ -70,7 +72,7 @@ func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 	}
 	followersProp := streams.NewActivityStreamsFollowersProperty()
 	followersProp.SetIRI(followersURI)
-	person.SetActivityStreamsFollowers(followersProp)
+	accountable.SetActivityStreamsFollowers(followersProp)
 
 	// inbox
 	// the activitypub inbox of this user for accepting messages
@@ -80,7 +82,7 @@ func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 	}
 	inboxProp := streams.NewActivityStreamsInboxProperty()
 	inboxProp.SetIRI(inboxURI)
-	person.SetActivityStreamsInbox(inboxProp)
+	accountable.SetActivityStreamsInbox(inboxProp)
 
 	// shared inbox -- only add this if we know for sure it has one
 	if a.SharedInboxURI != nil && *a.SharedInboxURI != "" {
@@ -94,7 +96,7 @@ func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 		sharedInboxProp.SetIRI(sharedInboxURI)
 		endpoints.SetActivityStreamsSharedInbox(sharedInboxProp)
 		endpointsProp.AppendActivityStreamsEndpoints(endpoints)
-		person.SetActivityStreamsEndpoints(endpointsProp)
+		accountable.SetActivityStreamsEndpoints(endpointsProp)
 	}
 
 	// outbox
@@ -105,7 +107,7 @@ func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 	}
 	outboxProp := streams.NewActivityStreamsOutboxProperty()
 	outboxProp.SetIRI(outboxURI)
-	person.SetActivityStreamsOutbox(outboxProp)
+	accountable.SetActivityStreamsOutbox(outboxProp)
 
 	// featured posts
 	// Pinned posts.
@@ -115,7 +117,7 @@ func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 	}
 	featuredProp := streams.NewTootFeaturedProperty()
 	featuredProp.SetIRI(featuredURI)
-	person.SetTootFeatured(featuredProp)
+	accountable.SetTootFeatured(featuredProp)
 
 	// featuredTags
 	// NOT IMPLEMENTED
@@ -124,7 +126,7 @@ func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 	// Used for Webfinger lookup. Must be unique on the domain, and must correspond to a Webfinger acct: URI.
 	preferredUsernameProp := streams.NewActivityStreamsPreferredUsernameProperty()
 	preferredUsernameProp.SetXMLSchemaString(a.Username)
-	person.SetActivityStreamsPreferredUsername(preferredUsernameProp)
+	accountable.SetActivityStreamsPreferredUsername(preferredUsernameProp)
 
 	// name
 	// Used as profile display name.
@@ -134,37 +136,57 @@ func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 	} else {
 		nameProp.AppendXMLSchemaString(a.Username)
 	}
-	person.SetActivityStreamsName(nameProp)
+	accountable.SetActivityStreamsName(nameProp)
 
 	// summary
 	// Used as profile bio.
 	if a.Note != "" {
 		summaryProp := streams.NewActivityStreamsSummaryProperty()
 		summaryProp.AppendXMLSchemaString(a.Note)
-		person.SetActivityStreamsSummary(summaryProp)
+		accountable.SetActivityStreamsSummary(summaryProp)
 	}
 
 	// url
-	// Used as profile link.
+	// Used as profile link. Feed accounts link
+	// their source feed, with its media type.
+	feed := c.accountRssFeed(ctx, a)
 	profileURL, err := url.Parse(a.URL)
 	if err != nil {
 		return nil, err
 	}
 	urlProp := streams.NewActivityStreamsUrlProperty()
-	urlProp.AppendIRI(profileURL)
-	person.SetActivityStreamsUrl(urlProp)
+	if feed != nil {
+		feedURL, err := url.Parse(feed.URL)
+		if err != nil {
+			return nil, err
+		}
+
+		link := streams.NewActivityStreamsLink()
+		hrefProp := streams.NewActivityStreamsHrefProperty()
+		hrefProp.SetIRI(feedURL)
+		link.SetActivityStreamsHref(hrefProp)
+		if feed.MediaType != "" {
+			mediaTypeProp := streams.NewActivityStreamsMediaTypeProperty()
+			mediaTypeProp.Set(feed.MediaType)
+			link.SetActivityStreamsMediaType(mediaTypeProp)
+		}
+		urlProp.AppendActivityStreamsLink(link)
+	} else {
+		urlProp.AppendIRI(profileURL)
+	}
+	accountable.SetActivityStreamsUrl(urlProp)
 
 	// manuallyApprovesFollowers
 	// Will be shown as a locked account.
 	manuallyApprovesFollowersProp := streams.NewActivityStreamsManuallyApprovesFollowersProperty()
 	manuallyApprovesFollowersProp.Set(*a.Locked)
-	person.SetActivityStreamsManuallyApprovesFollowers(manuallyApprovesFollowersProp)
+	accountable.SetActivityStreamsManuallyApprovesFollowers(manuallyApprovesFollowersProp)
 
 	// discoverable
 	// Will be shown in the profile directory.
 	discoverableProp := streams.NewTootDiscoverableProperty()
 	discoverableProp.Set(*a.Discoverable)
-	person.SetTootDiscoverable(discoverableProp)
+	accountable.SetTootDiscoverable(discoverableProp)
 
 	// devices
 	// NOT IMPLEMENTED, probably won't implement
@@ -182,7 +204,7 @@ func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 			alsoKnownAsURIs[i] = uri
 		}
 
-		ap.SetAlsoKnownAs(person, alsoKnownAsURIs)
+		ap.SetAlsoKnownAs(accountable, alsoKnownAsURIs)
 	}
 
 	// movedTo
@@ -193,7 +215,7 @@ func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 			return nil, err
 		}
 
-		ap.SetMovedTo(person, movedTo)
+		ap.SetMovedTo(accountable, movedTo)
 	}
 
 	// publicKey
@@ -233,8 +255,8 @@ func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 	// append the public key to the public key property
 	publicKeyProp.AppendW3IDSecurityV1PublicKey(publicKey)
 
-	// set the public key property on the Person
-	person.SetW3IDSecurityV1PublicKey(publicKeyProp)
+	// set the public key property on the actor
+	accountable.SetW3IDSecurityV1PublicKey(publicKeyProp)
 
 	// tags
 	tagProp := streams.NewActivityStreamsTagProperty()
@@ -262,11 +284,12 @@ func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 	// tag -- hashtags
 	// TODO
 
-	person.SetActivityStreamsTag(tagProp)
+	accountable.SetActivityStreamsTag(tagProp)
 
 	// attachment
-	// Used for profile fields.
-	if len(a.Fields) != 0 {
+	// Used for profile fields, and the
+	// source feed of feed accounts.
+	if len(a.Fields) != 0 || feed != nil {
 		attachmentProp := streams.NewActivityStreamsAttachmentProperty()
 
 		for _, field := range a.Fields {
@@ -283,7 +306,21 @@ func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 			attachmentProp.AppendSchemaPropertyValue(propertyValue)
 		}
 
-		person.SetActivityStreamsAttachment(attachmentProp)
+		if feed != nil {
+			propertyValue := streams.NewSchemaPropertyValue()
+
+			nameProp := streams.NewActivityStreamsNameProperty()
+			nameProp.AppendXMLSchemaString("Feed")
+			propertyValue.SetActivityStreamsName(nameProp)
+
+			valueProp := streams.NewSchemaValueProperty()
+			valueProp.Set(`<a href="` + html.EscapeString(feed.URL) + `" rel="nofollow noreferrer noopener" target="_blank">` + html.EscapeString(feed.URL) + `</a>`)
+			propertyValue.SetSchemaValue(valueProp)
+
+			attachmentProp.AppendSchemaPropertyValue(propertyValue)
+		}
+
+		accountable.SetActivityStreamsAttachment(attachmentProp)
 	}
 
 	// endpoints
@@ -319,7 +356,7 @@ func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 			iconImage.SetActivityStreamsUrl(avatarURLProperty)
 
 			iconProperty.AppendActivityStreamsImage(iconImage)
-			person.SetActivityStreamsIcon(iconProperty)
+			accountable.SetActivityStreamsIcon(iconProperty)
 		}
 	}
 
@@ -353,20 +390,20 @@ func (c *Converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 			headerImage.SetActivityStreamsUrl(headerURLProperty)
 
 			headerProperty.AppendActivityStreamsImage(headerImage)
-			person.SetActivityStreamsImage(headerProperty)
+			accountable.SetActivityStreamsImage(headerProperty)
 		}
 	}
 
-	return person, nil
+	return accountable, nil
 }
 
-// AccountToASMinimal converts a gts model account into an activity streams person, suitable for federation.
+// AccountToASMinimal converts a gts model account into an activity streams actor, suitable for federation.
 //
 // The returned account will just have the Type, Username, PublicKey, and ID properties set. This is
 // suitable for serving to requesters to whom we want to give as little information as possible because
 // we don't trust them (yet).
-func (c *Converter) AccountToASMinimal(ctx context.Context, a *gtsmodel.Account) (vocab.ActivityStreamsPerson, error) {
-	person := streams.NewActivityStreamsPerson()
+func (c *Converter) AccountToASMinimal(ctx context.Context, a *gtsmodel.Account) (ap.Accountable, error) {
+	accountable := newAccountable(a)
 
 	// id should be the activitypub URI of this user
 	// something like https://example.org/users/example_user
@@ -376,13 +413,13 @@ func (c *Converter) AccountToASMinimal(ctx context.Context, a *gtsmodel.Account)
 	}
 	idProp := streams.NewJSONLDIdProperty()
 	idProp.SetIRI(profileIDURI)
-	person.SetJSONLDId(idProp)
+	accountable.SetJSONLDId(idProp)
 
 	// preferredUsername
 	// Used for Webfinger lookup. Must be unique on the domain, and must correspond to a Webfinger acct: URI.
 	preferredUsernameProp := streams.NewActivityStreamsPreferredUsernameProperty()
 	preferredUsernameProp.SetXMLSchemaString(a.Username)
-	person.SetActivityStreamsPreferredUsername(preferredUsernameProp)
+	accountable.SetActivityStreamsPreferredUsername(preferredUsernameProp)
 
 	// publicKey
 	// Required for signatures.
@@ -421,10 +458,41 @@ func (c *Converter) AccountToASMinimal(ctx context.Context, a *gtsmodel.Account)
 	// append the public key to the public key property
 	publicKeyProp.AppendW3IDSecurityV1PublicKey(publicKey)
 
-	// set the public key property on the Person
-	person.SetW3IDSecurityV1PublicKey(publicKeyProp)
+	// set the public key property on the actor
+	accountable.SetW3IDSecurityV1PublicKey(publicKeyProp)
+
+	return accountable, nil
+}
+
+// accountRssFeed returns the source feed of a
+// if it's a local feed account, or nil.
+func (c *Converter) accountRssFeed(ctx context.Context, a *gtsmodel.Account) *gtsmodel.RssFeed {
+	if !a.IsLocal() || a.ActorType != ap.ActorService {
+		return nil
+	}
+
+	feed, err := c.state.DB.GetRssFeedByAccountID(ctx, a.ID)
+	if err != nil {
+		if !errors.Is(err, db.ErrNoEntries) {
+			log.Errorf(ctx, "error getting feed of account %s: %v", a.ID, err)
+		}
+		return nil
+	}
 
-	return person, nil
+	return feed
+}
+
+// newAccountable returns an empty activity streams actor
+// of the actor type of a, a Person for other types.
+func newAccountable(a *gtsmodel.Account) ap.Accountable {
+	switch a.ActorType {
+	case ap.ActorService:
+		return streams.NewActivityStreamsService()
+	case ap.ActorApplication:
+		return streams.NewActivityStreamsApplication()
+	default:
+		return streams.NewActivityStreamsPerson()
+	}
 }
 
 // StatusToAS converts a gts model status into an ActivityStreams Statusable implementation, suitable for federation
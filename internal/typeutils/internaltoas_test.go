@@ -138,6 +138,84 @@ func (suite *InternalToASTestSuite) TestAccountToASWithFields() {
 }`, trimmed)
 }
 
+func (suite *InternalToASTestSuite) TestAccountToASFeed() {
+	testAccount := &gtsmodel.Account{}
+	*testAccount = *suite.testAccounts["local_account_2"]
+
+	ctx := context.Background()
+
+	// Suppose turtle is the account of a feed.
+	testAccount.ActorType = ap.ActorService
+	if _, err := suite.state.DB.DB().NewInsert().Model(&gtsmodel.RssFeed{
+		ID:        "01J0DQ4ZJ7X8B3Y0QH6GT2K3XW",
+		AccountID: testAccount.ID,
+		URL:       "https://blog.example.org/feed.xml",
+		MediaType: "application/atom+xml",
+	}).Exec(ctx); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	asService, err := suite.typeconverter.AccountToAS(ctx, testAccount)
+	suite.NoError(err)
+
+	ser, err := ap.Serialize(asService)
+	suite.NoError(err)
+
+	bytes, err := json.MarshalIndent(ser, "", "  ")
+	suite.NoError(err)
+
+	// trim off everything up to 'attachment';
+	// this is necessary because the order of multiple 'context' entries is not determinate
+	trimmed := strings.Split(string(bytes), "\"attachment\"")[1]
+
+	suite.Equal(`: [
+    {
+      "name": "should you follow me?",
+      "type": "PropertyValue",
+      "value": "maybe!"
+    },
+    {
+      "name": "age",
+      "type": "PropertyValue",
+      "value": "120"
+    },
+    {
+      "name": "Feed",
+      "type": "PropertyValue",
+      "value": "\u003ca href=\"https://blog.example.org/feed.xml\" rel=\"nofollow noreferrer noopener\" target=\"_blank\"\u003ehttps://blog.example.org/feed.xml\u003c/a\u003e"
+    }
+  ],
+  "discoverable": false,
+  "featured": "http://localhost:8080/users/1happyturtle/collections/featured",
+  "followers": "http://localhost:8080/users/1happyturtle/followers",
+  "following": "http://localhost:8080/users/1happyturtle/following",
+  "id": "http://localhost:8080/users/1happyturtle",
+  "inbox": "http://localhost:8080/users/1happyturtle/inbox",
+  "manuallyApprovesFollowers": true,
+  "name": "happy little turtle :3",
+  "outbox": "http://localhost:8080/users/1happyturtle/outbox",
+  "preferredUsername": "1happyturtle",
+  "publicKey": {
+    "id": "http://localhost:8080/users/1happyturtle#main-key",
+    "owner": "http://localhost:8080/users/1happyturtle",
+    "publicKeyPem": "-----BEGIN PUBLIC KEY-----\nMIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEAtTc6Jpg6LrRPhVQG4KLz\n2+YqEUUtZPd4YR+TKXuCnwEG9ZNGhgP046xa9h3EWzrZXaOhXvkUQgJuRqPrAcfN\nvc8jBHV2xrUeD8pu/MWKEabAsA/tgCv3nUC47HQ3/c12aHfYoPz3ufWsGGnrkhci\nv8PaveJ3LohO5vjCn1yZ00v6osMJMViEZvZQaazyE9A8FwraIexXabDpoy7tkHRg\nA1fvSkg4FeSG1XMcIz2NN7xyUuFACD+XkuOk7UqzRd4cjPUPLxiDwIsTlcgGOd3E\nUFMWVlPxSGjY2hIKa3lEHytaYK9IMYdSuyCsJshd3/yYC9LqxZY2KdlKJ80VOVyh\nyQIDAQAB\n-----END PUBLIC KEY-----\n"
+  },
+  "summary": "\u003cp\u003ei post about things that concern me\u003c/p\u003e",
+  "tag": [],
+  "type": "Service",
+  "url": {
+    "href": "https://blog.example.org/feed.xml",
+    "mediaType": "application/atom+xml",
+    "type": "Link"
+  }
+}`, trimmed)
+
+	// The feed is read back as the url of the account.
+	feedURL, err := ap.ExtractURL(asService)
+	suite.NoError(err)
+	suite.Equal("https://blog.example.org/feed.xml", feedURL.String())
+}
+
 func (suite *InternalToASTestSuite) TestAccountToASAliasedAndMoved() {
 	testAccount := &gtsmodel.Account{}
 	*testAccount = *suite.testAccounts["local_account_1"] // take zork for this test
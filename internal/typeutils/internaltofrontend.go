@@ -1185,7 +1185,7 @@ func (c *Converter) baseStatusToFrontend(
 		Mentions:           apiMentions,
 		Tags:               apiTags,
 		Emojis:             apiEmojis,
-		Card:               nil, // TODO: implement cards
+		Card:               nil, // Set below.
 		Text:               s.Text,
 	}
 
@@ -1212,6 +1212,10 @@ func (c *Converter) baseStatusToFrontend(
 		}
 	}
 
+	if s.PreviewCard != nil {
+		apiStatus.Card = c.PreviewCardToAPICard(ctx, s.PreviewCard)
+	}
+
 	if s.Poll != nil {
 		// Set originating
 		// status on the poll.
@@ -1825,6 +1829,37 @@ func (c *Converter) MarkersToAPIMarker(ctx context.Context, markers []*gtsmodel.
 	return apiMarker, nil
 }
 
+// PreviewCardToAPICard converts a database (gtsmodel) PreviewCard into an API model representation.
+func (c *Converter) PreviewCardToAPICard(ctx context.Context, card *gtsmodel.PreviewCard) *apimodel.Card {
+	apiCard := &apimodel.Card{
+		URL:          card.URL,
+		Title:        card.Title,
+		Description:  card.Description,
+		Type:         string(card.Type),
+		AuthorName:   card.AuthorName,
+		AuthorURL:    card.AuthorURL,
+		ProviderName: card.ProviderName,
+		ProviderURL:  card.ProviderURL,
+		HTML:         card.HTML,
+		Width:        card.Width,
+		Height:       card.Height,
+		EmbedURL:     card.EmbedURL,
+	}
+
+	if image := card.Image; image != nil {
+		apiCard.Image = image.URL
+		apiCard.Blurhash = image.Blurhash
+
+		if apiCard.Width == 0 || apiCard.Height == 0 {
+			// Fall back to the size of the cached image.
+			apiCard.Width = image.FileMeta.Original.Width
+			apiCard.Height = image.FileMeta.Original.Height
+		}
+	}
+
+	return apiCard
+}
+
 // PollToAPIPoll converts a database (gtsmodel) Poll into an API model representation appropriate for the given requesting account.
 func (c *Converter) PollToAPIPoll(ctx context.Context, requester *gtsmodel.Account, poll *gtsmodel.Poll) (*apimodel.Poll, error) {
 	// Ensure the poll model is fully populated for src status.
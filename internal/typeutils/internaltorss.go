@@ -19,9 +19,11 @@ package typeutils
 
 import (
 	"context"
+	"encoding/xml"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/feeds"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
@@ -29,6 +31,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/log"
 	"github.com/superseriousbusiness/gotosocial/internal/text"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
 
 const (
@@ -36,6 +39,37 @@ const (
 	rssDescriptionMaxRunes = 256
 )
 
+// FeedFormat is a syndication format a feed can be serialized to.
+type FeedFormat int
+
+const (
+	FeedFormatRSS  FeedFormat = iota // RSS 2.0
+	FeedFormatAtom                   // Atom 1.0
+	FeedFormatJSON                   // JSON Feed 1.1
+)
+
+// Extension returns the file extension of documents in this format.
+func (f FeedFormat) Extension() string {
+	switch f {
+	case FeedFormatAtom:
+		return "atom"
+	case FeedFormatJSON:
+		return "json"
+	default:
+		return "rss"
+	}
+}
+
+// ParseFeedFormat returns the format with the given file extension.
+func ParseFeedFormat(extension string) (FeedFormat, bool) {
+	for _, f := range []FeedFormat{FeedFormatRSS, FeedFormatAtom, FeedFormatJSON} {
+		if f.Extension() == extension {
+			return f, true
+		}
+	}
+	return FeedFormatRSS, false
+}
+
 func (c *Converter) StatusToRSSItem(ctx context.Context, s *gtsmodel.Status) (*feeds.Item, error) {
 	// see https://cyber.harvard.edu/rss/rss.html
 
@@ -44,8 +78,11 @@ func (c *Converter) StatusToRSSItem(ctx context.Context, s *gtsmodel.Status) (*f
 	var title string
 	if s.ContentWarning != "" {
 		title = trimTo(s.ContentWarning, rssTitleMaxRunes)
-	} else {
+	} else if s.Text != "" {
 		title = trimTo(s.Text, rssTitleMaxRunes)
+	} else {
+		// Remote statuses have no source text.
+		title = trimTo(text.SanitizeToPlaintext(s.Content), rssTitleMaxRunes)
 	}
 
 	// Link -- The URL of the item.
@@ -63,14 +100,22 @@ func (c *Converter) StatusToRSSItem(ctx context.Context, s *gtsmodel.Status) (*f
 		}
 		s.Account = a
 	}
-	authorName := "@" + s.Account.Username + "@" + config.GetAccountDomain()
+	authorDomain := s.Account.Domain
+	if authorDomain == "" {
+		authorDomain = config.GetAccountDomain()
+	}
+	authorName := "@" + s.Account.Username + "@" + authorDomain
 	author := &feeds.Author{
 		Name: authorName,
 	}
 
 	// Source -- The RSS channel that the item came from.
-	source := &feeds.Link{
-		Href: s.Account.URL + "/feed.rss",
+	// Only local accounts are known to have one.
+	var source *feeds.Link
+	if s.Account.IsLocal() {
+		source = &feeds.Link{
+			Href: s.Account.URL + "/feed.rss",
+		}
 	}
 
 	// Description -- The item synopsis.
@@ -167,6 +212,179 @@ func (c *Converter) StatusToRSSItem(ctx context.Context, s *gtsmodel.Status) (*f
 	}, nil
 }
 
+// FeedLinks are the urls of a feed document. First and Next are the
+// RFC 5005 paging links of paged feeds, left empty for unpaged feeds.
+type FeedLinks struct {
+	Self  string // url the document is served at
+	First string // url of the newest page
+	Next  string // url of the following, older page
+}
+
+// FeedToString serializes the feed to format, with the given links.
+// The items of the feed must be the statuses converted by StatusToRSSItem.
+func FeedToString(feed *feeds.Feed, format FeedFormat, links FeedLinks, statuses []*gtsmodel.Status) (string, error) {
+	switch format {
+	case FeedFormatAtom:
+		return feeds.ToXML(atomFeed(feed, links, statuses))
+	case FeedFormatJSON:
+		return jsonFeed(feed, links, statuses).ToJSON()
+	default:
+		return feeds.ToXML(rssFeed(feed, links))
+	}
+}
+
+// rssPagedFeed is an RSS document with atom:link paging links,
+// which the generic RSS channel can't hold.
+type rssPagedFeed struct {
+	XMLName          xml.Name `xml:"rss"`
+	Version          string   `xml:"version,attr"`
+	ContentNamespace string   `xml:"xmlns:content,attr"`
+	AtomNamespace    string   `xml:"xmlns:atom,attr,omitempty"`
+	Channel          *rssPagedChannel
+}
+
+type rssPagedChannel struct {
+	*feeds.RssFeed
+	Links []rssAtomLink
+}
+
+type rssAtomLink struct {
+	XMLName xml.Name `xml:"atom:link"`
+	Href    string   `xml:"href,attr"`
+	Rel     string   `xml:"rel,attr"`
+	Type    string   `xml:"type,attr,omitempty"`
+}
+
+func (r *rssPagedFeed) FeedXml() interface{} {
+	return r
+}
+
+// rssFeed converts the feed to RSS, with its links.
+func rssFeed(feed *feeds.Feed, links FeedLinks) *rssPagedFeed {
+	rss := &rssPagedFeed{
+		Version:          "2.0",
+		ContentNamespace: "http://purl.org/rss/1.0/modules/content/",
+		Channel: &rssPagedChannel{
+			RssFeed: (&feeds.Rss{Feed: feed}).RssFeed(),
+		},
+	}
+
+	for _, link := range pagingLinks(links) {
+		rss.AtomNamespace = "http://www.w3.org/2005/Atom"
+		rss.Channel.Links = append(rss.Channel.Links, rssAtomLink{
+			Href: link.Href,
+			Rel:  link.Rel,
+			Type: link.Type,
+		})
+	}
+
+	return rss
+}
+
+// atomPagedFeed is an Atom document with the
+// paging links the generic Atom feed can't hold.
+type atomPagedFeed struct {
+	*feeds.AtomFeed
+	Links []feeds.AtomLink
+}
+
+func (a *atomPagedFeed) FeedXml() interface{} {
+	return a
+}
+
+// pagingLinks returns the self and RFC 5005 paging links to add to a feed.
+func pagingLinks(links FeedLinks) []feeds.AtomLink {
+	var atomLinks []feeds.AtomLink
+	if links.Self != "" && (links.First != "" || links.Next != "") {
+		atomLinks = append(atomLinks, feeds.AtomLink{Href: links.Self, Rel: "self"})
+	}
+	if links.First != "" {
+		atomLinks = append(atomLinks, feeds.AtomLink{Href: links.First, Rel: "first"})
+	}
+	if links.Next != "" {
+		atomLinks = append(atomLinks, feeds.AtomLink{Href: links.Next, Rel: "next"})
+	}
+	return atomLinks
+}
+
+// atomFeed converts the feed to Atom, adding what the generic
+// feed items can't hold: every media attachment as an enclosure
+// link, content warnings as summary, and publication dates.
+func atomFeed(feed *feeds.Feed, links FeedLinks, statuses []*gtsmodel.Status) *atomPagedFeed {
+	atom := (&feeds.Atom{Feed: feed}).AtomFeed()
+	atom.Link.Rel = "alternate"
+	if feed.Image != nil {
+		atom.Icon = feed.Image.Url
+	}
+
+	for i, entry := range atom.Entries {
+		status := statuses[i]
+
+		entry.Published = status.CreatedAt.Format(time.RFC3339)
+		entry.Updated = statusUpdatedAt(status).Format(time.RFC3339)
+
+		// Replace the single (possibly empty)
+		// enclosure of the generic item.
+		entry.Links = entry.Links[:1]
+		for _, attachment := range status.Attachments {
+			entry.Links = append(entry.Links, feeds.AtomLink{
+				Href:   attachment.URL,
+				Rel:    "enclosure",
+				Type:   attachment.File.ContentType,
+				Length: strconv.Itoa(attachment.File.FileSize),
+			})
+		}
+
+		if status.ContentWarning != "" {
+			entry.Summary = &feeds.AtomSummary{Content: status.ContentWarning, Type: "text"}
+		}
+	}
+
+	return &atomPagedFeed{AtomFeed: atom, Links: pagingLinks(links)}
+}
+
+// jsonFeed converts the feed to JSON Feed, adding what the
+// generic feed items can't hold: every media attachment,
+// content warnings as summary, and modification dates.
+func jsonFeed(feed *feeds.Feed, links FeedLinks, statuses []*gtsmodel.Status) *feeds.JSONFeed {
+	json := (&feeds.JSON{Feed: feed}).JSONFeed()
+	json.FeedUrl = links.Self
+	json.NextUrl = links.Next
+	if feed.Image != nil {
+		json.Icon = feed.Image.Url
+	}
+
+	for i, item := range json.Items {
+		status := statuses[i]
+
+		item.ModifiedDate = util.Ptr(statusUpdatedAt(status))
+
+		for _, attachment := range status.Attachments {
+			item.Attachments = append(item.Attachments, feeds.JSONAttachment{
+				Url:      attachment.URL,
+				MIMEType: attachment.File.ContentType,
+				Title:    attachment.Description,
+				Size:     int32(attachment.File.FileSize),
+			})
+		}
+
+		if status.ContentWarning != "" {
+			item.Summary = status.ContentWarning
+		}
+	}
+
+	return json
+}
+
+// statusUpdatedAt returns when the status was
+// last updated, never before it was created.
+func statusUpdatedAt(status *gtsmodel.Status) time.Time {
+	if status.UpdatedAt.Before(status.CreatedAt) {
+		return status.CreatedAt
+	}
+	return status.UpdatedAt
+}
+
 // trimTo trims the given `in` string to
 // the length `to`, measured in runes.
 //
@@ -22,9 +22,11 @@ import (
 	"encoding/xml"
 	"testing"
 
+	"github.com/gorilla/feeds"
 	"github.com/stretchr/testify/suite"
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
 
@@ -141,6 +143,50 @@ func (suite *InternalToRSSTestSuite) TestStatusToRSSItem3() {
 </Item>`, string(data))
 }
 
+func (suite *InternalToRSSTestSuite) TestStatusToRSSItemRemote() {
+	s := suite.testStatuses["remote_account_1_status_1"]
+	item, err := suite.typeconverter.StatusToRSSItem(context.Background(), s)
+	suite.NoError(err)
+
+	suite.Equal("dark souls status bot: \"thoughts of dog\"", item.Title)
+	suite.Equal("http://fossbros-anonymous.io/@foss_satan/statuses/01FVW7JHQFSFK166WWKR8CBA6M", item.Link.Href)
+	suite.Nil(item.Source)
+	suite.Equal("@foss_satan@fossbros-anonymous.io", item.Author.Name)
+}
+
+func (suite *InternalToRSSTestSuite) TestFeedToStringPaged() {
+	feed := &feeds.Feed{
+		Title:       "Posts from @admin@localhost:8080",
+		Description: "Posts from @admin@localhost:8080",
+		Link:        &feeds.Link{Href: "http://localhost:8080/@admin"},
+	}
+	links := typeutils.FeedLinks{
+		Self:  "http://localhost:8080/@admin/feed.rss?max_id=01F8MHAAY43M6RJ473VQFCVH37",
+		First: "http://localhost:8080/@admin/feed.rss",
+		Next:  "http://localhost:8080/@admin/feed.rss?max_id=01F8MH75CBF9JFX4ZAD54N0W0R",
+	}
+
+	rss, err := typeutils.FeedToString(feed, typeutils.FeedFormatRSS, links, nil)
+	suite.NoError(err)
+	suite.Contains(rss, `xmlns:atom="http://www.w3.org/2005/Atom"`)
+	suite.Contains(rss, `<atom:link href="http://localhost:8080/@admin/feed.rss" rel="first"></atom:link>`)
+	suite.Contains(rss, `<atom:link href="http://localhost:8080/@admin/feed.rss?max_id=01F8MH75CBF9JFX4ZAD54N0W0R" rel="next"></atom:link>`)
+
+	atom, err := typeutils.FeedToString(feed, typeutils.FeedFormatAtom, links, nil)
+	suite.NoError(err)
+	suite.Contains(atom, `<link href="http://localhost:8080/@admin/feed.rss?max_id=01F8MHAAY43M6RJ473VQFCVH37" rel="self"></link>`)
+	suite.Contains(atom, `<link href="http://localhost:8080/@admin/feed.rss?max_id=01F8MH75CBF9JFX4ZAD54N0W0R" rel="next"></link>`)
+
+	json, err := typeutils.FeedToString(feed, typeutils.FeedFormatJSON, links, nil)
+	suite.NoError(err)
+	suite.Contains(json, `"next_url": "http://localhost:8080/@admin/feed.rss?max_id=01F8MH75CBF9JFX4ZAD54N0W0R"`)
+
+	// Unpaged feeds have no paging links.
+	rss, err = typeutils.FeedToString(feed, typeutils.FeedFormatRSS, typeutils.FeedLinks{Self: links.First}, nil)
+	suite.NoError(err)
+	suite.NotContains(rss, "atom:link")
+}
+
 func TestInternalToRSSTestSuite(t *testing.T) {
 	suite.Run(t, new(InternalToRSSTestSuite))
 }
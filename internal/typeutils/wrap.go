@@ -30,8 +30,8 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/uris"
 )
 
-// WrapPersonInUpdate ...
-func (c *Converter) WrapPersonInUpdate(person vocab.ActivityStreamsPerson, originAccount *gtsmodel.Account) (vocab.ActivityStreamsUpdate, error) {
+// WrapAccountableInUpdate wraps the actor of originAccount in a public Update.
+func (c *Converter) WrapAccountableInUpdate(accountable ap.Accountable, originAccount *gtsmodel.Account) (vocab.ActivityStreamsUpdate, error) {
 	update := streams.NewActivityStreamsUpdate()
 
 	// set the actor
@@ -58,9 +58,11 @@ func (c *Converter) WrapPersonInUpdate(person vocab.ActivityStreamsPerson, origi
 	idProp.SetIRI(idURI)
 	update.SetJSONLDId(idProp)
 
-	// set the person as the object here
+	// set the actor as the object here
 	objectProp := streams.NewActivityStreamsObjectProperty()
-	objectProp.AppendActivityStreamsPerson(person)
+	if err := objectProp.AppendType(accountable); err != nil {
+		return nil, gtserror.Newf("error setting object: %w", err)
+	}
 	update.SetActivityStreamsObject(objectProp)
 
 	// to should be public
@@ -102,10 +102,12 @@ func (m *Module) profileGETHandler(c *gin.Context) {
 		return
 	}
 
-	// Only generate RSS link if account has RSS enabled.
-	var rssFeed string
+	// Only generate feed links if account has RSS enabled.
+	var rssFeed, atomFeed, jsonFeed string
 	if targetAccount.EnableRSS {
 		rssFeed = "/@" + targetAccount.Username + "/feed.rss"
+		atomFeed = "/@" + targetAccount.Username + "/feed.atom"
+		jsonFeed = "/@" + targetAccount.Username + "/feed.json"
 	}
 
 	// Only allow search engines / robots to
@@ -177,6 +179,8 @@ func (m *Module) profileGETHandler(c *gin.Context) {
 		Extra: map[string]any{
 			"account":          targetAccount,
 			"rssFeed":          rssFeed,
+			"atomFeed":         atomFeed,
+			"jsonFeed":         jsonFeed,
 			"robotsMeta":       robotsMeta,
 			"statuses":         statusResp.Items,
 			"statuses_next":    statusResp.NextLink,
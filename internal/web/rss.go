@@ -27,12 +27,32 @@ import (
 	apiutil "github.com/superseriousbusiness/gotosocial/internal/api/util"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/processing/account"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 )
 
-const appRSSUTF8 = string(apiutil.AppRSSXML) + "; charset=utf-8"
+const (
+	appRSSUTF8      = string(apiutil.AppRSSXML) + "; charset=utf-8"
+	appAtomUTF8     = string(apiutil.AppAtomXML) + "; charset=utf-8"
+	appFeedJSONUTF8 = string(apiutil.AppFeedJSON) + "; charset=utf-8"
+)
 
 func (m *Module) rssFeedGETHandler(c *gin.Context) {
-	if _, err := apiutil.NegotiateAccept(c, apiutil.AppRSSXML); err != nil {
+	m.feedGETHandler(c, typeutils.FeedFormatRSS, appRSSUTF8, apiutil.AppRSSXML)
+}
+
+func (m *Module) atomFeedGETHandler(c *gin.Context) {
+	m.feedGETHandler(c, typeutils.FeedFormatAtom, appAtomUTF8, apiutil.AppAtomXML)
+}
+
+func (m *Module) jsonFeedGETHandler(c *gin.Context) {
+	m.feedGETHandler(c, typeutils.FeedFormatJSON, appFeedJSONUTF8, apiutil.AppFeedJSON, apiutil.AppJSON)
+}
+
+// feedGETHandler serves the feed of an account in the given format.
+// Every format has its own path, and so its own ETag cache entry.
+func (m *Module) feedGETHandler(c *gin.Context, format typeutils.FeedFormat, contentType string, offers ...string) {
+	if _, err := apiutil.NegotiateAccept(c, offers...); err != nil {
 		apiutil.WebErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
 		return
 	}
@@ -49,19 +69,32 @@ func (m *Module) rssFeedGETHandler(c *gin.Context) {
 	// todo: https://github.com/superseriousbusiness/gotosocial/issues/1813
 	username = strings.ToLower(username)
 
+	opts, errWithCode := parseFeedOptions(c)
+	if errWithCode != nil {
+		apiutil.WebErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
 	// Retrieve the getRSSFeed function from the processor.
 	// We'll only call the function if we need to, to save db calls.
 	// lastPostAt may be a zero time if account has never posted.
-	getRSSFeed, lastPostAt, errWithCode := m.processor.Account().GetRSSFeedForUsername(c.Request.Context(), username)
+	getRSSFeed, lastPostAt, errWithCode := m.processor.Account().GetFeedForUsername(c.Request.Context(), username, format, opts)
 	if errWithCode != nil {
 		apiutil.WebErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
 		return
 	}
 
+	// Every page and filter of the feed has its own
+	// cache entry, keyed by the normalized query.
+	query := opts.Query()
+	if opts.MaxID != "" {
+		query.Set(apiutil.MaxIDKey, opts.MaxID)
+	}
+
 	var (
 		rssFeed string // Stringified rss feed.
 
-		cacheKey              = c.Request.URL.Path
+		cacheKey              = c.Request.URL.Path + "?" + query.Encode()
 		cacheEntry, wasCached = m.eTagCache.Get(cacheKey)
 	)
 
@@ -157,7 +190,36 @@ func (m *Module) rssFeedGETHandler(c *gin.Context) {
 		}
 	}
 
-	c.Data(http.StatusOK, appRSSUTF8, []byte(rssFeed))
+	c.Data(http.StatusOK, contentType, []byte(rssFeed))
+}
+
+// parseFeedOptions parses the paging and filtering query parameters of a feed request.
+func parseFeedOptions(c *gin.Context) (account.FeedOptions, gtserror.WithCode) {
+	var (
+		opts        account.FeedOptions
+		errWithCode gtserror.WithCode
+	)
+
+	opts.MaxID = apiutil.ParseMaxID(c.Query(apiutil.MaxIDKey), "")
+	opts.Tag = c.Query(apiutil.WebFeedTagKey)
+
+	if opts.Replies, errWithCode = apiutil.ParseWebFeedReplies(c.Query(apiutil.WebFeedRepliesKey), false); errWithCode != nil {
+		return opts, errWithCode
+	}
+
+	if opts.Boosts, errWithCode = apiutil.ParseWebFeedBoosts(c.Query(apiutil.WebFeedBoostsKey), false); errWithCode != nil {
+		return opts, errWithCode
+	}
+
+	if opts.MediaOnly, errWithCode = apiutil.ParseWebFeedMediaOnly(c.Query(apiutil.WebFeedMediaOnlyKey), false); errWithCode != nil {
+		return opts, errWithCode
+	}
+
+	if opts.Summary, errWithCode = apiutil.ParseWebFeedSummary(c.Query(apiutil.WebFeedSummaryKey), false); errWithCode != nil {
+		return opts, errWithCode
+	}
+
+	return opts, nil
 }
 
 // unixAfter returns true if the unix value of t1
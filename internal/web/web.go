@@ -42,6 +42,8 @@ const (
 	tagsPath           = "/tags/:" + apiutil.TagNameKey
 	customCSSPath      = profileGroupPath + "/custom.css"
 	rssFeedPath        = profileGroupPath + "/feed.rss"
+	atomFeedPath       = profileGroupPath + "/feed.atom"
+	jsonFeedPath       = profileGroupPath + "/feed.json"
 	assetsPathPrefix   = "/assets"
 	distPathPrefix     = assetsPathPrefix + "/dist"
 	themesPathPrefix   = assetsPathPrefix + "/themes"
@@ -115,6 +117,8 @@ func (m *Module) Route(r *router.Router, mi ...gin.HandlerFunc) {
 	r.AttachHandler(http.MethodGet, settingsPanelGlob, m.SettingsPanelHandler)
 	r.AttachHandler(http.MethodGet, customCSSPath, m.customCSSGETHandler)
 	r.AttachHandler(http.MethodGet, rssFeedPath, m.rssFeedGETHandler)
+	r.AttachHandler(http.MethodGet, atomFeedPath, m.atomFeedGETHandler)
+	r.AttachHandler(http.MethodGet, jsonFeedPath, m.jsonFeedGETHandler)
 	r.AttachHandler(http.MethodGet, confirmEmailPath, m.confirmEmailGETHandler)
 	r.AttachHandler(http.MethodPost, confirmEmailPath, m.confirmEmailPOSTHandler)
 	r.AttachHandler(http.MethodGet, robotsPath, m.robotsGETHandler)
@@ -130,6 +130,30 @@ func testDefaults() config.Configuration {
 		SMTPFrom:               "GoToSocial",
 		SMTPDiscloseRecipients: false,
 
+		RssPollFrequency:                  1,
+		RssBackfillCount:                  20,
+		RssBackfillArchivePages:           2,
+		RssFetchComments:                  false,
+		RssProfileRefreshFrequency:        0,
+		RssFetchAuthorHandles:             false,
+		RssPreviewCards:                   false,
+		RssPreviewCardsLocal:              false,
+		RssDefaultVisibility:              "public",
+		RssDefaultLocalOnly:               false,
+		RssDefaultSensitive:               false,
+		RssDefaultContentWarning:          "",
+		RssDefaultContentWarningFromTitle: false,
+		RssDefaultBoostable:               true,
+		RssDefaultLikeable:                true,
+		RssDefaultReplyable:               true,
+		RssDefaultByline:                  true,
+		RssDefaultRetentionDays:           0,
+		RssDefaultRetentionItems:          0,
+		RssDeduplicate:                    true,
+		RssDeduplicateCanonical:           false,
+		RssNewsletterMaildir:              "",
+		RssNewsletterDomain:               "",
+
 		TracingEnabled:           false,
 		TracingEndpoint:          "localhost:4317",
 		TracingTransport:         "grpc",
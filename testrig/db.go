@@ -69,6 +69,19 @@ var testModels = []interface{}{
 	&gtsmodel.Rule{},
 	&gtsmodel.AccountNote{},
 	&gtsmodel.AccountSettings{},
+	&gtsmodel.RssFeed{},
+	&gtsmodel.RssFeedDelegate{},
+	&gtsmodel.RssFeedClaim{},
+	&gtsmodel.RssNewsletter{},
+	&gtsmodel.FeedToken{},
+	&gtsmodel.AppPassword{},
+	&gtsmodel.RssArticle{},
+	&gtsmodel.RssItem{},
+	&gtsmodel.RssWebhook{},
+	&gtsmodel.RssWebhookDelivery{},
+	&gtsmodel.RssCommentFeed{},
+	&gtsmodel.RssCursor{},
+	&gtsmodel.PreviewCard{},
 }
 
 // NewTestDB returns a new initialized, empty database for testing.